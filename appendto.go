@@ -0,0 +1,77 @@
+package async
+
+import "context"
+
+// AppendOption configures TaskAppend.
+type AppendOption func(*appendConfig)
+
+type appendConfig struct {
+	ordered bool
+}
+
+// InRegistrationOrder makes TaskAppend keep results in the order the tasks
+// were added rather than the order they complete. A slot is reserved in the
+// slice when the task is added, so tasks that fail leave a zero value behind.
+func InRegistrationOrder() AppendOption {
+	return func(c *appendConfig) {
+		c.ordered = true
+	}
+}
+
+// TaskAppend adds fn to the batch and appends its result to the slice dest
+// points to, so many homogeneous results can be collected without managing
+// indexes. Results are appended in completion order unless InRegistrationOrder
+// is given, and go through the same checks as Bind: Strict, WithCopyResults,
+// WithAssigner and StubResult apply. The slice must not be modified elsewhere
+// while the batch runs.
+func TaskAppend[T any](a Async, dest *[]T, fn func(ctx context.Context) (T, error), opts ...AppendOption) Async {
+	if fn == nil {
+		return a.Task(nil)
+	}
+	var cfg appendConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.ordered {
+		var i int
+		a.Task(storesResult(func(ctx context.Context) error {
+			s := bindStateFrom(ctx)
+			res, err := result(ctx, fn)
+			if err != nil {
+				return err
+			}
+			// Each task owns its slot, so no locking is needed
+			return store(s, &(*dest)[i], res)
+		}))
+		// Reserved once the batch accepted the task, since a batch failing
+		// to build runs no task and would leave a zero value behind
+		if a.Err() == nil {
+			var zero T
+			*dest = append(*dest, zero)
+			i = len(*dest) - 1
+		}
+		return a
+	}
+
+	return a.Task(storesResult(func(ctx context.Context) error {
+		s := bindStateFrom(ctx)
+		res, err := result(ctx, fn)
+		if err != nil {
+			return err
+		}
+		var v T
+		if err := store(s, &v, res); err != nil {
+			return err
+		}
+		if s == nil {
+			*dest = append(*dest, v)
+			return nil
+		}
+		// Tasks of a phase share the bind state, which serializes the appends
+		s.mu.Lock()
+		*dest = append(*dest, v)
+		s.mu.Unlock()
+		return nil
+	}))
+}
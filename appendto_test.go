@@ -0,0 +1,90 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTaskAppend(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var results []int
+	a := runner.RunInAsync()
+	for i := 0; i < 50; i++ {
+		TaskAppend(a, &results, func(ctx context.Context) (int, error) {
+			return i, nil
+		})
+	}
+	if err := a.Go(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	slices.Sort(results)
+	if len(results) != 50 || results[0] != 0 || results[49] != 49 {
+		t.Errorf("Expected all 50 results, got %v", results)
+	}
+}
+
+func TestTaskAppendInRegistrationOrder(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	results := []string{"existing"}
+	a := runner.RunInAsync()
+	for i, name := range []string{"slow", "medium", "fast"} {
+		TaskAppend(a, &results, func(ctx context.Context) (string, error) {
+			time.Sleep(time.Duration(3-i) * 5 * time.Millisecond)
+			return name, nil
+		}, InRegistrationOrder())
+	}
+	if err := a.Go(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := []string{"existing", "slow", "medium", "fast"}
+	if !slices.Equal(results, want) {
+		t.Errorf("Expected %v, got %v", want, results)
+	}
+
+	err := TaskAppend[int](runner.RunInAsync(), new([]int), nil).Go(context.Background())
+	if !errors.Is(err, ErrNilTask) {
+		t.Errorf("Expected ErrNilTask, got %v", err)
+	}
+}
+
+func TestTaskAppendResultPath(t *testing.T) {
+	var ptrs []*int
+	err := TaskAppend(NewAsyncRunner().RunInAsync().Strict(), &ptrs, func(ctx context.Context) (*int, error) {
+		return nil, nil
+	}).Go(context.Background())
+	if !errors.Is(err, ErrNilResult) || len(ptrs) != 0 {
+		t.Errorf("Expected ErrNilResult in strict mode, got %v, %v", ptrs, err)
+	}
+
+	stub := func(info TaskInfo, next AsyncFunc) AsyncFunc {
+		return func(ctx context.Context) error {
+			return next(StubResult(ctx, 42))
+		}
+	}
+	var stubbed []int
+	err = TaskAppend(NewAsyncRunner(WithMiddleware(stub)).RunInAsync(), &stubbed, func(ctx context.Context) (int, error) {
+		return 1, nil
+	}, InRegistrationOrder()).Go(context.Background())
+	if err != nil || !slices.Equal(stubbed, []int{42}) {
+		t.Errorf("Expected the stubbed result, got %v, %v", stubbed, err)
+	}
+
+	// A task rejected by the batch reserves no slot
+	var results []int
+	a := NewAsyncRunner().RunInAsync().WithMaxTasks(1)
+	for i := range 2 {
+		TaskAppend(a, &results, func(ctx context.Context) (int, error) {
+			return i, nil
+		}, InRegistrationOrder())
+	}
+	if !errors.Is(a.Err(), ErrTooManyTasks) || !slices.Equal(results, []int{0}) {
+		t.Errorf("Expected a single slot for the accepted task, got %v, %v", results, a.Err())
+	}
+}
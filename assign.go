@@ -0,0 +1,26 @@
+package async
+
+// Assigner stores a task result into its destination. dest is a pointer to the
+// destination, such as the pointer given to Bind or a struct field filled by
+// Populate, and res is the result of the task's function. Custom assigners
+// allow conversions through mapstructure, protobuf or custom copiers.
+type Assigner interface {
+	Assign(dest, res any) error
+}
+
+// AssignerFunc adapts a function to an Assigner.
+type AssignerFunc func(dest, res any) error
+
+// Assign calls f(dest, res).
+func (f AssignerFunc) Assign(dest, res any) error {
+	return f(dest, res)
+}
+
+// WithAssigner makes the batch store results through assigner.
+func (a *async) WithAssigner(assigner Assigner) Async {
+	a.build.Lock()
+	defer a.build.Unlock()
+
+	a.assigner = assigner
+	return a
+}
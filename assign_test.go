@@ -0,0 +1,69 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+// atoiAssigner converts string results into int destinations.
+var atoiAssigner = AssignerFunc(func(dest, res any) error {
+	switch d := dest.(type) {
+	case *int:
+		n, err := strconv.Atoi(res.(string))
+		if err != nil {
+			return err
+		}
+		*d = n
+		return nil
+	case *string:
+		*d = res.(string)
+		return nil
+	}
+	return errors.New("unsupported destination")
+})
+
+func TestWithAssigner(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var view struct {
+		Count int
+		Name  string
+	}
+	var name string
+	err := Populate(runner.RunInAsync().WithAssigner(atoiAssigner), &view, map[string]ValueFunc{
+		"Count": func(ctx context.Context) (any, error) {
+			return "42", nil
+		},
+		"Name": func(ctx context.Context) (any, error) {
+			return "alice", nil
+		},
+	}).
+		Task(Bind(&name, func(ctx context.Context) (string, error) {
+			return "bob", nil
+		})).
+		Go(context.Background())
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if view.Count != 42 || view.Name != "alice" || name != "bob" {
+		t.Errorf("Expected results stored by the assigner, got %+v and %q", view, name)
+	}
+}
+
+func TestWithAssignerError(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var n float64
+	err := runner.RunInAsync().
+		WithAssigner(atoiAssigner).
+		Task(Bind(&n, func(ctx context.Context) (float64, error) {
+			return 1, nil
+		})).
+		Go(context.Background())
+	if err == nil || err.Error() != "unsupported destination" {
+		t.Errorf("Expected the assigner error, got %v", err)
+	}
+}
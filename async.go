@@ -2,111 +2,879 @@ package async
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand/v2"
+	"runtime/debug"
+	"slices"
+	"sync"
 	"time"
+)
+
+// ErrNilTask is returned by Go when a nil function was added to the batch.
+var ErrNilTask = errors.New("async: nil task function")
 
-	"golang.org/x/sync/errgroup"
+var (
+	// ErrBatchAlreadyRun is returned by Go when the batch was already executed.
+	// A batch runs once; Reset it or create a new one with RunInAsync to run again.
+	ErrBatchAlreadyRun = errors.New("async: batch already run")
+	// ErrBatchSealed is reported by Err when tasks were added after Go was called.
+	ErrBatchSealed = errors.New("async: task added after batch started")
 )
 
+// ErrTooManyTasks is returned when a batch exceeds the limit set with
+// WithMaxTasks, which usually means tasks are being registered in a runaway loop.
+var ErrTooManyTasks = errors.New("async: too many tasks")
+
+// ErrUnknownPool is returned by Go when a task was routed to a pool name that
+// was not registered on the runner.
+var ErrUnknownPool = errors.New("async: unknown pool")
+
+// ErrUnsupportedPool is returned by Go when a task was routed to a registered
+// pool that was not created by NewWorkerPool, since TaskOn relies on its
+// internals to hand tasks over and account for abandoned ones.
+var ErrUnsupportedPool = errors.New("async: pool was not created by NewWorkerPool")
+
 // AsyncFunc represents a function that can be executed concurrently.
 // It receives a context to handle graceful cancellations.
 type AsyncFunc func(ctx context.Context) error
 
 // Async defines the contract for building and executing a batch of async operations.
 type Async interface {
+	BatchBuilder[Async]
+	BatchExecutor
+
+	// Reset clears the outcome of the last run, keeping the configuration and
+	// tasks, so the same batch can run again. It must not be called while Go runs.
+	Reset() Async
+	// Release hands the batch back for reuse by a later RunInAsync. The batch
+	// must not be used afterwards, and must not be released while Go runs.
+	Release()
+}
+
+// BatchBuilder is the part of a batch registering and configuring tasks, for
+// code that only assembles a batch run by its caller. Its methods return B,
+// the batch itself, so calls chain: Async is a BatchBuilder[Async], and code
+// generic over B, such as func[B BatchBuilder[B]](b B) B, accepts both a
+// batch and a builder mock.
+type BatchBuilder[B any] interface {
 	// Task adds a function to the execution queue.
-	Task(fn AsyncFunc) Async
+	Task(fn AsyncFunc) B
+	// TaskNamed adds a function under name, which identifies the task in its
+	// report and in the errors of strict batches.
+	TaskNamed(name string, fn AsyncFunc) B
+	// TaskIf adds a function that only runs if pred reports true when the task is
+	// about to start; otherwise it is recorded as skipped.
+	TaskIf(pred func(ctx context.Context) bool, fn AsyncFunc) B
+	// TaskOn adds a function executed by the worker pool registered on the
+	// runner under name.
+	TaskOn(name string, fn AsyncFunc) B
+	// TaskDelayed adds a function that starts only after the given delay has elapsed.
+	TaskDelayed(delay time.Duration, fn AsyncFunc, opts ...ScheduleOption) B
+	// TaskJSON adds a function returning a JSON document, such as a raw HTTP
+	// response body, which is decoded into dest on the task's goroutine.
+	TaskJSON(dest any, fn func(ctx context.Context) ([]byte, error)) B
+	// TaskWithTimeout adds a function whose context expires after timeout,
+	// independently of the other tasks.
+	TaskWithTimeout(timeout time.Duration, fn AsyncFunc) B
 	// WithTimeout sets a maximum duration for the entire batch to complete.
-	WithTimeout(timeout time.Duration) Async
+	WithTimeout(timeout time.Duration) B
+	// WithConcurrency limits how many tasks may run at the same time. Zero means no limit.
+	WithConcurrency(n int) B
+	// WithMaxTasks limits the number of tasks of the batch, spawned ones
+	// included. Zero means no limit.
+	WithMaxTasks(n int) B
+	// Critical spares the batch when the runner starts shutting down: instead
+	// of being cancelled right away with best-effort batches, it keeps running
+	// until the context of Shutdown is done.
+	Critical() B
+	// WithQuorum makes the batch succeed as soon as n tasks have succeeded,
+	// cancelling the others. Failures only fail the batch once fewer than n
+	// tasks can still succeed. Zero disables the quorum.
+	WithQuorum(n int) B
+	// AllowSharedDest disables the check failing tasks that Bind the same
+	// destination, for callers who synchronize those writes themselves.
+	AllowSharedDest() B
+	// WithWatchdog calls onStall with a report of the batch, including goroutine
+	// stacks, whenever no task has finished for d while the batch is running.
+	WithWatchdog(d time.Duration, onStall func(Report)) B
+	// WithCopyResults makes Bind store deep copies of results, for callers who
+	// mutate results that the producing function may still share.
+	WithCopyResults() B
+	// Strict makes Bind fail with ErrNilResult when a function returns a nil
+	// pointer, slice, map, channel, function or interface without an error.
+	Strict() B
+	// WithAssigner makes Bind and Populate store results through assigner
+	// instead of assigning them directly.
+	WithAssigner(assigner Assigner) B
+	// Phase starts a new phase: tasks added afterwards only start once every
+	// task added before has finished.
+	Phase() B
+}
+
+// BatchExecutor is the part of Async running a batch and reporting on it, for
+// code that runs a batch assembled elsewhere.
+type BatchExecutor interface {
 	// Go executes all queued tasks and waits for completion or the first error.
+	// A batch can only be run once.
 	Go(ctx context.Context) error
+	// GoSettled executes all queued tasks like Go, except that failures do not
+	// cancel the other tasks nor the later phases: every task is attempted and
+	// GoSettled waits for all of them, returning the report of each. The error
+	// is only set when the batch could not run at all, as with Go.
+	GoSettled(ctx context.Context) ([]TaskReport, error)
+	// Report returns the per-task outcome of the last execution.
+	Report() Report
+	// Err returns the first error found while building the batch, which Go
+	// returns without running any task.
+	Err() error
 }
 
 // AsyncRunner provides a factory method to create new async operation batches.
 type AsyncRunner interface {
 	RunInAsync() Async
+	// RunInAsyncN initializes a batch with room for n tasks, avoiding repeated
+	// growth of its task list when building large batches.
+	RunInAsyncN(n int) Async
+	// Every runs fn repeatedly at the given interval until the returned task is
+	// stopped or ctx is done.
+	Every(ctx context.Context, interval time.Duration, fn AsyncFunc, opts ...ScheduleOption) ScheduledTask
+	// At runs fn once at the given time unless the returned task is stopped first.
+	At(ctx context.Context, t time.Time, fn AsyncFunc, opts ...ScheduleOption) ScheduledTask
+	// Supervise keeps fn running in the background, restarting it according
+	// to policy, until the returned task is stopped or ctx is done.
+	Supervise(ctx context.Context, fn AsyncFunc, policy RestartPolicy, opts ...ScheduleOption) SupervisedTask
+	// Pool returns the worker pool registered under name with WithPool, or nil.
+	Pool(name string) WorkerPool
+	// OnIdle registers fn to be called every time the runner has had no
+	// running batch for d. It returns ErrShuttingDown once Shutdown has begun.
+	OnIdle(d time.Duration, fn func()) error
+	// OnShutdown registers fn to run during Shutdown, once the pools are shut
+	// down. Hooks run phase by phase in ascending order, those of a phase
+	// concurrently. It returns ErrShuttingDown once Shutdown has begun.
+	OnShutdown(phase int, fn func(ctx context.Context) error) error
+	// Shutdown cancels the running batches and scheduled tasks of the runner,
+	// waits for them to return, shuts down the pools registered with
+	// WithPool, then runs the OnShutdown hooks, until ctx is done. Batches
+	// started afterwards are handled according to WithDrainPolicy. Later
+	// calls wait for the first one and return its result.
+	Shutdown(ctx context.Context) error
+	// ShuttingDown returns a channel closed as soon as Shutdown starts,
+	// including when it is started by WithSignalHandling.
+	ShuttingDown() <-chan struct{}
+	// Health returns a snapshot of the runner's pools and background tasks.
+	Health() Health
+	// CacheStats returns how the runner's TaskCached tasks were served so far.
+	CacheStats() CacheStats
+	// DedupStats returns the calls the runner's keyed tasks saved so far.
+	DedupStats() DedupStats
 }
 
-type asyncRunner struct{}
+type asyncRunner struct {
+	cfg runnerConfig
+	// delays backs delayed and scheduled tasks with a single shared timer
+	delays *delayQueue
+	drain  *drain
+	// cache backs TaskCached, if the runner has a cache
+	cache *resultCache
+	// idempotency backs TaskIdempotent, if the runner has a store
+	idempotency *idempotency
+	// flights backs TaskCoalesced
+	flights *flightGroup[string]
+}
+
+// NewAsyncRunner creates a new instance of AsyncRunner. Options set the
+// defaults inherited by every batch the runner creates.
+func NewAsyncRunner(opts ...RunnerOption) AsyncRunner {
+	return newAsyncRunner(opts)
+}
 
-// NewAsyncRunner creates a new instance of AsyncRunner.
-func NewAsyncRunner() AsyncRunner {
-	return &asyncRunner{}
+func newAsyncRunner(opts []RunnerOption) *asyncRunner {
+	a := &asyncRunner{}
+	for _, opt := range opts {
+		opt(&a.cfg)
+	}
+	if a.cfg.clock == nil {
+		a.cfg.clock = realClock{}
+	}
+	a.delays = newDelayQueue(a.cfg.clock)
+	a.drain = newDrain(a.delays)
+	if a.cfg.cache != nil {
+		if c, ok := a.cfg.cache.(clockedCache); ok {
+			a.cfg.cache = c.withClock(a.cfg.clock)
+		}
+		a.cache = newResultCache(a.cfg.cache, a.cfg.clock, a.cfg.onPanic, newFlightGroup[string](a.cfg.coalesce, a.delays))
+	}
+	if a.cfg.idempotencyStore != nil {
+		a.idempotency = &idempotency{store: a.cfg.idempotencyStore, flights: newFlightGroup[string](a.cfg.coalesce, a.delays)}
+	}
+	a.flights = newFlightGroup[string](a.cfg.coalesce, a.delays)
+	if len(a.cfg.signals) > 0 {
+		a.handleSignals(a.cfg.signals)
+	}
+	return a
 }
 
 // RunInAsync initializes a new batch of async operations.
 func (a *asyncRunner) RunInAsync() Async {
-	return &async{
-		funcs: make([]AsyncFunc, 0),
+	return a.newBatch(nil)
+}
+
+// RunInAsyncN initializes a new batch preallocated for n tasks.
+func (a *asyncRunner) RunInAsyncN(n int) Async {
+	b := a.newBatch(nil)
+	b.tasks = slices.Grow(b.tasks, n)
+	return b
+}
+
+// newBatch creates a batch inheriting the runner defaults, executed by pool if set.
+// Batches come from batchPool, so services that Release them after every
+// request avoid allocating a new batch each time.
+func (a *asyncRunner) newBatch(pool *workerPool) *async {
+	b := batchPool.Get().(*async)
+	b.runner = a
+	b.pool = pool
+	b.released = false
+	b.concurrency = a.cfg.concurrency
+	b.maxTasks = a.cfg.maxTasks
+	if a.cfg.timeout > 0 {
+		timeout := a.cfg.timeout
+		b.timeout = &timeout
 	}
+	return b
+}
+
+// Pool returns the worker pool registered under name, or nil if there is none.
+func (a *asyncRunner) Pool(name string) WorkerPool {
+	return a.cfg.pools[name]
 }
 
 // Bind is a generic helper that bridges a function's result to a destination pointer.
 // It ensures type safety at compile-time without the overhead of reflection.
+// A nil fn yields a nil AsyncFunc, which the batch rejects.
+//
+// The result is stored as returned, so a nil result sets a pointer, slice or
+// map destination to nil; strict batches fail with ErrNilResult instead.
 func Bind[T any](dest *T, fn func(ctx context.Context) (T, error)) AsyncFunc {
-	return func(ctx context.Context) error {
-		res, err := fn(ctx)
+	if fn == nil {
+		return nil
+	}
+	return storesResult(func(ctx context.Context) error {
+		s := bindStateFrom(ctx)
+		if dest != nil {
+			if err := s.claim(dest); err != nil {
+				return err
+			}
+		}
+		res, err := result(ctx, fn)
 		if err != nil {
 			return err
 		}
 		if dest != nil {
-			*dest = res
+			return store(s, dest, res)
 		}
 		return nil
+	})
+}
+
+// BindPtr is like Bind for optional results: on success *dest is set to a
+// newly allocated copy of the result, while a skipped or failed task leaves
+// it nil. Functions already returning *T can use Bind directly.
+func BindPtr[T any](dest **T, fn func(ctx context.Context) (T, error)) AsyncFunc {
+	if fn == nil {
+		return nil
 	}
+	return storesResult(func(ctx context.Context) error {
+		s := bindStateFrom(ctx)
+		if dest != nil {
+			if err := s.claim(dest); err != nil {
+				return err
+			}
+		}
+		res, err := result(ctx, fn)
+		if err != nil {
+			return err
+		}
+		if dest != nil {
+			return store(s, dest, &res)
+		}
+		return nil
+	})
+}
+
+// BindFunc is like Bind, but hands the result to sink instead of storing it,
+// for results consumed by a sink such as a channel or an aggregator. sink is
+// only called when fn succeeds, and may be called from several tasks at once.
+func BindFunc[T any](sink func(T), fn func(ctx context.Context) (T, error)) AsyncFunc {
+	if fn == nil {
+		return nil
+	}
+	return storesResult(func(ctx context.Context) error {
+		res, err := result(ctx, fn)
+		if err != nil {
+			return err
+		}
+		s := bindStateFrom(ctx)
+		if s != nil && s.strict && isNil(res) {
+			return detailed(ErrNilResult, "%T", res)
+		}
+		if s != nil && s.copy {
+			res = clone(res)
+		}
+		if sink != nil {
+			sink(res)
+		}
+		return nil
+	})
 }
 
 // async implements the Async interface and manages the state of the task batch.
 type async struct {
-	funcs   []AsyncFunc
-	timeout *time.Duration
+	runner *asyncRunner
+	pool   *workerPool
+
+	// build guards the configuration below, so tasks may be added concurrently
+	build       sync.Mutex
+	tasks       []task
+	phase       int
+	timeout     *time.Duration
+	concurrency int
+	maxTasks    int
+	quorum      int
+	critical    bool
+	sharedDest  bool
+	copyResults bool
+	strict      bool
+	assigner    Assigner
+	watchdog    *watchdog
+	// err is the first error found while building the batch
+	err error
+	// started is set once Go was called, sealing the batch
+	started bool
+	// released is set while the batch sits in batchPool
+	released bool
+
+	mu      sync.Mutex
+	reports []TaskReport
+	// progress is when a task last finished, or when the run started
+	progress time.Time
+	// spawned counts the tasks spawned during the run
+	spawned int
+	// tally counts the outcomes of a run with a quorum
+	tally *quorum
+	// settled is set while GoSettled runs, keeping failures from cancelling tasks
+	settled bool
+}
+
+// batchPlan is the configuration of a batch captured when Go starts.
+type batchPlan struct {
+	tasks       []task
+	timeout     *time.Duration
+	concurrency int
+	maxTasks    int
+	quorum      int
+	critical    bool
+	sharedDest  bool
+	copyResults bool
+	strict      bool
+	assigner    Assigner
+	watchdog    *watchdog
+}
+
+// task is a single queued function and the condition guarding it.
+type task struct {
+	fn    AsyncFunc
+	cond  func(ctx context.Context) bool
+	phase int
+	// pool names the runner pool executing the task, if any
+	pool string
+	// name identifies the task in reports and errors, if set
+	name string
 }
 
 // Task appends a function to the execution list.
 func (a *async) Task(fn AsyncFunc) Async {
-	a.funcs = append(a.funcs, fn)
+	return a.add(task{fn: fn})
+}
+
+// TaskNamed appends a function identified by name.
+func (a *async) TaskNamed(name string, fn AsyncFunc) Async {
+	return a.add(task{fn: fn, name: name})
+}
+
+// TaskIf appends a function guarded by pred, which is evaluated right before the task starts.
+func (a *async) TaskIf(pred func(ctx context.Context) bool, fn AsyncFunc) Async {
+	return a.add(task{fn: fn, cond: pred})
+}
+
+// TaskOn appends a function executed by the runner pool registered under name.
+func (a *async) TaskOn(name string, fn AsyncFunc) Async {
+	return a.add(task{fn: fn, pool: name})
+}
+
+// add appends t to the current phase, recording a build error if it has no function.
+func (a *async) add(t task) Async {
+	a.build.Lock()
+	defer a.build.Unlock()
+
+	if a.started {
+		// The task would never run, so surface the misuse through Err
+		if a.err == nil {
+			a.err = ErrBatchSealed
+		}
+		return a
+	}
+	if a.maxTasks > 0 && len(a.tasks) >= a.maxTasks {
+		// Stop growing, so a runaway loop cannot exhaust memory either
+		if a.err == nil {
+			a.err = fmt.Errorf("%w: limit is %d", ErrTooManyTasks, a.maxTasks)
+		}
+		return a
+	}
+	if t.fn == nil && a.err == nil {
+		a.err = fmt.Errorf("%w at index %d", ErrNilTask, len(a.tasks))
+	}
+	t.phase = a.phase
+	a.tasks = append(a.tasks, t)
 	return a
 }
 
+// Err returns the first error found while building the batch.
+func (a *async) Err() error {
+	a.build.Lock()
+	defer a.build.Unlock()
+	return a.err
+}
+
+// Phase closes the current phase. Empty phases are ignored.
+func (a *async) Phase() Async {
+	a.build.Lock()
+	defer a.build.Unlock()
+
+	if len(a.tasks) > 0 && a.tasks[len(a.tasks)-1].phase == a.phase {
+		a.phase++
+	}
+	return a
+}
+
+// TaskDelayed appends a function that waits for delay before running.
+// The wait is aborted as soon as the batch context is done.
+func (a *async) TaskDelayed(delay time.Duration, fn AsyncFunc, opts ...ScheduleOption) Async {
+	if fn == nil {
+		return a.Task(nil)
+	}
+	return a.Task(delayed(a.runner.delays, delay, fn, opts))
+}
+
 // WithTimeout applies an optional timeout to the operation context.
 func (a *async) WithTimeout(timeout time.Duration) Async {
+	a.build.Lock()
+	defer a.build.Unlock()
+
 	a.timeout = &timeout
 	return a
 }
 
-// Go executes all tasks concurrently using an errgroup.
+// WithConcurrency limits the number of tasks running at the same time.
+func (a *async) WithConcurrency(n int) Async {
+	a.build.Lock()
+	defer a.build.Unlock()
+
+	a.concurrency = n
+	return a
+}
+
+// WithMaxTasks limits the number of tasks of the batch.
+func (a *async) WithMaxTasks(n int) Async {
+	a.build.Lock()
+	defer a.build.Unlock()
+
+	a.maxTasks = n
+	return a
+}
+
+// WithQuorum makes the batch succeed once n tasks have succeeded.
+func (a *async) WithQuorum(n int) Async {
+	a.build.Lock()
+	defer a.build.Unlock()
+
+	a.quorum = n
+	return a
+}
+
+// Critical keeps the batch running during Shutdown until its context is done.
+func (a *async) Critical() Async {
+	a.build.Lock()
+	defer a.build.Unlock()
+
+	a.critical = true
+	return a
+}
+
+// AllowSharedDest lets several tasks Bind the same destination.
+func (a *async) AllowSharedDest() Async {
+	a.build.Lock()
+	defer a.build.Unlock()
+
+	a.sharedDest = true
+	return a
+}
+
+// WithCopyResults makes Bind deep-copy results before storing them.
+func (a *async) WithCopyResults() Async {
+	a.build.Lock()
+	defer a.build.Unlock()
+
+	a.copyResults = true
+	return a
+}
+
+// Strict makes Bind reject nil results.
+func (a *async) Strict() Async {
+	a.build.Lock()
+	defer a.build.Unlock()
+
+	a.strict = true
+	return a
+}
+
+// Go executes all tasks concurrently, one phase after another.
 func (a *async) Go(ctx context.Context) error {
+	return a.execute(ctx, false)
+}
+
+// GoSettled executes all tasks, whatever their failures, and returns their reports.
+func (a *async) GoSettled(ctx context.Context) ([]TaskReport, error) {
+	if err := a.execute(ctx, true); err != nil {
+		return nil, err
+	}
+	return a.Report().Tasks, nil
+}
+
+// execute runs the batch. Settled runs record failures without returning
+// them to the groups, so no task is cancelled because another failed.
+func (a *async) execute(ctx context.Context, settled bool) error {
+	p, err := a.plan()
+	if err != nil {
+		return err
+	}
+	if a.pool != nil {
+		if err := a.pool.checkSelfWait(ctx); err != nil {
+			return err
+		}
+	}
+
+	// Lets Shutdown wait for the batch; each phase hands it its cancellation
+	if err := a.runner.drain.addBatch(a, a.runner.cfg.drainPolicy, p.critical); err != nil {
+		return err
+	}
+	defer a.runner.drain.removeBatch(a)
+
 	// Apply timeout if specified to prevent goroutine leaks
-	if a.timeout != nil {
+	if p.timeout != nil {
 		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, *a.timeout)
+		if _, ok := a.runner.cfg.clock.(realClock); ok {
+			ctx, cancel = context.WithTimeout(ctx, *p.timeout)
+		} else {
+			ctx, cancel = withDeadline(ctx, a.runner.delays, *p.timeout)
+		}
 		defer cancel()
 	}
 
-	// Use errgroup for concurrency management and error propagation
-	g, ctx := errgroup.WithContext(ctx)
-
-	for _, fn := range a.funcs {
-		// Re-bind the function variable to avoid closure capture issues in loops
-		f := fn
-		g.Go(func() (err error) {
-			// Panic Recovery: Prevents the entire application from crashing on unexpected errors
-			defer func() {
-				if r := recover(); r != nil {
-					err = fmt.Errorf("async task panicked: %v", r)
-				}
-			}()
-
-			// Pre-check if context is already cancelled before execution
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			default:
-				return f(ctx)
+	a.mu.Lock()
+	// Reuses the capacity left by a released batch
+	a.reports = append(a.reports[:0], make([]TaskReport, len(p.tasks))...)
+	for i := range a.reports {
+		a.reports[i].Index = i
+		a.reports[i].Name = p.tasks[i].name
+	}
+	a.progress = a.runner.cfg.clock.Now()
+	a.tally = nil
+	a.settled = settled
+	if p.quorum > 0 && !settled {
+		a.tally = &quorum{need: p.quorum, total: len(p.tasks)}
+	}
+	a.mu.Unlock()
+
+	if p.watchdog != nil {
+		stop := a.watch(p.watchdog)
+		defer stop()
+	}
+
+	// Tasks are appended in phase order, so every phase is a contiguous range
+	for start := 0; start < len(p.tasks); {
+		end := start
+		for end < len(p.tasks) && p.tasks[end].phase == p.tasks[start].phase {
+			end++
+		}
+
+		if err := a.runPhase(ctx, p, start, end); err != nil {
+			a.cancelFrom(start)
+			if err == errQuorum {
+				return nil
 			}
+			return err
+		}
+		start = end
+	}
+	if a.tally != nil {
+		return a.tally.err()
+	}
+	return nil
+}
+
+// plan seals the batch and captures its configuration, or returns the error
+// that prevents running it.
+func (a *async) plan() (batchPlan, error) {
+	a.build.Lock()
+	defer a.build.Unlock()
+
+	if a.started {
+		return batchPlan{}, ErrBatchAlreadyRun
+	}
+	a.started = true
+
+	if a.err != nil {
+		return batchPlan{}, a.err
+	}
+	if a.maxTasks > 0 && len(a.tasks) > a.maxTasks {
+		return batchPlan{}, fmt.Errorf("%w: limit is %d", ErrTooManyTasks, a.maxTasks)
+	}
+	for _, t := range a.tasks {
+		if t.pool == "" {
+			continue
+		}
+		switch a.runner.Pool(t.pool).(type) {
+		case nil:
+			return batchPlan{}, fmt.Errorf("%w: %q", ErrUnknownPool, t.pool)
+		case *workerPool:
+		default:
+			return batchPlan{}, fmt.Errorf("%w: %q", ErrUnsupportedPool, t.pool)
+		}
+	}
+
+	return batchPlan{
+		tasks:       a.tasks,
+		timeout:     a.timeout,
+		concurrency: a.concurrency,
+		maxTasks:    a.maxTasks,
+		quorum:      a.quorum,
+		critical:    a.critical,
+		sharedDest:  a.sharedDest,
+		copyResults: a.copyResults,
+		strict:      a.strict,
+		assigner:    a.assigner,
+		watchdog:    a.watchdog,
+	}, nil
+}
+
+// runPhase executes p.tasks[start:end] concurrently, along with any task they spawn.
+func (a *async) runPhase(ctx context.Context, p batchPlan, start, end int) error {
+	// The group manages concurrency and error propagation, running tasks on
+	// the pool's workers when the batch was created from a WorkerPool
+	var exec executor
+	if a.pool != nil {
+		exec = a.pool
+	}
+	// Synchronous runners queue every task, spawned ones included, and run
+	// them in order on the calling goroutine
+	var seq *sequential
+	if a.runner.cfg.synchronous {
+		seq = new(sequential)
+		if a.runner.cfg.seeded {
+			seq.rng = rand.New(rand.NewPCG(a.runner.cfg.seed, uint64(start)))
+		}
+		exec = seq
+	}
+	g, ctx := newGroup(ctx, exec, p.concurrency)
+	a.runner.drain.setCancel(a, g.cancel)
+	if a.pool != nil {
+		ctx = onPool(ctx, a.pool)
+	}
+
+	// Bind tasks claim their destination, catching writes that would race.
+	// Phases run one after another, so each phase has its own claims
+	ctx = context.WithValue(ctx, bindKey{}, &bindState{
+		shared: p.sharedDest,
+		copy:   p.copyResults,
+		strict: p.strict,
+		assign: p.assigner,
+		runner: a.runner,
+	})
+
+	s, ctx := newGroupSpawner(ctx, g, func(ctx context.Context, fn AsyncFunc) error {
+		return a.run(ctx, a.addReport(), task{fn: fn})
+	})
+	if p.maxTasks > 0 {
+		s.admit = func() error {
+			return a.admitSpawn(len(p.tasks), p.maxTasks)
+		}
+	}
+
+	for i := start; i < end; i++ {
+		t := p.tasks[i]
+		if seq != nil {
+			g.Go(func() error {
+				return a.run(ctx, i, t)
+			})
+			continue
+		}
+		if t.pool != "" {
+			pool := a.runner.Pool(t.pool).(*workerPool)
+			g.GoOn(pool, func() error {
+				return a.run(onPool(ctx, pool), i, t)
+			})
+			continue
+		}
+		if end-start == 1 && a.pool == nil {
+			// A lone task runs on the calling goroutine, which would only wait
+			// for it anyway; tasks it spawns still get their own goroutines
+			g.GoOn(inline{}, func() error {
+				return a.run(ctx, i, t)
+			})
+			continue
+		}
+		g.Go(func() error {
+			return a.run(ctx, i, t)
 		})
 	}
 
+	if seq != nil {
+		seq.drain()
+	}
 	// Wait for all tasks to finish or return the first error encountered
-	return g.Wait()
+	return s.wait()
+}
+
+// admitSpawn counts a spawned task, failing once the batch would exceed limit tasks.
+func (a *async) admitSpawn(registered, limit int) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if registered+a.spawned >= limit {
+		return detailed(ErrTooManyTasks, "limit is %d", limit)
+	}
+	a.spawned++
+	return nil
+}
+
+// addReport reserves a report entry for a spawned task and returns its index.
+func (a *async) addReport() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	i := len(a.reports)
+	a.reports = append(a.reports, TaskReport{Index: i, Spawned: true})
+	if a.tally != nil {
+		a.tally.add()
+	}
+	return i
+}
+
+// cancelFrom marks the tasks from start onwards that never started as canceled.
+func (a *async) cancelFrom(start int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for i := start; i < len(a.reports); i++ {
+		if a.reports[i].Status == StatusPending {
+			a.reports[i].Status = StatusCanceled
+		}
+	}
+}
+
+// run executes a single task and records its outcome in the report.
+func (a *async) run(ctx context.Context, i int, t task) error {
+	var (
+		start   time.Time
+		skipped bool
+	)
+
+	err := safeCall(ctx, func(ctx context.Context) error {
+		if t.cond != nil && !t.cond(ctx) {
+			skipped = true
+			return nil
+		}
+		start = a.runner.cfg.clock.Now()
+		a.setStatus(i, StatusRunning)
+		if mws := a.runner.cfg.middleware; len(mws) > 0 {
+			return wrap(mws, TaskInfo{Index: i, Name: t.name, Result: isResultFunc(t.fn)}, t.fn)(ctx)
+		}
+		return t.fn(ctx)
+	})
+
+	if t.name != "" && errors.Is(err, ErrNilResult) {
+		// Points strict batches' failures at the offending task
+		err = detailed(err, "task %q", t.name)
+	}
+
+	// A type assertion rather than errors.As, which would make the common
+	// paths allocate
+	if panicErr, ok := err.(*PanicError); ok && a.runner.cfg.onPanic != nil {
+		a.runner.cfg.onPanic(panicErr)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	r := &a.reports[i]
+	r.Err = err
+	switch {
+	case skipped:
+		r.Status = StatusSkipped
+	case start.IsZero():
+		// The context was done before the task could start
+		r.Status = StatusCanceled
+	case err != nil:
+		r.Status = StatusFailed
+	default:
+		r.Status = StatusSucceeded
+	}
+	if !start.IsZero() {
+		r.Duration = a.runner.cfg.clock.Now().Sub(start)
+	}
+	a.progress = a.runner.cfg.clock.Now()
+	if a.settled {
+		return nil
+	}
+	if a.tally != nil {
+		if skipped {
+			return a.tally.skip()
+		}
+		return a.tally.done(err)
+	}
+	return err
+}
+
+// setStatus updates the status of the i-th task.
+func (a *async) setStatus(i int, status TaskStatus) {
+	a.mu.Lock()
+	a.reports[i].Status = status
+	a.mu.Unlock()
+}
+
+// Report returns a snapshot of the per-task outcomes of the last execution.
+func (a *async) Report() Report {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return Report{Tasks: append([]TaskReport(nil), a.reports...)}
+}
+
+// safeCall runs fn with panic recovery, skipping it when ctx is already done.
+func safeCall(ctx context.Context, fn AsyncFunc) (err error) {
+	// Panic Recovery: Prevents the entire application from crashing on unexpected errors
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	// Pre-check if context is already cancelled before execution. Err is
+	// cheaper than selecting on Done, which also makes the context allocate
+	// its channel
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return fn(ctx)
 }
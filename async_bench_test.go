@@ -74,4 +74,96 @@ func BenchmarkAsyncRawTask(b *testing.B) {
 			b.Fatal(err)
 		}
 	}
-}
\ No newline at end of file
+}
+func BenchmarkAsyncRelease(b *testing.B) {
+	runner := NewAsyncRunner()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a := runner.RunInAsync().
+			Task(func(ctx context.Context) error {
+				return nil
+			}).
+			Task(func(ctx context.Context) error {
+				return nil
+			})
+
+		if err := a.Go(context.Background()); err != nil {
+			b.Fatal(err)
+		}
+		a.Release()
+	}
+}
+
+func BenchmarkAsyncSingleTask(b *testing.B) {
+	runner := NewAsyncRunner()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var result int
+
+		err := runner.RunInAsync().
+			Task(Bind(&result, func(ctx context.Context) (int, error) {
+				return 1, nil
+			})).
+			Go(context.Background())
+
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAsyncFuture(b *testing.B) {
+	runner := NewAsyncRunner()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a := runner.RunInAsync()
+		f1 := TaskFuture(a, func(ctx context.Context) (int, error) {
+			return 1, nil
+		})
+		f2 := TaskFuture(a, func(ctx context.Context) (int, error) {
+			return 2, nil
+		})
+		f3 := TaskFuture(a, func(ctx context.Context) (int, error) {
+			return 3, nil
+		})
+
+		if err := a.Go(context.Background()); err != nil {
+			b.Fatal(err)
+		}
+		f1.Get()
+		f2.Get()
+		f3.Get()
+	}
+}
+
+func BenchmarkCollect(b *testing.B) {
+	fn := func(ctx context.Context) (int, error) {
+		return 1, nil
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Collect(context.Background(), fn, fn, fn); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSafeCall(b *testing.B) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fn := func(ctx context.Context) error {
+		return nil
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := safeCall(ctx, fn); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -3,6 +3,11 @@ package async
 import (
 	"context"
 	"errors"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -246,4 +251,578 @@ func TestAsyncContextPassedToTask(t *testing.T) {
 	if received != "test-value" {
 		t.Errorf("Expected 'test-value', got %s", received)
 	}
-}
\ No newline at end of file
+}
+func TestAsyncTaskIf(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var enabled, disabled bool
+
+	a := runner.RunInAsync().
+		TaskIf(func(ctx context.Context) bool { return true }, func(ctx context.Context) error {
+			enabled = true
+			return nil
+		}).
+		TaskIf(func(ctx context.Context) bool { return false }, func(ctx context.Context) error {
+			disabled = true
+			return nil
+		})
+
+	if err := a.Go(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !enabled {
+		t.Error("Expected enabled task to run")
+	}
+	if disabled {
+		t.Error("Expected disabled task to be skipped")
+	}
+
+	report := a.Report()
+	if report.Tasks[0].Status != StatusSucceeded {
+		t.Errorf("Expected first task to be succeeded, got %v", report.Tasks[0].Status)
+	}
+	if report.Tasks[1].Status != StatusSkipped {
+		t.Errorf("Expected second task to be skipped, got %v", report.Tasks[1].Status)
+	}
+}
+
+func TestAsyncReport(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := runner.RunInAsync().
+		Task(func(ctx context.Context) error {
+			return nil
+		}).
+		Task(func(ctx context.Context) error {
+			return errors.New("task failed")
+		})
+
+	if err := a.Go(ctx); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	report := a.Report()
+	if len(report.Tasks) != 2 {
+		t.Fatalf("Expected 2 task reports, got %d", len(report.Tasks))
+	}
+	if report.Tasks[1].Status != StatusFailed || report.Tasks[1].Err == nil {
+		t.Errorf("Expected second task to be failed with an error, got %+v", report.Tasks[1])
+	}
+
+	cancel()
+	a = runner.RunInAsync().
+		Task(func(ctx context.Context) error {
+			return nil
+		})
+	if err := a.Go(ctx); err == nil {
+		t.Fatal("Expected cancellation error, got nil")
+	}
+	if status := a.Report().Tasks[0].Status; status != StatusCanceled {
+		t.Errorf("Expected canceled status, got %v", status)
+	}
+}
+
+func TestAsyncPhase(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var firstDone atomic.Int32
+	var sawFirst int32
+
+	err := runner.RunInAsync().
+		Task(func(ctx context.Context) error {
+			time.Sleep(20 * time.Millisecond)
+			firstDone.Add(1)
+			return nil
+		}).
+		Task(func(ctx context.Context) error {
+			firstDone.Add(1)
+			return nil
+		}).
+		Phase().
+		Task(func(ctx context.Context) error {
+			sawFirst = firstDone.Load()
+			return nil
+		}).
+		Go(context.Background())
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sawFirst != 2 {
+		t.Errorf("Expected second phase to start after both first-phase tasks, saw %d", sawFirst)
+	}
+}
+
+func TestAsyncPhaseErrorStopsLaterPhases(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	called := false
+
+	a := runner.RunInAsync().
+		Task(func(ctx context.Context) error {
+			return errors.New("phase one failed")
+		}).
+		Phase().
+		Task(func(ctx context.Context) error {
+			called = true
+			return nil
+		})
+
+	err := a.Go(context.Background())
+	if err == nil || err.Error() != "phase one failed" {
+		t.Errorf("Expected 'phase one failed', got %v", err)
+	}
+	if called {
+		t.Error("Expected later phase not to run")
+	}
+	if status := a.Report().Tasks[1].Status; status != StatusCanceled {
+		t.Errorf("Expected later phase task to be canceled, got %v", status)
+	}
+}
+
+func TestAsyncWithConcurrency(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var current, peak atomic.Int32
+
+	a := runner.RunInAsync().WithConcurrency(2)
+	for i := 0; i < 6; i++ {
+		a.Task(func(ctx context.Context) error {
+			n := current.Add(1)
+			defer current.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		})
+	}
+
+	if err := a.Go(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if peak.Load() > 2 {
+		t.Errorf("Expected at most 2 concurrent tasks, got %d", peak.Load())
+	}
+}
+
+func TestAsyncConcurrencyGoroutines(t *testing.T) {
+	runner := NewAsyncRunner()
+	base := runtime.NumGoroutine()
+
+	var peak atomic.Int32
+	a := runner.RunInAsync().WithConcurrency(4)
+	for i := 0; i < 1000; i++ {
+		a.Task(func(ctx context.Context) error {
+			n := int32(runtime.NumGoroutine())
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			return nil
+		})
+	}
+
+	if err := a.Go(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// Leaves some slack for goroutines started by the runtime meanwhile
+	if extra := int(peak.Load()) - base; extra > 8 {
+		t.Errorf("Expected goroutines to scale with the limit, got %d extra", extra)
+	}
+}
+
+func TestRunnerDefaults(t *testing.T) {
+	runner := NewAsyncRunner(WithDefaultTimeout(20*time.Millisecond), WithDefaultConcurrency(1))
+
+	err := runner.RunInAsync().
+		Task(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}).
+		Go(context.Background())
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the default timeout to apply, got %v", err)
+	}
+
+	// A batch overrides the runner defaults
+	var running, peak atomic.Int32
+	task := func(ctx context.Context) error {
+		if n := running.Add(1); n > peak.Load() {
+			peak.Store(n)
+		}
+		time.Sleep(30 * time.Millisecond)
+		running.Add(-1)
+		return nil
+	}
+
+	err = runner.RunInAsync().
+		WithTimeout(time.Second).
+		WithConcurrency(0).
+		Task(task).
+		Task(task).
+		Go(context.Background())
+
+	if err != nil {
+		t.Fatalf("Expected no error with overridden timeout, got %v", err)
+	}
+	if peak.Load() != 2 {
+		t.Errorf("Expected overridden concurrency to allow 2 tasks at once, got %d", peak.Load())
+	}
+}
+
+func TestAsyncNilTask(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var ran atomic.Bool
+	a := runner.RunInAsync().
+		Task(func(ctx context.Context) error {
+			ran.Store(true)
+			return nil
+		}).
+		Task(Bind[int](nil, nil))
+
+	if !errors.Is(a.Err(), ErrNilTask) {
+		t.Fatalf("Expected ErrNilTask from Err, got %v", a.Err())
+	}
+	if err := a.Go(context.Background()); !errors.Is(err, ErrNilTask) || err.Error() != "async: nil task function at index 1" {
+		t.Errorf("Expected ErrNilTask at index 1, got %v", err)
+	}
+	if ran.Load() {
+		t.Error("Expected no task to run when the batch is invalid")
+	}
+
+	g := NewGraph().Node("a", nil)
+	if err := g.Validate(); !errors.Is(err, ErrNilTask) {
+		t.Errorf("Expected ErrNilTask from the graph, got %v", err)
+	}
+}
+
+func TestAsyncConcurrentTaskRegistration(t *testing.T) {
+	runner := NewAsyncRunner()
+	a := runner.RunInAsync()
+
+	var count atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.Task(func(ctx context.Context) error {
+				count.Add(1)
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	if err := a.Go(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if count.Load() != 50 {
+		t.Errorf("Expected 50 tasks to run, got %d", count.Load())
+	}
+	if n := len(a.Report().Tasks); n != 50 {
+		t.Errorf("Expected 50 task reports, got %d", n)
+	}
+}
+
+func TestAsyncRunOnce(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var count atomic.Int32
+	a := runner.RunInAsync().Task(func(ctx context.Context) error {
+		count.Add(1)
+		return nil
+	})
+
+	if err := a.Go(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := a.Go(context.Background()); !errors.Is(err, ErrBatchAlreadyRun) {
+		t.Errorf("Expected ErrBatchAlreadyRun, got %v", err)
+	}
+
+	a.Task(func(ctx context.Context) error {
+		count.Add(1)
+		return nil
+	})
+	if !errors.Is(a.Err(), ErrBatchSealed) {
+		t.Errorf("Expected ErrBatchSealed, got %v", a.Err())
+	}
+	if count.Load() != 1 {
+		t.Errorf("Expected the batch to run once, got %d", count.Load())
+	}
+}
+
+// The tests below are meant to run with -race: results written by tasks must
+// be visible to the caller once Go returns, whatever the outcome.
+func TestAsyncResultsVisibleAfterGo(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	results := make([]int, 20)
+	a := runner.RunInAsync()
+	for i := range results {
+		a.Task(Bind(&results[i], func(ctx context.Context) (int, error) {
+			return i * 2, nil
+		}))
+	}
+	if err := a.Go(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for i, v := range results {
+		if v != i*2 {
+			t.Errorf("Expected results[%d] = %d, got %d", i, i*2, v)
+		}
+	}
+}
+
+func TestAsyncResultsVisibleAfterError(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var slow int
+	started := make(chan struct{})
+	err := runner.RunInAsync().
+		Task(Bind(&slow, func(ctx context.Context) (int, error) {
+			close(started)
+			// Ignores cancellation, so Go must wait for the assignment
+			time.Sleep(20 * time.Millisecond)
+			return 7, nil
+		})).
+		Task(func(ctx context.Context) error {
+			<-started
+			return errors.New("fail fast")
+		}).
+		Go(context.Background())
+
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if slow != 7 {
+		t.Errorf("Expected the slow result to be assigned before Go returned, got %d", slow)
+	}
+}
+
+func TestAsyncMaxTasks(t *testing.T) {
+	runner := NewAsyncRunner(WithDefaultMaxTasks(3))
+
+	var count atomic.Int32
+	task := func(ctx context.Context) error {
+		count.Add(1)
+		return nil
+	}
+
+	a := runner.RunInAsync()
+	for i := 0; i < 1000; i++ {
+		a.Task(task)
+	}
+	if err := a.Go(context.Background()); !errors.Is(err, ErrTooManyTasks) {
+		t.Errorf("Expected ErrTooManyTasks, got %v", err)
+	}
+	if count.Load() != 0 {
+		t.Errorf("Expected no task to run, got %d", count.Load())
+	}
+
+	// Spawned tasks count towards the limit
+	var spawnErr error
+	err := runner.RunInAsync().
+		WithMaxTasks(2).
+		Task(func(ctx context.Context) error {
+			if err := Spawn(ctx, task); err != nil {
+				return err
+			}
+			spawnErr = Spawn(ctx, task)
+			return nil
+		}).
+		Go(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !errors.Is(spawnErr, ErrTooManyTasks) {
+		t.Errorf("Expected the second spawn to fail with ErrTooManyTasks, got %v", spawnErr)
+	}
+	if count.Load() != 1 {
+		t.Errorf("Expected one spawned task to run, got %d", count.Load())
+	}
+}
+
+func TestRunInAsyncN(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	a := runner.RunInAsyncN(100)
+	if c := cap(a.(*async).tasks); c < 100 {
+		t.Errorf("Expected room for 100 tasks, got %d", c)
+	}
+
+	var count atomic.Int32
+	for i := 0; i < 100; i++ {
+		a.Task(func(ctx context.Context) error {
+			count.Add(1)
+			return nil
+		})
+	}
+	if err := a.Go(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if count.Load() != 100 {
+		t.Errorf("Expected 100 tasks to run, got %d", count.Load())
+	}
+}
+
+// goroutineID returns the id of the calling goroutine, parsed from its stack.
+func goroutineID() string {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	return strings.Fields(string(buf))[1]
+}
+
+func TestAsyncSingleTaskInline(t *testing.T) {
+	runner := NewAsyncRunner()
+	caller := goroutineID()
+
+	var taskID string
+	var spawned atomic.Bool
+	err := runner.RunInAsync().
+		Task(func(ctx context.Context) error {
+			taskID = goroutineID()
+			return Spawn(ctx, func(ctx context.Context) error {
+				spawned.Store(true)
+				return nil
+			})
+		}).
+		Go(context.Background())
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if taskID != caller {
+		t.Errorf("Expected the task to run on the calling goroutine %s, got %s", caller, taskID)
+	}
+	if !spawned.Load() {
+		t.Error("Expected the spawned task to run")
+	}
+
+	// Timeouts still apply to the inlined task
+	err = runner.RunInAsync().
+		WithTimeout(10 * time.Millisecond).
+		Task(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}).
+		Go(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestAsyncRunAllocs(t *testing.T) {
+	a := NewAsyncRunner(WithPanicHandler(func(*PanicError) {})).RunInAsync().(*async)
+	a.reports = make([]TaskReport, 1)
+	tk := task{fn: func(ctx context.Context) error { return nil }}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if n := testing.AllocsPerRun(100, func() { _ = a.run(context.Background(), 0, tk) }); n != 0 {
+		t.Errorf("Expected a succeeding task not to allocate, got %v allocs", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { _ = a.run(canceled, 0, tk) }); n != 0 {
+		t.Errorf("Expected a canceled task not to allocate, got %v allocs", n)
+	}
+}
+
+func TestRunnerSynchronous(t *testing.T) {
+	pool := NewWorkerPool(2)
+	defer pool.Shutdown(context.Background())
+	runner := NewAsyncRunner(WithSynchronous(), WithPool("db", pool))
+	caller := goroutineID()
+
+	var order []string
+	record := func(name string) AsyncFunc {
+		return func(ctx context.Context) error {
+			if id := goroutineID(); id != caller {
+				t.Errorf("Expected %s to run on the calling goroutine, got %s", name, id)
+			}
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	err := runner.RunInAsync().
+		Task(func(ctx context.Context) error {
+			order = append(order, "first")
+			return Spawn(ctx, record("spawned"))
+		}).
+		TaskOn("db", record("pooled")).
+		Task(record("third")).
+		Phase().
+		Task(record("next phase")).
+		Go(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := []string{"first", "pooled", "third", "spawned", "next phase"}
+	if strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected order %v, got %v", want, order)
+	}
+
+	boom := errors.New("boom")
+	a := runner.RunInAsync().
+		Task(func(ctx context.Context) error { return boom }).
+		Task(record("after failure"))
+	if err := a.Go(context.Background()); !errors.Is(err, boom) {
+		t.Fatalf("Expected boom, got %v", err)
+	}
+	if s := a.Report().Tasks[1].Status; s != StatusCanceled {
+		t.Errorf("Expected the task after the failure to be canceled, got %v", s)
+	}
+}
+
+func TestRunnerSeededOrder(t *testing.T) {
+	run := func(seed uint64) string {
+		var order []string
+		a := NewAsyncRunner(WithSeededOrder(seed)).RunInAsync()
+		for i := 0; i < 8; i++ {
+			a.Task(func(ctx context.Context) error {
+				order = append(order, strconv.Itoa(i))
+				return nil
+			})
+		}
+		a.Phase().Task(func(ctx context.Context) error {
+			order = append(order, "last")
+			return nil
+		})
+		if err := a.Go(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return strings.Join(order, ",")
+	}
+
+	first := run(1)
+	if again := run(1); again != first {
+		t.Errorf("Expected the same seed to give the same order, got %s and %s", first, again)
+	}
+	if !strings.HasSuffix(first, ",last") {
+		t.Errorf("Expected phases to keep their order, got %s", first)
+	}
+
+	// Some seed among a few must depart from registration order
+	shuffled := false
+	for seed := uint64(1); seed <= 5 && !shuffled; seed++ {
+		shuffled = run(seed) != "0,1,2,3,4,5,6,7,last"
+	}
+	if !shuffled {
+		t.Error("Expected seeds to shuffle the tasks")
+	}
+}
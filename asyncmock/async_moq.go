@@ -0,0 +1,3706 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package asyncmock
+
+import (
+	"context"
+	"github.com/andryhardiyanto/go-async"
+	"sync"
+	"time"
+)
+
+// Ensure, that Runner does implement async.AsyncRunner.
+// If this is not the case, regenerate this file with moq.
+var _ async.AsyncRunner = &Runner{}
+
+// Runner is a mock implementation of async.AsyncRunner.
+//
+//	func TestSomethingThatUsesAsyncRunner(t *testing.T) {
+//
+//		// make and configure a mocked async.AsyncRunner
+//		mockedAsyncRunner := &Runner{
+//			AtFunc: func(ctx context.Context, t time.Time, fn async.AsyncFunc, opts ...async.ScheduleOption) async.ScheduledTask {
+//				panic("mock out the At method")
+//			},
+//			CacheStatsFunc: func() async.CacheStats {
+//				panic("mock out the CacheStats method")
+//			},
+//			DedupStatsFunc: func() async.DedupStats {
+//				panic("mock out the DedupStats method")
+//			},
+//			EveryFunc: func(ctx context.Context, interval time.Duration, fn async.AsyncFunc, opts ...async.ScheduleOption) async.ScheduledTask {
+//				panic("mock out the Every method")
+//			},
+//			HealthFunc: func() async.Health {
+//				panic("mock out the Health method")
+//			},
+//			OnIdleFunc: func(d time.Duration, fn func()) error {
+//				panic("mock out the OnIdle method")
+//			},
+//			OnShutdownFunc: func(phase int, fn func(ctx context.Context) error) error {
+//				panic("mock out the OnShutdown method")
+//			},
+//			PoolFunc: func(name string) async.WorkerPool {
+//				panic("mock out the Pool method")
+//			},
+//			RunInAsyncFunc: func() async.Async {
+//				panic("mock out the RunInAsync method")
+//			},
+//			RunInAsyncNFunc: func(n int) async.Async {
+//				panic("mock out the RunInAsyncN method")
+//			},
+//			ShutdownFunc: func(ctx context.Context) error {
+//				panic("mock out the Shutdown method")
+//			},
+//			ShuttingDownFunc: func() <-chan struct{} {
+//				panic("mock out the ShuttingDown method")
+//			},
+//			SuperviseFunc: func(ctx context.Context, fn async.AsyncFunc, policy async.RestartPolicy, opts ...async.ScheduleOption) async.SupervisedTask {
+//				panic("mock out the Supervise method")
+//			},
+//		}
+//
+//		// use mockedAsyncRunner in code that requires async.AsyncRunner
+//		// and then make assertions.
+//
+//	}
+type Runner struct {
+	// AtFunc mocks the At method.
+	AtFunc func(ctx context.Context, t time.Time, fn async.AsyncFunc, opts ...async.ScheduleOption) async.ScheduledTask
+
+	// CacheStatsFunc mocks the CacheStats method.
+	CacheStatsFunc func() async.CacheStats
+
+	// DedupStatsFunc mocks the DedupStats method.
+	DedupStatsFunc func() async.DedupStats
+
+	// EveryFunc mocks the Every method.
+	EveryFunc func(ctx context.Context, interval time.Duration, fn async.AsyncFunc, opts ...async.ScheduleOption) async.ScheduledTask
+
+	// HealthFunc mocks the Health method.
+	HealthFunc func() async.Health
+
+	// OnIdleFunc mocks the OnIdle method.
+	OnIdleFunc func(d time.Duration, fn func()) error
+
+	// OnShutdownFunc mocks the OnShutdown method.
+	OnShutdownFunc func(phase int, fn func(ctx context.Context) error) error
+
+	// PoolFunc mocks the Pool method.
+	PoolFunc func(name string) async.WorkerPool
+
+	// RunInAsyncFunc mocks the RunInAsync method.
+	RunInAsyncFunc func() async.Async
+
+	// RunInAsyncNFunc mocks the RunInAsyncN method.
+	RunInAsyncNFunc func(n int) async.Async
+
+	// ShutdownFunc mocks the Shutdown method.
+	ShutdownFunc func(ctx context.Context) error
+
+	// ShuttingDownFunc mocks the ShuttingDown method.
+	ShuttingDownFunc func() <-chan struct{}
+
+	// SuperviseFunc mocks the Supervise method.
+	SuperviseFunc func(ctx context.Context, fn async.AsyncFunc, policy async.RestartPolicy, opts ...async.ScheduleOption) async.SupervisedTask
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// At holds details about calls to the At method.
+		At []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// T is the t argument value.
+			T time.Time
+			// Fn is the fn argument value.
+			Fn async.AsyncFunc
+			// Opts is the opts argument value.
+			Opts []async.ScheduleOption
+		}
+		// CacheStats holds details about calls to the CacheStats method.
+		CacheStats []struct {
+		}
+		// DedupStats holds details about calls to the DedupStats method.
+		DedupStats []struct {
+		}
+		// Every holds details about calls to the Every method.
+		Every []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Interval is the interval argument value.
+			Interval time.Duration
+			// Fn is the fn argument value.
+			Fn async.AsyncFunc
+			// Opts is the opts argument value.
+			Opts []async.ScheduleOption
+		}
+		// Health holds details about calls to the Health method.
+		Health []struct {
+		}
+		// OnIdle holds details about calls to the OnIdle method.
+		OnIdle []struct {
+			// D is the d argument value.
+			D time.Duration
+			// Fn is the fn argument value.
+			Fn func()
+		}
+		// OnShutdown holds details about calls to the OnShutdown method.
+		OnShutdown []struct {
+			// Phase is the phase argument value.
+			Phase int
+			// Fn is the fn argument value.
+			Fn func(ctx context.Context) error
+		}
+		// Pool holds details about calls to the Pool method.
+		Pool []struct {
+			// Name is the name argument value.
+			Name string
+		}
+		// RunInAsync holds details about calls to the RunInAsync method.
+		RunInAsync []struct {
+		}
+		// RunInAsyncN holds details about calls to the RunInAsyncN method.
+		RunInAsyncN []struct {
+			// N is the n argument value.
+			N int
+		}
+		// Shutdown holds details about calls to the Shutdown method.
+		Shutdown []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// ShuttingDown holds details about calls to the ShuttingDown method.
+		ShuttingDown []struct {
+		}
+		// Supervise holds details about calls to the Supervise method.
+		Supervise []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Fn is the fn argument value.
+			Fn async.AsyncFunc
+			// Policy is the policy argument value.
+			Policy async.RestartPolicy
+			// Opts is the opts argument value.
+			Opts []async.ScheduleOption
+		}
+	}
+	lockAt           sync.RWMutex
+	lockCacheStats   sync.RWMutex
+	lockDedupStats   sync.RWMutex
+	lockEvery        sync.RWMutex
+	lockHealth       sync.RWMutex
+	lockOnIdle       sync.RWMutex
+	lockOnShutdown   sync.RWMutex
+	lockPool         sync.RWMutex
+	lockRunInAsync   sync.RWMutex
+	lockRunInAsyncN  sync.RWMutex
+	lockShutdown     sync.RWMutex
+	lockShuttingDown sync.RWMutex
+	lockSupervise    sync.RWMutex
+}
+
+// At calls AtFunc.
+func (mock *Runner) At(ctx context.Context, t time.Time, fn async.AsyncFunc, opts ...async.ScheduleOption) async.ScheduledTask {
+	callInfo := struct {
+		Ctx  context.Context
+		T    time.Time
+		Fn   async.AsyncFunc
+		Opts []async.ScheduleOption
+	}{
+		Ctx:  ctx,
+		T:    t,
+		Fn:   fn,
+		Opts: opts,
+	}
+	mock.lockAt.Lock()
+	mock.calls.At = append(mock.calls.At, callInfo)
+	mock.lockAt.Unlock()
+	if mock.AtFunc == nil {
+		var (
+			scheduledTaskOut async.ScheduledTask
+		)
+		return scheduledTaskOut
+	}
+	return mock.AtFunc(ctx, t, fn, opts...)
+}
+
+// AtCalls gets all the calls that were made to At.
+// Check the length with:
+//
+//	len(mockedAsyncRunner.AtCalls())
+func (mock *Runner) AtCalls() []struct {
+	Ctx  context.Context
+	T    time.Time
+	Fn   async.AsyncFunc
+	Opts []async.ScheduleOption
+} {
+	var calls []struct {
+		Ctx  context.Context
+		T    time.Time
+		Fn   async.AsyncFunc
+		Opts []async.ScheduleOption
+	}
+	mock.lockAt.RLock()
+	calls = mock.calls.At
+	mock.lockAt.RUnlock()
+	return calls
+}
+
+// CacheStats calls CacheStatsFunc.
+func (mock *Runner) CacheStats() async.CacheStats {
+	callInfo := struct {
+	}{}
+	mock.lockCacheStats.Lock()
+	mock.calls.CacheStats = append(mock.calls.CacheStats, callInfo)
+	mock.lockCacheStats.Unlock()
+	if mock.CacheStatsFunc == nil {
+		var (
+			cacheStatsOut async.CacheStats
+		)
+		return cacheStatsOut
+	}
+	return mock.CacheStatsFunc()
+}
+
+// CacheStatsCalls gets all the calls that were made to CacheStats.
+// Check the length with:
+//
+//	len(mockedAsyncRunner.CacheStatsCalls())
+func (mock *Runner) CacheStatsCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockCacheStats.RLock()
+	calls = mock.calls.CacheStats
+	mock.lockCacheStats.RUnlock()
+	return calls
+}
+
+// DedupStats calls DedupStatsFunc.
+func (mock *Runner) DedupStats() async.DedupStats {
+	callInfo := struct {
+	}{}
+	mock.lockDedupStats.Lock()
+	mock.calls.DedupStats = append(mock.calls.DedupStats, callInfo)
+	mock.lockDedupStats.Unlock()
+	if mock.DedupStatsFunc == nil {
+		var (
+			dedupStatsOut async.DedupStats
+		)
+		return dedupStatsOut
+	}
+	return mock.DedupStatsFunc()
+}
+
+// DedupStatsCalls gets all the calls that were made to DedupStats.
+// Check the length with:
+//
+//	len(mockedAsyncRunner.DedupStatsCalls())
+func (mock *Runner) DedupStatsCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockDedupStats.RLock()
+	calls = mock.calls.DedupStats
+	mock.lockDedupStats.RUnlock()
+	return calls
+}
+
+// Every calls EveryFunc.
+func (mock *Runner) Every(ctx context.Context, interval time.Duration, fn async.AsyncFunc, opts ...async.ScheduleOption) async.ScheduledTask {
+	callInfo := struct {
+		Ctx      context.Context
+		Interval time.Duration
+		Fn       async.AsyncFunc
+		Opts     []async.ScheduleOption
+	}{
+		Ctx:      ctx,
+		Interval: interval,
+		Fn:       fn,
+		Opts:     opts,
+	}
+	mock.lockEvery.Lock()
+	mock.calls.Every = append(mock.calls.Every, callInfo)
+	mock.lockEvery.Unlock()
+	if mock.EveryFunc == nil {
+		var (
+			scheduledTaskOut async.ScheduledTask
+		)
+		return scheduledTaskOut
+	}
+	return mock.EveryFunc(ctx, interval, fn, opts...)
+}
+
+// EveryCalls gets all the calls that were made to Every.
+// Check the length with:
+//
+//	len(mockedAsyncRunner.EveryCalls())
+func (mock *Runner) EveryCalls() []struct {
+	Ctx      context.Context
+	Interval time.Duration
+	Fn       async.AsyncFunc
+	Opts     []async.ScheduleOption
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Interval time.Duration
+		Fn       async.AsyncFunc
+		Opts     []async.ScheduleOption
+	}
+	mock.lockEvery.RLock()
+	calls = mock.calls.Every
+	mock.lockEvery.RUnlock()
+	return calls
+}
+
+// Health calls HealthFunc.
+func (mock *Runner) Health() async.Health {
+	callInfo := struct {
+	}{}
+	mock.lockHealth.Lock()
+	mock.calls.Health = append(mock.calls.Health, callInfo)
+	mock.lockHealth.Unlock()
+	if mock.HealthFunc == nil {
+		var (
+			healthOut async.Health
+		)
+		return healthOut
+	}
+	return mock.HealthFunc()
+}
+
+// HealthCalls gets all the calls that were made to Health.
+// Check the length with:
+//
+//	len(mockedAsyncRunner.HealthCalls())
+func (mock *Runner) HealthCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockHealth.RLock()
+	calls = mock.calls.Health
+	mock.lockHealth.RUnlock()
+	return calls
+}
+
+// OnIdle calls OnIdleFunc.
+func (mock *Runner) OnIdle(d time.Duration, fn func()) error {
+	callInfo := struct {
+		D  time.Duration
+		Fn func()
+	}{
+		D:  d,
+		Fn: fn,
+	}
+	mock.lockOnIdle.Lock()
+	mock.calls.OnIdle = append(mock.calls.OnIdle, callInfo)
+	mock.lockOnIdle.Unlock()
+	if mock.OnIdleFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.OnIdleFunc(d, fn)
+}
+
+// OnIdleCalls gets all the calls that were made to OnIdle.
+// Check the length with:
+//
+//	len(mockedAsyncRunner.OnIdleCalls())
+func (mock *Runner) OnIdleCalls() []struct {
+	D  time.Duration
+	Fn func()
+} {
+	var calls []struct {
+		D  time.Duration
+		Fn func()
+	}
+	mock.lockOnIdle.RLock()
+	calls = mock.calls.OnIdle
+	mock.lockOnIdle.RUnlock()
+	return calls
+}
+
+// OnShutdown calls OnShutdownFunc.
+func (mock *Runner) OnShutdown(phase int, fn func(ctx context.Context) error) error {
+	callInfo := struct {
+		Phase int
+		Fn    func(ctx context.Context) error
+	}{
+		Phase: phase,
+		Fn:    fn,
+	}
+	mock.lockOnShutdown.Lock()
+	mock.calls.OnShutdown = append(mock.calls.OnShutdown, callInfo)
+	mock.lockOnShutdown.Unlock()
+	if mock.OnShutdownFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.OnShutdownFunc(phase, fn)
+}
+
+// OnShutdownCalls gets all the calls that were made to OnShutdown.
+// Check the length with:
+//
+//	len(mockedAsyncRunner.OnShutdownCalls())
+func (mock *Runner) OnShutdownCalls() []struct {
+	Phase int
+	Fn    func(ctx context.Context) error
+} {
+	var calls []struct {
+		Phase int
+		Fn    func(ctx context.Context) error
+	}
+	mock.lockOnShutdown.RLock()
+	calls = mock.calls.OnShutdown
+	mock.lockOnShutdown.RUnlock()
+	return calls
+}
+
+// Pool calls PoolFunc.
+func (mock *Runner) Pool(name string) async.WorkerPool {
+	callInfo := struct {
+		Name string
+	}{
+		Name: name,
+	}
+	mock.lockPool.Lock()
+	mock.calls.Pool = append(mock.calls.Pool, callInfo)
+	mock.lockPool.Unlock()
+	if mock.PoolFunc == nil {
+		var (
+			workerPoolOut async.WorkerPool
+		)
+		return workerPoolOut
+	}
+	return mock.PoolFunc(name)
+}
+
+// PoolCalls gets all the calls that were made to Pool.
+// Check the length with:
+//
+//	len(mockedAsyncRunner.PoolCalls())
+func (mock *Runner) PoolCalls() []struct {
+	Name string
+} {
+	var calls []struct {
+		Name string
+	}
+	mock.lockPool.RLock()
+	calls = mock.calls.Pool
+	mock.lockPool.RUnlock()
+	return calls
+}
+
+// RunInAsync calls RunInAsyncFunc.
+func (mock *Runner) RunInAsync() async.Async {
+	callInfo := struct {
+	}{}
+	mock.lockRunInAsync.Lock()
+	mock.calls.RunInAsync = append(mock.calls.RunInAsync, callInfo)
+	mock.lockRunInAsync.Unlock()
+	if mock.RunInAsyncFunc == nil {
+		var (
+			asyncOut async.Async
+		)
+		return asyncOut
+	}
+	return mock.RunInAsyncFunc()
+}
+
+// RunInAsyncCalls gets all the calls that were made to RunInAsync.
+// Check the length with:
+//
+//	len(mockedAsyncRunner.RunInAsyncCalls())
+func (mock *Runner) RunInAsyncCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockRunInAsync.RLock()
+	calls = mock.calls.RunInAsync
+	mock.lockRunInAsync.RUnlock()
+	return calls
+}
+
+// RunInAsyncN calls RunInAsyncNFunc.
+func (mock *Runner) RunInAsyncN(n int) async.Async {
+	callInfo := struct {
+		N int
+	}{
+		N: n,
+	}
+	mock.lockRunInAsyncN.Lock()
+	mock.calls.RunInAsyncN = append(mock.calls.RunInAsyncN, callInfo)
+	mock.lockRunInAsyncN.Unlock()
+	if mock.RunInAsyncNFunc == nil {
+		var (
+			asyncOut async.Async
+		)
+		return asyncOut
+	}
+	return mock.RunInAsyncNFunc(n)
+}
+
+// RunInAsyncNCalls gets all the calls that were made to RunInAsyncN.
+// Check the length with:
+//
+//	len(mockedAsyncRunner.RunInAsyncNCalls())
+func (mock *Runner) RunInAsyncNCalls() []struct {
+	N int
+} {
+	var calls []struct {
+		N int
+	}
+	mock.lockRunInAsyncN.RLock()
+	calls = mock.calls.RunInAsyncN
+	mock.lockRunInAsyncN.RUnlock()
+	return calls
+}
+
+// Shutdown calls ShutdownFunc.
+func (mock *Runner) Shutdown(ctx context.Context) error {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockShutdown.Lock()
+	mock.calls.Shutdown = append(mock.calls.Shutdown, callInfo)
+	mock.lockShutdown.Unlock()
+	if mock.ShutdownFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.ShutdownFunc(ctx)
+}
+
+// ShutdownCalls gets all the calls that were made to Shutdown.
+// Check the length with:
+//
+//	len(mockedAsyncRunner.ShutdownCalls())
+func (mock *Runner) ShutdownCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockShutdown.RLock()
+	calls = mock.calls.Shutdown
+	mock.lockShutdown.RUnlock()
+	return calls
+}
+
+// ShuttingDown calls ShuttingDownFunc.
+func (mock *Runner) ShuttingDown() <-chan struct{} {
+	callInfo := struct {
+	}{}
+	mock.lockShuttingDown.Lock()
+	mock.calls.ShuttingDown = append(mock.calls.ShuttingDown, callInfo)
+	mock.lockShuttingDown.Unlock()
+	if mock.ShuttingDownFunc == nil {
+		var (
+			valChOut <-chan struct{}
+		)
+		return valChOut
+	}
+	return mock.ShuttingDownFunc()
+}
+
+// ShuttingDownCalls gets all the calls that were made to ShuttingDown.
+// Check the length with:
+//
+//	len(mockedAsyncRunner.ShuttingDownCalls())
+func (mock *Runner) ShuttingDownCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockShuttingDown.RLock()
+	calls = mock.calls.ShuttingDown
+	mock.lockShuttingDown.RUnlock()
+	return calls
+}
+
+// Supervise calls SuperviseFunc.
+func (mock *Runner) Supervise(ctx context.Context, fn async.AsyncFunc, policy async.RestartPolicy, opts ...async.ScheduleOption) async.SupervisedTask {
+	callInfo := struct {
+		Ctx    context.Context
+		Fn     async.AsyncFunc
+		Policy async.RestartPolicy
+		Opts   []async.ScheduleOption
+	}{
+		Ctx:    ctx,
+		Fn:     fn,
+		Policy: policy,
+		Opts:   opts,
+	}
+	mock.lockSupervise.Lock()
+	mock.calls.Supervise = append(mock.calls.Supervise, callInfo)
+	mock.lockSupervise.Unlock()
+	if mock.SuperviseFunc == nil {
+		var (
+			supervisedTaskOut async.SupervisedTask
+		)
+		return supervisedTaskOut
+	}
+	return mock.SuperviseFunc(ctx, fn, policy, opts...)
+}
+
+// SuperviseCalls gets all the calls that were made to Supervise.
+// Check the length with:
+//
+//	len(mockedAsyncRunner.SuperviseCalls())
+func (mock *Runner) SuperviseCalls() []struct {
+	Ctx    context.Context
+	Fn     async.AsyncFunc
+	Policy async.RestartPolicy
+	Opts   []async.ScheduleOption
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Fn     async.AsyncFunc
+		Policy async.RestartPolicy
+		Opts   []async.ScheduleOption
+	}
+	mock.lockSupervise.RLock()
+	calls = mock.calls.Supervise
+	mock.lockSupervise.RUnlock()
+	return calls
+}
+
+// Ensure, that Batch does implement async.Async.
+// If this is not the case, regenerate this file with moq.
+var _ async.Async = &Batch{}
+
+// Batch is a mock implementation of async.Async.
+//
+//	func TestSomethingThatUsesAsync(t *testing.T) {
+//
+//		// make and configure a mocked async.Async
+//		mockedAsync := &Batch{
+//			AllowSharedDestFunc: func() async.Async {
+//				panic("mock out the AllowSharedDest method")
+//			},
+//			CriticalFunc: func() async.Async {
+//				panic("mock out the Critical method")
+//			},
+//			ErrFunc: func() error {
+//				panic("mock out the Err method")
+//			},
+//			GoFunc: func(ctx context.Context) error {
+//				panic("mock out the Go method")
+//			},
+//			GoSettledFunc: func(ctx context.Context) ([]async.TaskReport, error) {
+//				panic("mock out the GoSettled method")
+//			},
+//			PhaseFunc: func() async.Async {
+//				panic("mock out the Phase method")
+//			},
+//			ReleaseFunc: func()  {
+//				panic("mock out the Release method")
+//			},
+//			ReportFunc: func() async.Report {
+//				panic("mock out the Report method")
+//			},
+//			ResetFunc: func() async.Async {
+//				panic("mock out the Reset method")
+//			},
+//			StrictFunc: func() async.Async {
+//				panic("mock out the Strict method")
+//			},
+//			TaskFunc: func(fn async.AsyncFunc) async.Async {
+//				panic("mock out the Task method")
+//			},
+//			TaskDelayedFunc: func(delay time.Duration, fn async.AsyncFunc, opts ...async.ScheduleOption) async.Async {
+//				panic("mock out the TaskDelayed method")
+//			},
+//			TaskIfFunc: func(pred func(ctx context.Context) bool, fn async.AsyncFunc) async.Async {
+//				panic("mock out the TaskIf method")
+//			},
+//			TaskJSONFunc: func(dest any, fn func(ctx context.Context) ([]byte, error)) async.Async {
+//				panic("mock out the TaskJSON method")
+//			},
+//			TaskNamedFunc: func(name string, fn async.AsyncFunc) async.Async {
+//				panic("mock out the TaskNamed method")
+//			},
+//			TaskOnFunc: func(name string, fn async.AsyncFunc) async.Async {
+//				panic("mock out the TaskOn method")
+//			},
+//			TaskWithTimeoutFunc: func(timeout time.Duration, fn async.AsyncFunc) async.Async {
+//				panic("mock out the TaskWithTimeout method")
+//			},
+//			WithAssignerFunc: func(assigner async.Assigner) async.Async {
+//				panic("mock out the WithAssigner method")
+//			},
+//			WithConcurrencyFunc: func(n int) async.Async {
+//				panic("mock out the WithConcurrency method")
+//			},
+//			WithCopyResultsFunc: func() async.Async {
+//				panic("mock out the WithCopyResults method")
+//			},
+//			WithMaxTasksFunc: func(n int) async.Async {
+//				panic("mock out the WithMaxTasks method")
+//			},
+//			WithQuorumFunc: func(n int) async.Async {
+//				panic("mock out the WithQuorum method")
+//			},
+//			WithTimeoutFunc: func(timeout time.Duration) async.Async {
+//				panic("mock out the WithTimeout method")
+//			},
+//			WithWatchdogFunc: func(d time.Duration, onStall func(async.Report)) async.Async {
+//				panic("mock out the WithWatchdog method")
+//			},
+//		}
+//
+//		// use mockedAsync in code that requires async.Async
+//		// and then make assertions.
+//
+//	}
+type Batch struct {
+	// AllowSharedDestFunc mocks the AllowSharedDest method.
+	AllowSharedDestFunc func() async.Async
+
+	// CriticalFunc mocks the Critical method.
+	CriticalFunc func() async.Async
+
+	// ErrFunc mocks the Err method.
+	ErrFunc func() error
+
+	// GoFunc mocks the Go method.
+	GoFunc func(ctx context.Context) error
+
+	// GoSettledFunc mocks the GoSettled method.
+	GoSettledFunc func(ctx context.Context) ([]async.TaskReport, error)
+
+	// PhaseFunc mocks the Phase method.
+	PhaseFunc func() async.Async
+
+	// ReleaseFunc mocks the Release method.
+	ReleaseFunc func()
+
+	// ReportFunc mocks the Report method.
+	ReportFunc func() async.Report
+
+	// ResetFunc mocks the Reset method.
+	ResetFunc func() async.Async
+
+	// StrictFunc mocks the Strict method.
+	StrictFunc func() async.Async
+
+	// TaskFunc mocks the Task method.
+	TaskFunc func(fn async.AsyncFunc) async.Async
+
+	// TaskDelayedFunc mocks the TaskDelayed method.
+	TaskDelayedFunc func(delay time.Duration, fn async.AsyncFunc, opts ...async.ScheduleOption) async.Async
+
+	// TaskIfFunc mocks the TaskIf method.
+	TaskIfFunc func(pred func(ctx context.Context) bool, fn async.AsyncFunc) async.Async
+
+	// TaskJSONFunc mocks the TaskJSON method.
+	TaskJSONFunc func(dest any, fn func(ctx context.Context) ([]byte, error)) async.Async
+
+	// TaskNamedFunc mocks the TaskNamed method.
+	TaskNamedFunc func(name string, fn async.AsyncFunc) async.Async
+
+	// TaskOnFunc mocks the TaskOn method.
+	TaskOnFunc func(name string, fn async.AsyncFunc) async.Async
+
+	// TaskWithTimeoutFunc mocks the TaskWithTimeout method.
+	TaskWithTimeoutFunc func(timeout time.Duration, fn async.AsyncFunc) async.Async
+
+	// WithAssignerFunc mocks the WithAssigner method.
+	WithAssignerFunc func(assigner async.Assigner) async.Async
+
+	// WithConcurrencyFunc mocks the WithConcurrency method.
+	WithConcurrencyFunc func(n int) async.Async
+
+	// WithCopyResultsFunc mocks the WithCopyResults method.
+	WithCopyResultsFunc func() async.Async
+
+	// WithMaxTasksFunc mocks the WithMaxTasks method.
+	WithMaxTasksFunc func(n int) async.Async
+
+	// WithQuorumFunc mocks the WithQuorum method.
+	WithQuorumFunc func(n int) async.Async
+
+	// WithTimeoutFunc mocks the WithTimeout method.
+	WithTimeoutFunc func(timeout time.Duration) async.Async
+
+	// WithWatchdogFunc mocks the WithWatchdog method.
+	WithWatchdogFunc func(d time.Duration, onStall func(async.Report)) async.Async
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// AllowSharedDest holds details about calls to the AllowSharedDest method.
+		AllowSharedDest []struct {
+		}
+		// Critical holds details about calls to the Critical method.
+		Critical []struct {
+		}
+		// Err holds details about calls to the Err method.
+		Err []struct {
+		}
+		// Go holds details about calls to the Go method.
+		Go []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// GoSettled holds details about calls to the GoSettled method.
+		GoSettled []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// Phase holds details about calls to the Phase method.
+		Phase []struct {
+		}
+		// Release holds details about calls to the Release method.
+		Release []struct {
+		}
+		// Report holds details about calls to the Report method.
+		Report []struct {
+		}
+		// Reset holds details about calls to the Reset method.
+		Reset []struct {
+		}
+		// Strict holds details about calls to the Strict method.
+		Strict []struct {
+		}
+		// Task holds details about calls to the Task method.
+		Task []struct {
+			// Fn is the fn argument value.
+			Fn async.AsyncFunc
+		}
+		// TaskDelayed holds details about calls to the TaskDelayed method.
+		TaskDelayed []struct {
+			// Delay is the delay argument value.
+			Delay time.Duration
+			// Fn is the fn argument value.
+			Fn async.AsyncFunc
+			// Opts is the opts argument value.
+			Opts []async.ScheduleOption
+		}
+		// TaskIf holds details about calls to the TaskIf method.
+		TaskIf []struct {
+			// Pred is the pred argument value.
+			Pred func(ctx context.Context) bool
+			// Fn is the fn argument value.
+			Fn async.AsyncFunc
+		}
+		// TaskJSON holds details about calls to the TaskJSON method.
+		TaskJSON []struct {
+			// Dest is the dest argument value.
+			Dest any
+			// Fn is the fn argument value.
+			Fn func(ctx context.Context) ([]byte, error)
+		}
+		// TaskNamed holds details about calls to the TaskNamed method.
+		TaskNamed []struct {
+			// Name is the name argument value.
+			Name string
+			// Fn is the fn argument value.
+			Fn async.AsyncFunc
+		}
+		// TaskOn holds details about calls to the TaskOn method.
+		TaskOn []struct {
+			// Name is the name argument value.
+			Name string
+			// Fn is the fn argument value.
+			Fn async.AsyncFunc
+		}
+		// TaskWithTimeout holds details about calls to the TaskWithTimeout method.
+		TaskWithTimeout []struct {
+			// Timeout is the timeout argument value.
+			Timeout time.Duration
+			// Fn is the fn argument value.
+			Fn async.AsyncFunc
+		}
+		// WithAssigner holds details about calls to the WithAssigner method.
+		WithAssigner []struct {
+			// Assigner is the assigner argument value.
+			Assigner async.Assigner
+		}
+		// WithConcurrency holds details about calls to the WithConcurrency method.
+		WithConcurrency []struct {
+			// N is the n argument value.
+			N int
+		}
+		// WithCopyResults holds details about calls to the WithCopyResults method.
+		WithCopyResults []struct {
+		}
+		// WithMaxTasks holds details about calls to the WithMaxTasks method.
+		WithMaxTasks []struct {
+			// N is the n argument value.
+			N int
+		}
+		// WithQuorum holds details about calls to the WithQuorum method.
+		WithQuorum []struct {
+			// N is the n argument value.
+			N int
+		}
+		// WithTimeout holds details about calls to the WithTimeout method.
+		WithTimeout []struct {
+			// Timeout is the timeout argument value.
+			Timeout time.Duration
+		}
+		// WithWatchdog holds details about calls to the WithWatchdog method.
+		WithWatchdog []struct {
+			// D is the d argument value.
+			D time.Duration
+			// OnStall is the onStall argument value.
+			OnStall func(async.Report)
+		}
+	}
+	lockAllowSharedDest sync.RWMutex
+	lockCritical        sync.RWMutex
+	lockErr             sync.RWMutex
+	lockGo              sync.RWMutex
+	lockGoSettled       sync.RWMutex
+	lockPhase           sync.RWMutex
+	lockRelease         sync.RWMutex
+	lockReport          sync.RWMutex
+	lockReset           sync.RWMutex
+	lockStrict          sync.RWMutex
+	lockTask            sync.RWMutex
+	lockTaskDelayed     sync.RWMutex
+	lockTaskIf          sync.RWMutex
+	lockTaskJSON        sync.RWMutex
+	lockTaskNamed       sync.RWMutex
+	lockTaskOn          sync.RWMutex
+	lockTaskWithTimeout sync.RWMutex
+	lockWithAssigner    sync.RWMutex
+	lockWithConcurrency sync.RWMutex
+	lockWithCopyResults sync.RWMutex
+	lockWithMaxTasks    sync.RWMutex
+	lockWithQuorum      sync.RWMutex
+	lockWithTimeout     sync.RWMutex
+	lockWithWatchdog    sync.RWMutex
+}
+
+// AllowSharedDest calls AllowSharedDestFunc.
+func (mock *Batch) AllowSharedDest() async.Async {
+	callInfo := struct {
+	}{}
+	mock.lockAllowSharedDest.Lock()
+	mock.calls.AllowSharedDest = append(mock.calls.AllowSharedDest, callInfo)
+	mock.lockAllowSharedDest.Unlock()
+	if mock.AllowSharedDestFunc == nil {
+		var (
+			asyncOut async.Async
+		)
+		return asyncOut
+	}
+	return mock.AllowSharedDestFunc()
+}
+
+// AllowSharedDestCalls gets all the calls that were made to AllowSharedDest.
+// Check the length with:
+//
+//	len(mockedAsync.AllowSharedDestCalls())
+func (mock *Batch) AllowSharedDestCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockAllowSharedDest.RLock()
+	calls = mock.calls.AllowSharedDest
+	mock.lockAllowSharedDest.RUnlock()
+	return calls
+}
+
+// Critical calls CriticalFunc.
+func (mock *Batch) Critical() async.Async {
+	callInfo := struct {
+	}{}
+	mock.lockCritical.Lock()
+	mock.calls.Critical = append(mock.calls.Critical, callInfo)
+	mock.lockCritical.Unlock()
+	if mock.CriticalFunc == nil {
+		var (
+			asyncOut async.Async
+		)
+		return asyncOut
+	}
+	return mock.CriticalFunc()
+}
+
+// CriticalCalls gets all the calls that were made to Critical.
+// Check the length with:
+//
+//	len(mockedAsync.CriticalCalls())
+func (mock *Batch) CriticalCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockCritical.RLock()
+	calls = mock.calls.Critical
+	mock.lockCritical.RUnlock()
+	return calls
+}
+
+// Err calls ErrFunc.
+func (mock *Batch) Err() error {
+	callInfo := struct {
+	}{}
+	mock.lockErr.Lock()
+	mock.calls.Err = append(mock.calls.Err, callInfo)
+	mock.lockErr.Unlock()
+	if mock.ErrFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.ErrFunc()
+}
+
+// ErrCalls gets all the calls that were made to Err.
+// Check the length with:
+//
+//	len(mockedAsync.ErrCalls())
+func (mock *Batch) ErrCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockErr.RLock()
+	calls = mock.calls.Err
+	mock.lockErr.RUnlock()
+	return calls
+}
+
+// Go calls GoFunc.
+func (mock *Batch) Go(ctx context.Context) error {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGo.Lock()
+	mock.calls.Go = append(mock.calls.Go, callInfo)
+	mock.lockGo.Unlock()
+	if mock.GoFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.GoFunc(ctx)
+}
+
+// GoCalls gets all the calls that were made to Go.
+// Check the length with:
+//
+//	len(mockedAsync.GoCalls())
+func (mock *Batch) GoCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGo.RLock()
+	calls = mock.calls.Go
+	mock.lockGo.RUnlock()
+	return calls
+}
+
+// GoSettled calls GoSettledFunc.
+func (mock *Batch) GoSettled(ctx context.Context) ([]async.TaskReport, error) {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGoSettled.Lock()
+	mock.calls.GoSettled = append(mock.calls.GoSettled, callInfo)
+	mock.lockGoSettled.Unlock()
+	if mock.GoSettledFunc == nil {
+		var (
+			taskReportsOut []async.TaskReport
+			errOut         error
+		)
+		return taskReportsOut, errOut
+	}
+	return mock.GoSettledFunc(ctx)
+}
+
+// GoSettledCalls gets all the calls that were made to GoSettled.
+// Check the length with:
+//
+//	len(mockedAsync.GoSettledCalls())
+func (mock *Batch) GoSettledCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGoSettled.RLock()
+	calls = mock.calls.GoSettled
+	mock.lockGoSettled.RUnlock()
+	return calls
+}
+
+// Phase calls PhaseFunc.
+func (mock *Batch) Phase() async.Async {
+	callInfo := struct {
+	}{}
+	mock.lockPhase.Lock()
+	mock.calls.Phase = append(mock.calls.Phase, callInfo)
+	mock.lockPhase.Unlock()
+	if mock.PhaseFunc == nil {
+		var (
+			asyncOut async.Async
+		)
+		return asyncOut
+	}
+	return mock.PhaseFunc()
+}
+
+// PhaseCalls gets all the calls that were made to Phase.
+// Check the length with:
+//
+//	len(mockedAsync.PhaseCalls())
+func (mock *Batch) PhaseCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockPhase.RLock()
+	calls = mock.calls.Phase
+	mock.lockPhase.RUnlock()
+	return calls
+}
+
+// Release calls ReleaseFunc.
+func (mock *Batch) Release() {
+	callInfo := struct {
+	}{}
+	mock.lockRelease.Lock()
+	mock.calls.Release = append(mock.calls.Release, callInfo)
+	mock.lockRelease.Unlock()
+	if mock.ReleaseFunc == nil {
+		return
+	}
+	mock.ReleaseFunc()
+}
+
+// ReleaseCalls gets all the calls that were made to Release.
+// Check the length with:
+//
+//	len(mockedAsync.ReleaseCalls())
+func (mock *Batch) ReleaseCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockRelease.RLock()
+	calls = mock.calls.Release
+	mock.lockRelease.RUnlock()
+	return calls
+}
+
+// Report calls ReportFunc.
+func (mock *Batch) Report() async.Report {
+	callInfo := struct {
+	}{}
+	mock.lockReport.Lock()
+	mock.calls.Report = append(mock.calls.Report, callInfo)
+	mock.lockReport.Unlock()
+	if mock.ReportFunc == nil {
+		var (
+			reportOut async.Report
+		)
+		return reportOut
+	}
+	return mock.ReportFunc()
+}
+
+// ReportCalls gets all the calls that were made to Report.
+// Check the length with:
+//
+//	len(mockedAsync.ReportCalls())
+func (mock *Batch) ReportCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockReport.RLock()
+	calls = mock.calls.Report
+	mock.lockReport.RUnlock()
+	return calls
+}
+
+// Reset calls ResetFunc.
+func (mock *Batch) Reset() async.Async {
+	callInfo := struct {
+	}{}
+	mock.lockReset.Lock()
+	mock.calls.Reset = append(mock.calls.Reset, callInfo)
+	mock.lockReset.Unlock()
+	if mock.ResetFunc == nil {
+		var (
+			asyncOut async.Async
+		)
+		return asyncOut
+	}
+	return mock.ResetFunc()
+}
+
+// ResetCalls gets all the calls that were made to Reset.
+// Check the length with:
+//
+//	len(mockedAsync.ResetCalls())
+func (mock *Batch) ResetCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockReset.RLock()
+	calls = mock.calls.Reset
+	mock.lockReset.RUnlock()
+	return calls
+}
+
+// Strict calls StrictFunc.
+func (mock *Batch) Strict() async.Async {
+	callInfo := struct {
+	}{}
+	mock.lockStrict.Lock()
+	mock.calls.Strict = append(mock.calls.Strict, callInfo)
+	mock.lockStrict.Unlock()
+	if mock.StrictFunc == nil {
+		var (
+			asyncOut async.Async
+		)
+		return asyncOut
+	}
+	return mock.StrictFunc()
+}
+
+// StrictCalls gets all the calls that were made to Strict.
+// Check the length with:
+//
+//	len(mockedAsync.StrictCalls())
+func (mock *Batch) StrictCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockStrict.RLock()
+	calls = mock.calls.Strict
+	mock.lockStrict.RUnlock()
+	return calls
+}
+
+// Task calls TaskFunc.
+func (mock *Batch) Task(fn async.AsyncFunc) async.Async {
+	callInfo := struct {
+		Fn async.AsyncFunc
+	}{
+		Fn: fn,
+	}
+	mock.lockTask.Lock()
+	mock.calls.Task = append(mock.calls.Task, callInfo)
+	mock.lockTask.Unlock()
+	if mock.TaskFunc == nil {
+		var (
+			asyncOut async.Async
+		)
+		return asyncOut
+	}
+	return mock.TaskFunc(fn)
+}
+
+// TaskCalls gets all the calls that were made to Task.
+// Check the length with:
+//
+//	len(mockedAsync.TaskCalls())
+func (mock *Batch) TaskCalls() []struct {
+	Fn async.AsyncFunc
+} {
+	var calls []struct {
+		Fn async.AsyncFunc
+	}
+	mock.lockTask.RLock()
+	calls = mock.calls.Task
+	mock.lockTask.RUnlock()
+	return calls
+}
+
+// TaskDelayed calls TaskDelayedFunc.
+func (mock *Batch) TaskDelayed(delay time.Duration, fn async.AsyncFunc, opts ...async.ScheduleOption) async.Async {
+	callInfo := struct {
+		Delay time.Duration
+		Fn    async.AsyncFunc
+		Opts  []async.ScheduleOption
+	}{
+		Delay: delay,
+		Fn:    fn,
+		Opts:  opts,
+	}
+	mock.lockTaskDelayed.Lock()
+	mock.calls.TaskDelayed = append(mock.calls.TaskDelayed, callInfo)
+	mock.lockTaskDelayed.Unlock()
+	if mock.TaskDelayedFunc == nil {
+		var (
+			asyncOut async.Async
+		)
+		return asyncOut
+	}
+	return mock.TaskDelayedFunc(delay, fn, opts...)
+}
+
+// TaskDelayedCalls gets all the calls that were made to TaskDelayed.
+// Check the length with:
+//
+//	len(mockedAsync.TaskDelayedCalls())
+func (mock *Batch) TaskDelayedCalls() []struct {
+	Delay time.Duration
+	Fn    async.AsyncFunc
+	Opts  []async.ScheduleOption
+} {
+	var calls []struct {
+		Delay time.Duration
+		Fn    async.AsyncFunc
+		Opts  []async.ScheduleOption
+	}
+	mock.lockTaskDelayed.RLock()
+	calls = mock.calls.TaskDelayed
+	mock.lockTaskDelayed.RUnlock()
+	return calls
+}
+
+// TaskIf calls TaskIfFunc.
+func (mock *Batch) TaskIf(pred func(ctx context.Context) bool, fn async.AsyncFunc) async.Async {
+	callInfo := struct {
+		Pred func(ctx context.Context) bool
+		Fn   async.AsyncFunc
+	}{
+		Pred: pred,
+		Fn:   fn,
+	}
+	mock.lockTaskIf.Lock()
+	mock.calls.TaskIf = append(mock.calls.TaskIf, callInfo)
+	mock.lockTaskIf.Unlock()
+	if mock.TaskIfFunc == nil {
+		var (
+			asyncOut async.Async
+		)
+		return asyncOut
+	}
+	return mock.TaskIfFunc(pred, fn)
+}
+
+// TaskIfCalls gets all the calls that were made to TaskIf.
+// Check the length with:
+//
+//	len(mockedAsync.TaskIfCalls())
+func (mock *Batch) TaskIfCalls() []struct {
+	Pred func(ctx context.Context) bool
+	Fn   async.AsyncFunc
+} {
+	var calls []struct {
+		Pred func(ctx context.Context) bool
+		Fn   async.AsyncFunc
+	}
+	mock.lockTaskIf.RLock()
+	calls = mock.calls.TaskIf
+	mock.lockTaskIf.RUnlock()
+	return calls
+}
+
+// TaskJSON calls TaskJSONFunc.
+func (mock *Batch) TaskJSON(dest any, fn func(ctx context.Context) ([]byte, error)) async.Async {
+	callInfo := struct {
+		Dest any
+		Fn   func(ctx context.Context) ([]byte, error)
+	}{
+		Dest: dest,
+		Fn:   fn,
+	}
+	mock.lockTaskJSON.Lock()
+	mock.calls.TaskJSON = append(mock.calls.TaskJSON, callInfo)
+	mock.lockTaskJSON.Unlock()
+	if mock.TaskJSONFunc == nil {
+		var (
+			asyncOut async.Async
+		)
+		return asyncOut
+	}
+	return mock.TaskJSONFunc(dest, fn)
+}
+
+// TaskJSONCalls gets all the calls that were made to TaskJSON.
+// Check the length with:
+//
+//	len(mockedAsync.TaskJSONCalls())
+func (mock *Batch) TaskJSONCalls() []struct {
+	Dest any
+	Fn   func(ctx context.Context) ([]byte, error)
+} {
+	var calls []struct {
+		Dest any
+		Fn   func(ctx context.Context) ([]byte, error)
+	}
+	mock.lockTaskJSON.RLock()
+	calls = mock.calls.TaskJSON
+	mock.lockTaskJSON.RUnlock()
+	return calls
+}
+
+// TaskNamed calls TaskNamedFunc.
+func (mock *Batch) TaskNamed(name string, fn async.AsyncFunc) async.Async {
+	callInfo := struct {
+		Name string
+		Fn   async.AsyncFunc
+	}{
+		Name: name,
+		Fn:   fn,
+	}
+	mock.lockTaskNamed.Lock()
+	mock.calls.TaskNamed = append(mock.calls.TaskNamed, callInfo)
+	mock.lockTaskNamed.Unlock()
+	if mock.TaskNamedFunc == nil {
+		var (
+			asyncOut async.Async
+		)
+		return asyncOut
+	}
+	return mock.TaskNamedFunc(name, fn)
+}
+
+// TaskNamedCalls gets all the calls that were made to TaskNamed.
+// Check the length with:
+//
+//	len(mockedAsync.TaskNamedCalls())
+func (mock *Batch) TaskNamedCalls() []struct {
+	Name string
+	Fn   async.AsyncFunc
+} {
+	var calls []struct {
+		Name string
+		Fn   async.AsyncFunc
+	}
+	mock.lockTaskNamed.RLock()
+	calls = mock.calls.TaskNamed
+	mock.lockTaskNamed.RUnlock()
+	return calls
+}
+
+// TaskOn calls TaskOnFunc.
+func (mock *Batch) TaskOn(name string, fn async.AsyncFunc) async.Async {
+	callInfo := struct {
+		Name string
+		Fn   async.AsyncFunc
+	}{
+		Name: name,
+		Fn:   fn,
+	}
+	mock.lockTaskOn.Lock()
+	mock.calls.TaskOn = append(mock.calls.TaskOn, callInfo)
+	mock.lockTaskOn.Unlock()
+	if mock.TaskOnFunc == nil {
+		var (
+			asyncOut async.Async
+		)
+		return asyncOut
+	}
+	return mock.TaskOnFunc(name, fn)
+}
+
+// TaskOnCalls gets all the calls that were made to TaskOn.
+// Check the length with:
+//
+//	len(mockedAsync.TaskOnCalls())
+func (mock *Batch) TaskOnCalls() []struct {
+	Name string
+	Fn   async.AsyncFunc
+} {
+	var calls []struct {
+		Name string
+		Fn   async.AsyncFunc
+	}
+	mock.lockTaskOn.RLock()
+	calls = mock.calls.TaskOn
+	mock.lockTaskOn.RUnlock()
+	return calls
+}
+
+// TaskWithTimeout calls TaskWithTimeoutFunc.
+func (mock *Batch) TaskWithTimeout(timeout time.Duration, fn async.AsyncFunc) async.Async {
+	callInfo := struct {
+		Timeout time.Duration
+		Fn      async.AsyncFunc
+	}{
+		Timeout: timeout,
+		Fn:      fn,
+	}
+	mock.lockTaskWithTimeout.Lock()
+	mock.calls.TaskWithTimeout = append(mock.calls.TaskWithTimeout, callInfo)
+	mock.lockTaskWithTimeout.Unlock()
+	if mock.TaskWithTimeoutFunc == nil {
+		var (
+			asyncOut async.Async
+		)
+		return asyncOut
+	}
+	return mock.TaskWithTimeoutFunc(timeout, fn)
+}
+
+// TaskWithTimeoutCalls gets all the calls that were made to TaskWithTimeout.
+// Check the length with:
+//
+//	len(mockedAsync.TaskWithTimeoutCalls())
+func (mock *Batch) TaskWithTimeoutCalls() []struct {
+	Timeout time.Duration
+	Fn      async.AsyncFunc
+} {
+	var calls []struct {
+		Timeout time.Duration
+		Fn      async.AsyncFunc
+	}
+	mock.lockTaskWithTimeout.RLock()
+	calls = mock.calls.TaskWithTimeout
+	mock.lockTaskWithTimeout.RUnlock()
+	return calls
+}
+
+// WithAssigner calls WithAssignerFunc.
+func (mock *Batch) WithAssigner(assigner async.Assigner) async.Async {
+	callInfo := struct {
+		Assigner async.Assigner
+	}{
+		Assigner: assigner,
+	}
+	mock.lockWithAssigner.Lock()
+	mock.calls.WithAssigner = append(mock.calls.WithAssigner, callInfo)
+	mock.lockWithAssigner.Unlock()
+	if mock.WithAssignerFunc == nil {
+		var (
+			asyncOut async.Async
+		)
+		return asyncOut
+	}
+	return mock.WithAssignerFunc(assigner)
+}
+
+// WithAssignerCalls gets all the calls that were made to WithAssigner.
+// Check the length with:
+//
+//	len(mockedAsync.WithAssignerCalls())
+func (mock *Batch) WithAssignerCalls() []struct {
+	Assigner async.Assigner
+} {
+	var calls []struct {
+		Assigner async.Assigner
+	}
+	mock.lockWithAssigner.RLock()
+	calls = mock.calls.WithAssigner
+	mock.lockWithAssigner.RUnlock()
+	return calls
+}
+
+// WithConcurrency calls WithConcurrencyFunc.
+func (mock *Batch) WithConcurrency(n int) async.Async {
+	callInfo := struct {
+		N int
+	}{
+		N: n,
+	}
+	mock.lockWithConcurrency.Lock()
+	mock.calls.WithConcurrency = append(mock.calls.WithConcurrency, callInfo)
+	mock.lockWithConcurrency.Unlock()
+	if mock.WithConcurrencyFunc == nil {
+		var (
+			asyncOut async.Async
+		)
+		return asyncOut
+	}
+	return mock.WithConcurrencyFunc(n)
+}
+
+// WithConcurrencyCalls gets all the calls that were made to WithConcurrency.
+// Check the length with:
+//
+//	len(mockedAsync.WithConcurrencyCalls())
+func (mock *Batch) WithConcurrencyCalls() []struct {
+	N int
+} {
+	var calls []struct {
+		N int
+	}
+	mock.lockWithConcurrency.RLock()
+	calls = mock.calls.WithConcurrency
+	mock.lockWithConcurrency.RUnlock()
+	return calls
+}
+
+// WithCopyResults calls WithCopyResultsFunc.
+func (mock *Batch) WithCopyResults() async.Async {
+	callInfo := struct {
+	}{}
+	mock.lockWithCopyResults.Lock()
+	mock.calls.WithCopyResults = append(mock.calls.WithCopyResults, callInfo)
+	mock.lockWithCopyResults.Unlock()
+	if mock.WithCopyResultsFunc == nil {
+		var (
+			asyncOut async.Async
+		)
+		return asyncOut
+	}
+	return mock.WithCopyResultsFunc()
+}
+
+// WithCopyResultsCalls gets all the calls that were made to WithCopyResults.
+// Check the length with:
+//
+//	len(mockedAsync.WithCopyResultsCalls())
+func (mock *Batch) WithCopyResultsCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockWithCopyResults.RLock()
+	calls = mock.calls.WithCopyResults
+	mock.lockWithCopyResults.RUnlock()
+	return calls
+}
+
+// WithMaxTasks calls WithMaxTasksFunc.
+func (mock *Batch) WithMaxTasks(n int) async.Async {
+	callInfo := struct {
+		N int
+	}{
+		N: n,
+	}
+	mock.lockWithMaxTasks.Lock()
+	mock.calls.WithMaxTasks = append(mock.calls.WithMaxTasks, callInfo)
+	mock.lockWithMaxTasks.Unlock()
+	if mock.WithMaxTasksFunc == nil {
+		var (
+			asyncOut async.Async
+		)
+		return asyncOut
+	}
+	return mock.WithMaxTasksFunc(n)
+}
+
+// WithMaxTasksCalls gets all the calls that were made to WithMaxTasks.
+// Check the length with:
+//
+//	len(mockedAsync.WithMaxTasksCalls())
+func (mock *Batch) WithMaxTasksCalls() []struct {
+	N int
+} {
+	var calls []struct {
+		N int
+	}
+	mock.lockWithMaxTasks.RLock()
+	calls = mock.calls.WithMaxTasks
+	mock.lockWithMaxTasks.RUnlock()
+	return calls
+}
+
+// WithQuorum calls WithQuorumFunc.
+func (mock *Batch) WithQuorum(n int) async.Async {
+	callInfo := struct {
+		N int
+	}{
+		N: n,
+	}
+	mock.lockWithQuorum.Lock()
+	mock.calls.WithQuorum = append(mock.calls.WithQuorum, callInfo)
+	mock.lockWithQuorum.Unlock()
+	if mock.WithQuorumFunc == nil {
+		var (
+			asyncOut async.Async
+		)
+		return asyncOut
+	}
+	return mock.WithQuorumFunc(n)
+}
+
+// WithQuorumCalls gets all the calls that were made to WithQuorum.
+// Check the length with:
+//
+//	len(mockedAsync.WithQuorumCalls())
+func (mock *Batch) WithQuorumCalls() []struct {
+	N int
+} {
+	var calls []struct {
+		N int
+	}
+	mock.lockWithQuorum.RLock()
+	calls = mock.calls.WithQuorum
+	mock.lockWithQuorum.RUnlock()
+	return calls
+}
+
+// WithTimeout calls WithTimeoutFunc.
+func (mock *Batch) WithTimeout(timeout time.Duration) async.Async {
+	callInfo := struct {
+		Timeout time.Duration
+	}{
+		Timeout: timeout,
+	}
+	mock.lockWithTimeout.Lock()
+	mock.calls.WithTimeout = append(mock.calls.WithTimeout, callInfo)
+	mock.lockWithTimeout.Unlock()
+	if mock.WithTimeoutFunc == nil {
+		var (
+			asyncOut async.Async
+		)
+		return asyncOut
+	}
+	return mock.WithTimeoutFunc(timeout)
+}
+
+// WithTimeoutCalls gets all the calls that were made to WithTimeout.
+// Check the length with:
+//
+//	len(mockedAsync.WithTimeoutCalls())
+func (mock *Batch) WithTimeoutCalls() []struct {
+	Timeout time.Duration
+} {
+	var calls []struct {
+		Timeout time.Duration
+	}
+	mock.lockWithTimeout.RLock()
+	calls = mock.calls.WithTimeout
+	mock.lockWithTimeout.RUnlock()
+	return calls
+}
+
+// WithWatchdog calls WithWatchdogFunc.
+func (mock *Batch) WithWatchdog(d time.Duration, onStall func(async.Report)) async.Async {
+	callInfo := struct {
+		D       time.Duration
+		OnStall func(async.Report)
+	}{
+		D:       d,
+		OnStall: onStall,
+	}
+	mock.lockWithWatchdog.Lock()
+	mock.calls.WithWatchdog = append(mock.calls.WithWatchdog, callInfo)
+	mock.lockWithWatchdog.Unlock()
+	if mock.WithWatchdogFunc == nil {
+		var (
+			asyncOut async.Async
+		)
+		return asyncOut
+	}
+	return mock.WithWatchdogFunc(d, onStall)
+}
+
+// WithWatchdogCalls gets all the calls that were made to WithWatchdog.
+// Check the length with:
+//
+//	len(mockedAsync.WithWatchdogCalls())
+func (mock *Batch) WithWatchdogCalls() []struct {
+	D       time.Duration
+	OnStall func(async.Report)
+} {
+	var calls []struct {
+		D       time.Duration
+		OnStall func(async.Report)
+	}
+	mock.lockWithWatchdog.RLock()
+	calls = mock.calls.WithWatchdog
+	mock.lockWithWatchdog.RUnlock()
+	return calls
+}
+
+// Ensure, that Builder does implement async.BatchBuilder.
+// If this is not the case, regenerate this file with moq.
+var _ async.BatchBuilder[any] = &Builder[any]{}
+
+// Builder is a mock implementation of async.BatchBuilder.
+//
+//	func TestSomethingThatUsesBatchBuilder(t *testing.T) {
+//
+//		// make and configure a mocked async.BatchBuilder
+//		mockedBatchBuilder := &Builder{
+//			AllowSharedDestFunc: func() B {
+//				panic("mock out the AllowSharedDest method")
+//			},
+//			CriticalFunc: func() B {
+//				panic("mock out the Critical method")
+//			},
+//			PhaseFunc: func() B {
+//				panic("mock out the Phase method")
+//			},
+//			StrictFunc: func() B {
+//				panic("mock out the Strict method")
+//			},
+//			TaskFunc: func(fn async.AsyncFunc) B {
+//				panic("mock out the Task method")
+//			},
+//			TaskDelayedFunc: func(delay time.Duration, fn async.AsyncFunc, opts ...async.ScheduleOption) B {
+//				panic("mock out the TaskDelayed method")
+//			},
+//			TaskIfFunc: func(pred func(ctx context.Context) bool, fn async.AsyncFunc) B {
+//				panic("mock out the TaskIf method")
+//			},
+//			TaskJSONFunc: func(dest any, fn func(ctx context.Context) ([]byte, error)) B {
+//				panic("mock out the TaskJSON method")
+//			},
+//			TaskNamedFunc: func(name string, fn async.AsyncFunc) B {
+//				panic("mock out the TaskNamed method")
+//			},
+//			TaskOnFunc: func(name string, fn async.AsyncFunc) B {
+//				panic("mock out the TaskOn method")
+//			},
+//			TaskWithTimeoutFunc: func(timeout time.Duration, fn async.AsyncFunc) B {
+//				panic("mock out the TaskWithTimeout method")
+//			},
+//			WithAssignerFunc: func(assigner async.Assigner) B {
+//				panic("mock out the WithAssigner method")
+//			},
+//			WithConcurrencyFunc: func(n int) B {
+//				panic("mock out the WithConcurrency method")
+//			},
+//			WithCopyResultsFunc: func() B {
+//				panic("mock out the WithCopyResults method")
+//			},
+//			WithMaxTasksFunc: func(n int) B {
+//				panic("mock out the WithMaxTasks method")
+//			},
+//			WithQuorumFunc: func(n int) B {
+//				panic("mock out the WithQuorum method")
+//			},
+//			WithTimeoutFunc: func(timeout time.Duration) B {
+//				panic("mock out the WithTimeout method")
+//			},
+//			WithWatchdogFunc: func(d time.Duration, onStall func(async.Report)) B {
+//				panic("mock out the WithWatchdog method")
+//			},
+//		}
+//
+//		// use mockedBatchBuilder in code that requires async.BatchBuilder
+//		// and then make assertions.
+//
+//	}
+type Builder[B any] struct {
+	// AllowSharedDestFunc mocks the AllowSharedDest method.
+	AllowSharedDestFunc func() B
+
+	// CriticalFunc mocks the Critical method.
+	CriticalFunc func() B
+
+	// PhaseFunc mocks the Phase method.
+	PhaseFunc func() B
+
+	// StrictFunc mocks the Strict method.
+	StrictFunc func() B
+
+	// TaskFunc mocks the Task method.
+	TaskFunc func(fn async.AsyncFunc) B
+
+	// TaskDelayedFunc mocks the TaskDelayed method.
+	TaskDelayedFunc func(delay time.Duration, fn async.AsyncFunc, opts ...async.ScheduleOption) B
+
+	// TaskIfFunc mocks the TaskIf method.
+	TaskIfFunc func(pred func(ctx context.Context) bool, fn async.AsyncFunc) B
+
+	// TaskJSONFunc mocks the TaskJSON method.
+	TaskJSONFunc func(dest any, fn func(ctx context.Context) ([]byte, error)) B
+
+	// TaskNamedFunc mocks the TaskNamed method.
+	TaskNamedFunc func(name string, fn async.AsyncFunc) B
+
+	// TaskOnFunc mocks the TaskOn method.
+	TaskOnFunc func(name string, fn async.AsyncFunc) B
+
+	// TaskWithTimeoutFunc mocks the TaskWithTimeout method.
+	TaskWithTimeoutFunc func(timeout time.Duration, fn async.AsyncFunc) B
+
+	// WithAssignerFunc mocks the WithAssigner method.
+	WithAssignerFunc func(assigner async.Assigner) B
+
+	// WithConcurrencyFunc mocks the WithConcurrency method.
+	WithConcurrencyFunc func(n int) B
+
+	// WithCopyResultsFunc mocks the WithCopyResults method.
+	WithCopyResultsFunc func() B
+
+	// WithMaxTasksFunc mocks the WithMaxTasks method.
+	WithMaxTasksFunc func(n int) B
+
+	// WithQuorumFunc mocks the WithQuorum method.
+	WithQuorumFunc func(n int) B
+
+	// WithTimeoutFunc mocks the WithTimeout method.
+	WithTimeoutFunc func(timeout time.Duration) B
+
+	// WithWatchdogFunc mocks the WithWatchdog method.
+	WithWatchdogFunc func(d time.Duration, onStall func(async.Report)) B
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// AllowSharedDest holds details about calls to the AllowSharedDest method.
+		AllowSharedDest []struct {
+		}
+		// Critical holds details about calls to the Critical method.
+		Critical []struct {
+		}
+		// Phase holds details about calls to the Phase method.
+		Phase []struct {
+		}
+		// Strict holds details about calls to the Strict method.
+		Strict []struct {
+		}
+		// Task holds details about calls to the Task method.
+		Task []struct {
+			// Fn is the fn argument value.
+			Fn async.AsyncFunc
+		}
+		// TaskDelayed holds details about calls to the TaskDelayed method.
+		TaskDelayed []struct {
+			// Delay is the delay argument value.
+			Delay time.Duration
+			// Fn is the fn argument value.
+			Fn async.AsyncFunc
+			// Opts is the opts argument value.
+			Opts []async.ScheduleOption
+		}
+		// TaskIf holds details about calls to the TaskIf method.
+		TaskIf []struct {
+			// Pred is the pred argument value.
+			Pred func(ctx context.Context) bool
+			// Fn is the fn argument value.
+			Fn async.AsyncFunc
+		}
+		// TaskJSON holds details about calls to the TaskJSON method.
+		TaskJSON []struct {
+			// Dest is the dest argument value.
+			Dest any
+			// Fn is the fn argument value.
+			Fn func(ctx context.Context) ([]byte, error)
+		}
+		// TaskNamed holds details about calls to the TaskNamed method.
+		TaskNamed []struct {
+			// Name is the name argument value.
+			Name string
+			// Fn is the fn argument value.
+			Fn async.AsyncFunc
+		}
+		// TaskOn holds details about calls to the TaskOn method.
+		TaskOn []struct {
+			// Name is the name argument value.
+			Name string
+			// Fn is the fn argument value.
+			Fn async.AsyncFunc
+		}
+		// TaskWithTimeout holds details about calls to the TaskWithTimeout method.
+		TaskWithTimeout []struct {
+			// Timeout is the timeout argument value.
+			Timeout time.Duration
+			// Fn is the fn argument value.
+			Fn async.AsyncFunc
+		}
+		// WithAssigner holds details about calls to the WithAssigner method.
+		WithAssigner []struct {
+			// Assigner is the assigner argument value.
+			Assigner async.Assigner
+		}
+		// WithConcurrency holds details about calls to the WithConcurrency method.
+		WithConcurrency []struct {
+			// N is the n argument value.
+			N int
+		}
+		// WithCopyResults holds details about calls to the WithCopyResults method.
+		WithCopyResults []struct {
+		}
+		// WithMaxTasks holds details about calls to the WithMaxTasks method.
+		WithMaxTasks []struct {
+			// N is the n argument value.
+			N int
+		}
+		// WithQuorum holds details about calls to the WithQuorum method.
+		WithQuorum []struct {
+			// N is the n argument value.
+			N int
+		}
+		// WithTimeout holds details about calls to the WithTimeout method.
+		WithTimeout []struct {
+			// Timeout is the timeout argument value.
+			Timeout time.Duration
+		}
+		// WithWatchdog holds details about calls to the WithWatchdog method.
+		WithWatchdog []struct {
+			// D is the d argument value.
+			D time.Duration
+			// OnStall is the onStall argument value.
+			OnStall func(async.Report)
+		}
+	}
+	lockAllowSharedDest sync.RWMutex
+	lockCritical        sync.RWMutex
+	lockPhase           sync.RWMutex
+	lockStrict          sync.RWMutex
+	lockTask            sync.RWMutex
+	lockTaskDelayed     sync.RWMutex
+	lockTaskIf          sync.RWMutex
+	lockTaskJSON        sync.RWMutex
+	lockTaskNamed       sync.RWMutex
+	lockTaskOn          sync.RWMutex
+	lockTaskWithTimeout sync.RWMutex
+	lockWithAssigner    sync.RWMutex
+	lockWithConcurrency sync.RWMutex
+	lockWithCopyResults sync.RWMutex
+	lockWithMaxTasks    sync.RWMutex
+	lockWithQuorum      sync.RWMutex
+	lockWithTimeout     sync.RWMutex
+	lockWithWatchdog    sync.RWMutex
+}
+
+// AllowSharedDest calls AllowSharedDestFunc.
+func (mock *Builder[B]) AllowSharedDest() B {
+	callInfo := struct {
+	}{}
+	mock.lockAllowSharedDest.Lock()
+	mock.calls.AllowSharedDest = append(mock.calls.AllowSharedDest, callInfo)
+	mock.lockAllowSharedDest.Unlock()
+	if mock.AllowSharedDestFunc == nil {
+		var (
+			vOut B
+		)
+		return vOut
+	}
+	return mock.AllowSharedDestFunc()
+}
+
+// AllowSharedDestCalls gets all the calls that were made to AllowSharedDest.
+// Check the length with:
+//
+//	len(mockedBatchBuilder.AllowSharedDestCalls())
+func (mock *Builder[B]) AllowSharedDestCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockAllowSharedDest.RLock()
+	calls = mock.calls.AllowSharedDest
+	mock.lockAllowSharedDest.RUnlock()
+	return calls
+}
+
+// Critical calls CriticalFunc.
+func (mock *Builder[B]) Critical() B {
+	callInfo := struct {
+	}{}
+	mock.lockCritical.Lock()
+	mock.calls.Critical = append(mock.calls.Critical, callInfo)
+	mock.lockCritical.Unlock()
+	if mock.CriticalFunc == nil {
+		var (
+			vOut B
+		)
+		return vOut
+	}
+	return mock.CriticalFunc()
+}
+
+// CriticalCalls gets all the calls that were made to Critical.
+// Check the length with:
+//
+//	len(mockedBatchBuilder.CriticalCalls())
+func (mock *Builder[B]) CriticalCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockCritical.RLock()
+	calls = mock.calls.Critical
+	mock.lockCritical.RUnlock()
+	return calls
+}
+
+// Phase calls PhaseFunc.
+func (mock *Builder[B]) Phase() B {
+	callInfo := struct {
+	}{}
+	mock.lockPhase.Lock()
+	mock.calls.Phase = append(mock.calls.Phase, callInfo)
+	mock.lockPhase.Unlock()
+	if mock.PhaseFunc == nil {
+		var (
+			vOut B
+		)
+		return vOut
+	}
+	return mock.PhaseFunc()
+}
+
+// PhaseCalls gets all the calls that were made to Phase.
+// Check the length with:
+//
+//	len(mockedBatchBuilder.PhaseCalls())
+func (mock *Builder[B]) PhaseCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockPhase.RLock()
+	calls = mock.calls.Phase
+	mock.lockPhase.RUnlock()
+	return calls
+}
+
+// Strict calls StrictFunc.
+func (mock *Builder[B]) Strict() B {
+	callInfo := struct {
+	}{}
+	mock.lockStrict.Lock()
+	mock.calls.Strict = append(mock.calls.Strict, callInfo)
+	mock.lockStrict.Unlock()
+	if mock.StrictFunc == nil {
+		var (
+			vOut B
+		)
+		return vOut
+	}
+	return mock.StrictFunc()
+}
+
+// StrictCalls gets all the calls that were made to Strict.
+// Check the length with:
+//
+//	len(mockedBatchBuilder.StrictCalls())
+func (mock *Builder[B]) StrictCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockStrict.RLock()
+	calls = mock.calls.Strict
+	mock.lockStrict.RUnlock()
+	return calls
+}
+
+// Task calls TaskFunc.
+func (mock *Builder[B]) Task(fn async.AsyncFunc) B {
+	callInfo := struct {
+		Fn async.AsyncFunc
+	}{
+		Fn: fn,
+	}
+	mock.lockTask.Lock()
+	mock.calls.Task = append(mock.calls.Task, callInfo)
+	mock.lockTask.Unlock()
+	if mock.TaskFunc == nil {
+		var (
+			vOut B
+		)
+		return vOut
+	}
+	return mock.TaskFunc(fn)
+}
+
+// TaskCalls gets all the calls that were made to Task.
+// Check the length with:
+//
+//	len(mockedBatchBuilder.TaskCalls())
+func (mock *Builder[B]) TaskCalls() []struct {
+	Fn async.AsyncFunc
+} {
+	var calls []struct {
+		Fn async.AsyncFunc
+	}
+	mock.lockTask.RLock()
+	calls = mock.calls.Task
+	mock.lockTask.RUnlock()
+	return calls
+}
+
+// TaskDelayed calls TaskDelayedFunc.
+func (mock *Builder[B]) TaskDelayed(delay time.Duration, fn async.AsyncFunc, opts ...async.ScheduleOption) B {
+	callInfo := struct {
+		Delay time.Duration
+		Fn    async.AsyncFunc
+		Opts  []async.ScheduleOption
+	}{
+		Delay: delay,
+		Fn:    fn,
+		Opts:  opts,
+	}
+	mock.lockTaskDelayed.Lock()
+	mock.calls.TaskDelayed = append(mock.calls.TaskDelayed, callInfo)
+	mock.lockTaskDelayed.Unlock()
+	if mock.TaskDelayedFunc == nil {
+		var (
+			vOut B
+		)
+		return vOut
+	}
+	return mock.TaskDelayedFunc(delay, fn, opts...)
+}
+
+// TaskDelayedCalls gets all the calls that were made to TaskDelayed.
+// Check the length with:
+//
+//	len(mockedBatchBuilder.TaskDelayedCalls())
+func (mock *Builder[B]) TaskDelayedCalls() []struct {
+	Delay time.Duration
+	Fn    async.AsyncFunc
+	Opts  []async.ScheduleOption
+} {
+	var calls []struct {
+		Delay time.Duration
+		Fn    async.AsyncFunc
+		Opts  []async.ScheduleOption
+	}
+	mock.lockTaskDelayed.RLock()
+	calls = mock.calls.TaskDelayed
+	mock.lockTaskDelayed.RUnlock()
+	return calls
+}
+
+// TaskIf calls TaskIfFunc.
+func (mock *Builder[B]) TaskIf(pred func(ctx context.Context) bool, fn async.AsyncFunc) B {
+	callInfo := struct {
+		Pred func(ctx context.Context) bool
+		Fn   async.AsyncFunc
+	}{
+		Pred: pred,
+		Fn:   fn,
+	}
+	mock.lockTaskIf.Lock()
+	mock.calls.TaskIf = append(mock.calls.TaskIf, callInfo)
+	mock.lockTaskIf.Unlock()
+	if mock.TaskIfFunc == nil {
+		var (
+			vOut B
+		)
+		return vOut
+	}
+	return mock.TaskIfFunc(pred, fn)
+}
+
+// TaskIfCalls gets all the calls that were made to TaskIf.
+// Check the length with:
+//
+//	len(mockedBatchBuilder.TaskIfCalls())
+func (mock *Builder[B]) TaskIfCalls() []struct {
+	Pred func(ctx context.Context) bool
+	Fn   async.AsyncFunc
+} {
+	var calls []struct {
+		Pred func(ctx context.Context) bool
+		Fn   async.AsyncFunc
+	}
+	mock.lockTaskIf.RLock()
+	calls = mock.calls.TaskIf
+	mock.lockTaskIf.RUnlock()
+	return calls
+}
+
+// TaskJSON calls TaskJSONFunc.
+func (mock *Builder[B]) TaskJSON(dest any, fn func(ctx context.Context) ([]byte, error)) B {
+	callInfo := struct {
+		Dest any
+		Fn   func(ctx context.Context) ([]byte, error)
+	}{
+		Dest: dest,
+		Fn:   fn,
+	}
+	mock.lockTaskJSON.Lock()
+	mock.calls.TaskJSON = append(mock.calls.TaskJSON, callInfo)
+	mock.lockTaskJSON.Unlock()
+	if mock.TaskJSONFunc == nil {
+		var (
+			vOut B
+		)
+		return vOut
+	}
+	return mock.TaskJSONFunc(dest, fn)
+}
+
+// TaskJSONCalls gets all the calls that were made to TaskJSON.
+// Check the length with:
+//
+//	len(mockedBatchBuilder.TaskJSONCalls())
+func (mock *Builder[B]) TaskJSONCalls() []struct {
+	Dest any
+	Fn   func(ctx context.Context) ([]byte, error)
+} {
+	var calls []struct {
+		Dest any
+		Fn   func(ctx context.Context) ([]byte, error)
+	}
+	mock.lockTaskJSON.RLock()
+	calls = mock.calls.TaskJSON
+	mock.lockTaskJSON.RUnlock()
+	return calls
+}
+
+// TaskNamed calls TaskNamedFunc.
+func (mock *Builder[B]) TaskNamed(name string, fn async.AsyncFunc) B {
+	callInfo := struct {
+		Name string
+		Fn   async.AsyncFunc
+	}{
+		Name: name,
+		Fn:   fn,
+	}
+	mock.lockTaskNamed.Lock()
+	mock.calls.TaskNamed = append(mock.calls.TaskNamed, callInfo)
+	mock.lockTaskNamed.Unlock()
+	if mock.TaskNamedFunc == nil {
+		var (
+			vOut B
+		)
+		return vOut
+	}
+	return mock.TaskNamedFunc(name, fn)
+}
+
+// TaskNamedCalls gets all the calls that were made to TaskNamed.
+// Check the length with:
+//
+//	len(mockedBatchBuilder.TaskNamedCalls())
+func (mock *Builder[B]) TaskNamedCalls() []struct {
+	Name string
+	Fn   async.AsyncFunc
+} {
+	var calls []struct {
+		Name string
+		Fn   async.AsyncFunc
+	}
+	mock.lockTaskNamed.RLock()
+	calls = mock.calls.TaskNamed
+	mock.lockTaskNamed.RUnlock()
+	return calls
+}
+
+// TaskOn calls TaskOnFunc.
+func (mock *Builder[B]) TaskOn(name string, fn async.AsyncFunc) B {
+	callInfo := struct {
+		Name string
+		Fn   async.AsyncFunc
+	}{
+		Name: name,
+		Fn:   fn,
+	}
+	mock.lockTaskOn.Lock()
+	mock.calls.TaskOn = append(mock.calls.TaskOn, callInfo)
+	mock.lockTaskOn.Unlock()
+	if mock.TaskOnFunc == nil {
+		var (
+			vOut B
+		)
+		return vOut
+	}
+	return mock.TaskOnFunc(name, fn)
+}
+
+// TaskOnCalls gets all the calls that were made to TaskOn.
+// Check the length with:
+//
+//	len(mockedBatchBuilder.TaskOnCalls())
+func (mock *Builder[B]) TaskOnCalls() []struct {
+	Name string
+	Fn   async.AsyncFunc
+} {
+	var calls []struct {
+		Name string
+		Fn   async.AsyncFunc
+	}
+	mock.lockTaskOn.RLock()
+	calls = mock.calls.TaskOn
+	mock.lockTaskOn.RUnlock()
+	return calls
+}
+
+// TaskWithTimeout calls TaskWithTimeoutFunc.
+func (mock *Builder[B]) TaskWithTimeout(timeout time.Duration, fn async.AsyncFunc) B {
+	callInfo := struct {
+		Timeout time.Duration
+		Fn      async.AsyncFunc
+	}{
+		Timeout: timeout,
+		Fn:      fn,
+	}
+	mock.lockTaskWithTimeout.Lock()
+	mock.calls.TaskWithTimeout = append(mock.calls.TaskWithTimeout, callInfo)
+	mock.lockTaskWithTimeout.Unlock()
+	if mock.TaskWithTimeoutFunc == nil {
+		var (
+			vOut B
+		)
+		return vOut
+	}
+	return mock.TaskWithTimeoutFunc(timeout, fn)
+}
+
+// TaskWithTimeoutCalls gets all the calls that were made to TaskWithTimeout.
+// Check the length with:
+//
+//	len(mockedBatchBuilder.TaskWithTimeoutCalls())
+func (mock *Builder[B]) TaskWithTimeoutCalls() []struct {
+	Timeout time.Duration
+	Fn      async.AsyncFunc
+} {
+	var calls []struct {
+		Timeout time.Duration
+		Fn      async.AsyncFunc
+	}
+	mock.lockTaskWithTimeout.RLock()
+	calls = mock.calls.TaskWithTimeout
+	mock.lockTaskWithTimeout.RUnlock()
+	return calls
+}
+
+// WithAssigner calls WithAssignerFunc.
+func (mock *Builder[B]) WithAssigner(assigner async.Assigner) B {
+	callInfo := struct {
+		Assigner async.Assigner
+	}{
+		Assigner: assigner,
+	}
+	mock.lockWithAssigner.Lock()
+	mock.calls.WithAssigner = append(mock.calls.WithAssigner, callInfo)
+	mock.lockWithAssigner.Unlock()
+	if mock.WithAssignerFunc == nil {
+		var (
+			vOut B
+		)
+		return vOut
+	}
+	return mock.WithAssignerFunc(assigner)
+}
+
+// WithAssignerCalls gets all the calls that were made to WithAssigner.
+// Check the length with:
+//
+//	len(mockedBatchBuilder.WithAssignerCalls())
+func (mock *Builder[B]) WithAssignerCalls() []struct {
+	Assigner async.Assigner
+} {
+	var calls []struct {
+		Assigner async.Assigner
+	}
+	mock.lockWithAssigner.RLock()
+	calls = mock.calls.WithAssigner
+	mock.lockWithAssigner.RUnlock()
+	return calls
+}
+
+// WithConcurrency calls WithConcurrencyFunc.
+func (mock *Builder[B]) WithConcurrency(n int) B {
+	callInfo := struct {
+		N int
+	}{
+		N: n,
+	}
+	mock.lockWithConcurrency.Lock()
+	mock.calls.WithConcurrency = append(mock.calls.WithConcurrency, callInfo)
+	mock.lockWithConcurrency.Unlock()
+	if mock.WithConcurrencyFunc == nil {
+		var (
+			vOut B
+		)
+		return vOut
+	}
+	return mock.WithConcurrencyFunc(n)
+}
+
+// WithConcurrencyCalls gets all the calls that were made to WithConcurrency.
+// Check the length with:
+//
+//	len(mockedBatchBuilder.WithConcurrencyCalls())
+func (mock *Builder[B]) WithConcurrencyCalls() []struct {
+	N int
+} {
+	var calls []struct {
+		N int
+	}
+	mock.lockWithConcurrency.RLock()
+	calls = mock.calls.WithConcurrency
+	mock.lockWithConcurrency.RUnlock()
+	return calls
+}
+
+// WithCopyResults calls WithCopyResultsFunc.
+func (mock *Builder[B]) WithCopyResults() B {
+	callInfo := struct {
+	}{}
+	mock.lockWithCopyResults.Lock()
+	mock.calls.WithCopyResults = append(mock.calls.WithCopyResults, callInfo)
+	mock.lockWithCopyResults.Unlock()
+	if mock.WithCopyResultsFunc == nil {
+		var (
+			vOut B
+		)
+		return vOut
+	}
+	return mock.WithCopyResultsFunc()
+}
+
+// WithCopyResultsCalls gets all the calls that were made to WithCopyResults.
+// Check the length with:
+//
+//	len(mockedBatchBuilder.WithCopyResultsCalls())
+func (mock *Builder[B]) WithCopyResultsCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockWithCopyResults.RLock()
+	calls = mock.calls.WithCopyResults
+	mock.lockWithCopyResults.RUnlock()
+	return calls
+}
+
+// WithMaxTasks calls WithMaxTasksFunc.
+func (mock *Builder[B]) WithMaxTasks(n int) B {
+	callInfo := struct {
+		N int
+	}{
+		N: n,
+	}
+	mock.lockWithMaxTasks.Lock()
+	mock.calls.WithMaxTasks = append(mock.calls.WithMaxTasks, callInfo)
+	mock.lockWithMaxTasks.Unlock()
+	if mock.WithMaxTasksFunc == nil {
+		var (
+			vOut B
+		)
+		return vOut
+	}
+	return mock.WithMaxTasksFunc(n)
+}
+
+// WithMaxTasksCalls gets all the calls that were made to WithMaxTasks.
+// Check the length with:
+//
+//	len(mockedBatchBuilder.WithMaxTasksCalls())
+func (mock *Builder[B]) WithMaxTasksCalls() []struct {
+	N int
+} {
+	var calls []struct {
+		N int
+	}
+	mock.lockWithMaxTasks.RLock()
+	calls = mock.calls.WithMaxTasks
+	mock.lockWithMaxTasks.RUnlock()
+	return calls
+}
+
+// WithQuorum calls WithQuorumFunc.
+func (mock *Builder[B]) WithQuorum(n int) B {
+	callInfo := struct {
+		N int
+	}{
+		N: n,
+	}
+	mock.lockWithQuorum.Lock()
+	mock.calls.WithQuorum = append(mock.calls.WithQuorum, callInfo)
+	mock.lockWithQuorum.Unlock()
+	if mock.WithQuorumFunc == nil {
+		var (
+			vOut B
+		)
+		return vOut
+	}
+	return mock.WithQuorumFunc(n)
+}
+
+// WithQuorumCalls gets all the calls that were made to WithQuorum.
+// Check the length with:
+//
+//	len(mockedBatchBuilder.WithQuorumCalls())
+func (mock *Builder[B]) WithQuorumCalls() []struct {
+	N int
+} {
+	var calls []struct {
+		N int
+	}
+	mock.lockWithQuorum.RLock()
+	calls = mock.calls.WithQuorum
+	mock.lockWithQuorum.RUnlock()
+	return calls
+}
+
+// WithTimeout calls WithTimeoutFunc.
+func (mock *Builder[B]) WithTimeout(timeout time.Duration) B {
+	callInfo := struct {
+		Timeout time.Duration
+	}{
+		Timeout: timeout,
+	}
+	mock.lockWithTimeout.Lock()
+	mock.calls.WithTimeout = append(mock.calls.WithTimeout, callInfo)
+	mock.lockWithTimeout.Unlock()
+	if mock.WithTimeoutFunc == nil {
+		var (
+			vOut B
+		)
+		return vOut
+	}
+	return mock.WithTimeoutFunc(timeout)
+}
+
+// WithTimeoutCalls gets all the calls that were made to WithTimeout.
+// Check the length with:
+//
+//	len(mockedBatchBuilder.WithTimeoutCalls())
+func (mock *Builder[B]) WithTimeoutCalls() []struct {
+	Timeout time.Duration
+} {
+	var calls []struct {
+		Timeout time.Duration
+	}
+	mock.lockWithTimeout.RLock()
+	calls = mock.calls.WithTimeout
+	mock.lockWithTimeout.RUnlock()
+	return calls
+}
+
+// WithWatchdog calls WithWatchdogFunc.
+func (mock *Builder[B]) WithWatchdog(d time.Duration, onStall func(async.Report)) B {
+	callInfo := struct {
+		D       time.Duration
+		OnStall func(async.Report)
+	}{
+		D:       d,
+		OnStall: onStall,
+	}
+	mock.lockWithWatchdog.Lock()
+	mock.calls.WithWatchdog = append(mock.calls.WithWatchdog, callInfo)
+	mock.lockWithWatchdog.Unlock()
+	if mock.WithWatchdogFunc == nil {
+		var (
+			vOut B
+		)
+		return vOut
+	}
+	return mock.WithWatchdogFunc(d, onStall)
+}
+
+// WithWatchdogCalls gets all the calls that were made to WithWatchdog.
+// Check the length with:
+//
+//	len(mockedBatchBuilder.WithWatchdogCalls())
+func (mock *Builder[B]) WithWatchdogCalls() []struct {
+	D       time.Duration
+	OnStall func(async.Report)
+} {
+	var calls []struct {
+		D       time.Duration
+		OnStall func(async.Report)
+	}
+	mock.lockWithWatchdog.RLock()
+	calls = mock.calls.WithWatchdog
+	mock.lockWithWatchdog.RUnlock()
+	return calls
+}
+
+// Ensure, that Executor does implement async.BatchExecutor.
+// If this is not the case, regenerate this file with moq.
+var _ async.BatchExecutor = &Executor{}
+
+// Executor is a mock implementation of async.BatchExecutor.
+//
+//	func TestSomethingThatUsesBatchExecutor(t *testing.T) {
+//
+//		// make and configure a mocked async.BatchExecutor
+//		mockedBatchExecutor := &Executor{
+//			ErrFunc: func() error {
+//				panic("mock out the Err method")
+//			},
+//			GoFunc: func(ctx context.Context) error {
+//				panic("mock out the Go method")
+//			},
+//			GoSettledFunc: func(ctx context.Context) ([]async.TaskReport, error) {
+//				panic("mock out the GoSettled method")
+//			},
+//			ReportFunc: func() async.Report {
+//				panic("mock out the Report method")
+//			},
+//		}
+//
+//		// use mockedBatchExecutor in code that requires async.BatchExecutor
+//		// and then make assertions.
+//
+//	}
+type Executor struct {
+	// ErrFunc mocks the Err method.
+	ErrFunc func() error
+
+	// GoFunc mocks the Go method.
+	GoFunc func(ctx context.Context) error
+
+	// GoSettledFunc mocks the GoSettled method.
+	GoSettledFunc func(ctx context.Context) ([]async.TaskReport, error)
+
+	// ReportFunc mocks the Report method.
+	ReportFunc func() async.Report
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Err holds details about calls to the Err method.
+		Err []struct {
+		}
+		// Go holds details about calls to the Go method.
+		Go []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// GoSettled holds details about calls to the GoSettled method.
+		GoSettled []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// Report holds details about calls to the Report method.
+		Report []struct {
+		}
+	}
+	lockErr       sync.RWMutex
+	lockGo        sync.RWMutex
+	lockGoSettled sync.RWMutex
+	lockReport    sync.RWMutex
+}
+
+// Err calls ErrFunc.
+func (mock *Executor) Err() error {
+	callInfo := struct {
+	}{}
+	mock.lockErr.Lock()
+	mock.calls.Err = append(mock.calls.Err, callInfo)
+	mock.lockErr.Unlock()
+	if mock.ErrFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.ErrFunc()
+}
+
+// ErrCalls gets all the calls that were made to Err.
+// Check the length with:
+//
+//	len(mockedBatchExecutor.ErrCalls())
+func (mock *Executor) ErrCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockErr.RLock()
+	calls = mock.calls.Err
+	mock.lockErr.RUnlock()
+	return calls
+}
+
+// Go calls GoFunc.
+func (mock *Executor) Go(ctx context.Context) error {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGo.Lock()
+	mock.calls.Go = append(mock.calls.Go, callInfo)
+	mock.lockGo.Unlock()
+	if mock.GoFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.GoFunc(ctx)
+}
+
+// GoCalls gets all the calls that were made to Go.
+// Check the length with:
+//
+//	len(mockedBatchExecutor.GoCalls())
+func (mock *Executor) GoCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGo.RLock()
+	calls = mock.calls.Go
+	mock.lockGo.RUnlock()
+	return calls
+}
+
+// GoSettled calls GoSettledFunc.
+func (mock *Executor) GoSettled(ctx context.Context) ([]async.TaskReport, error) {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGoSettled.Lock()
+	mock.calls.GoSettled = append(mock.calls.GoSettled, callInfo)
+	mock.lockGoSettled.Unlock()
+	if mock.GoSettledFunc == nil {
+		var (
+			taskReportsOut []async.TaskReport
+			errOut         error
+		)
+		return taskReportsOut, errOut
+	}
+	return mock.GoSettledFunc(ctx)
+}
+
+// GoSettledCalls gets all the calls that were made to GoSettled.
+// Check the length with:
+//
+//	len(mockedBatchExecutor.GoSettledCalls())
+func (mock *Executor) GoSettledCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGoSettled.RLock()
+	calls = mock.calls.GoSettled
+	mock.lockGoSettled.RUnlock()
+	return calls
+}
+
+// Report calls ReportFunc.
+func (mock *Executor) Report() async.Report {
+	callInfo := struct {
+	}{}
+	mock.lockReport.Lock()
+	mock.calls.Report = append(mock.calls.Report, callInfo)
+	mock.lockReport.Unlock()
+	if mock.ReportFunc == nil {
+		var (
+			reportOut async.Report
+		)
+		return reportOut
+	}
+	return mock.ReportFunc()
+}
+
+// ReportCalls gets all the calls that were made to Report.
+// Check the length with:
+//
+//	len(mockedBatchExecutor.ReportCalls())
+func (mock *Executor) ReportCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockReport.RLock()
+	calls = mock.calls.Report
+	mock.lockReport.RUnlock()
+	return calls
+}
+
+// Ensure, that Pool does implement async.WorkerPool.
+// If this is not the case, regenerate this file with moq.
+var _ async.WorkerPool = &Pool{}
+
+// Pool is a mock implementation of async.WorkerPool.
+//
+//	func TestSomethingThatUsesWorkerPool(t *testing.T) {
+//
+//		// make and configure a mocked async.WorkerPool
+//		mockedWorkerPool := &Pool{
+//			RunInAsyncFunc: func() async.Async {
+//				panic("mock out the RunInAsync method")
+//			},
+//			RunInAsyncNFunc: func(n int) async.Async {
+//				panic("mock out the RunInAsyncN method")
+//			},
+//			ShutdownFunc: func(ctx context.Context) error {
+//				panic("mock out the Shutdown method")
+//			},
+//			StatsFunc: func() async.PoolStats {
+//				panic("mock out the Stats method")
+//			},
+//			SubmitFunc: func(ctx context.Context, fn async.AsyncFunc) error {
+//				panic("mock out the Submit method")
+//			},
+//			SubmitKeyedFunc: func(ctx context.Context, key string, fn async.AsyncFunc) error {
+//				panic("mock out the SubmitKeyed method")
+//			},
+//			SubmitWaitFunc: func(ctx context.Context, fn async.AsyncFunc) error {
+//				panic("mock out the SubmitWait method")
+//			},
+//		}
+//
+//		// use mockedWorkerPool in code that requires async.WorkerPool
+//		// and then make assertions.
+//
+//	}
+type Pool struct {
+	// RunInAsyncFunc mocks the RunInAsync method.
+	RunInAsyncFunc func() async.Async
+
+	// RunInAsyncNFunc mocks the RunInAsyncN method.
+	RunInAsyncNFunc func(n int) async.Async
+
+	// ShutdownFunc mocks the Shutdown method.
+	ShutdownFunc func(ctx context.Context) error
+
+	// StatsFunc mocks the Stats method.
+	StatsFunc func() async.PoolStats
+
+	// SubmitFunc mocks the Submit method.
+	SubmitFunc func(ctx context.Context, fn async.AsyncFunc) error
+
+	// SubmitKeyedFunc mocks the SubmitKeyed method.
+	SubmitKeyedFunc func(ctx context.Context, key string, fn async.AsyncFunc) error
+
+	// SubmitWaitFunc mocks the SubmitWait method.
+	SubmitWaitFunc func(ctx context.Context, fn async.AsyncFunc) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// RunInAsync holds details about calls to the RunInAsync method.
+		RunInAsync []struct {
+		}
+		// RunInAsyncN holds details about calls to the RunInAsyncN method.
+		RunInAsyncN []struct {
+			// N is the n argument value.
+			N int
+		}
+		// Shutdown holds details about calls to the Shutdown method.
+		Shutdown []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// Stats holds details about calls to the Stats method.
+		Stats []struct {
+		}
+		// Submit holds details about calls to the Submit method.
+		Submit []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Fn is the fn argument value.
+			Fn async.AsyncFunc
+		}
+		// SubmitKeyed holds details about calls to the SubmitKeyed method.
+		SubmitKeyed []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Key is the key argument value.
+			Key string
+			// Fn is the fn argument value.
+			Fn async.AsyncFunc
+		}
+		// SubmitWait holds details about calls to the SubmitWait method.
+		SubmitWait []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Fn is the fn argument value.
+			Fn async.AsyncFunc
+		}
+	}
+	lockRunInAsync  sync.RWMutex
+	lockRunInAsyncN sync.RWMutex
+	lockShutdown    sync.RWMutex
+	lockStats       sync.RWMutex
+	lockSubmit      sync.RWMutex
+	lockSubmitKeyed sync.RWMutex
+	lockSubmitWait  sync.RWMutex
+}
+
+// RunInAsync calls RunInAsyncFunc.
+func (mock *Pool) RunInAsync() async.Async {
+	callInfo := struct {
+	}{}
+	mock.lockRunInAsync.Lock()
+	mock.calls.RunInAsync = append(mock.calls.RunInAsync, callInfo)
+	mock.lockRunInAsync.Unlock()
+	if mock.RunInAsyncFunc == nil {
+		var (
+			asyncOut async.Async
+		)
+		return asyncOut
+	}
+	return mock.RunInAsyncFunc()
+}
+
+// RunInAsyncCalls gets all the calls that were made to RunInAsync.
+// Check the length with:
+//
+//	len(mockedWorkerPool.RunInAsyncCalls())
+func (mock *Pool) RunInAsyncCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockRunInAsync.RLock()
+	calls = mock.calls.RunInAsync
+	mock.lockRunInAsync.RUnlock()
+	return calls
+}
+
+// RunInAsyncN calls RunInAsyncNFunc.
+func (mock *Pool) RunInAsyncN(n int) async.Async {
+	callInfo := struct {
+		N int
+	}{
+		N: n,
+	}
+	mock.lockRunInAsyncN.Lock()
+	mock.calls.RunInAsyncN = append(mock.calls.RunInAsyncN, callInfo)
+	mock.lockRunInAsyncN.Unlock()
+	if mock.RunInAsyncNFunc == nil {
+		var (
+			asyncOut async.Async
+		)
+		return asyncOut
+	}
+	return mock.RunInAsyncNFunc(n)
+}
+
+// RunInAsyncNCalls gets all the calls that were made to RunInAsyncN.
+// Check the length with:
+//
+//	len(mockedWorkerPool.RunInAsyncNCalls())
+func (mock *Pool) RunInAsyncNCalls() []struct {
+	N int
+} {
+	var calls []struct {
+		N int
+	}
+	mock.lockRunInAsyncN.RLock()
+	calls = mock.calls.RunInAsyncN
+	mock.lockRunInAsyncN.RUnlock()
+	return calls
+}
+
+// Shutdown calls ShutdownFunc.
+func (mock *Pool) Shutdown(ctx context.Context) error {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockShutdown.Lock()
+	mock.calls.Shutdown = append(mock.calls.Shutdown, callInfo)
+	mock.lockShutdown.Unlock()
+	if mock.ShutdownFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.ShutdownFunc(ctx)
+}
+
+// ShutdownCalls gets all the calls that were made to Shutdown.
+// Check the length with:
+//
+//	len(mockedWorkerPool.ShutdownCalls())
+func (mock *Pool) ShutdownCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockShutdown.RLock()
+	calls = mock.calls.Shutdown
+	mock.lockShutdown.RUnlock()
+	return calls
+}
+
+// Stats calls StatsFunc.
+func (mock *Pool) Stats() async.PoolStats {
+	callInfo := struct {
+	}{}
+	mock.lockStats.Lock()
+	mock.calls.Stats = append(mock.calls.Stats, callInfo)
+	mock.lockStats.Unlock()
+	if mock.StatsFunc == nil {
+		var (
+			poolStatsOut async.PoolStats
+		)
+		return poolStatsOut
+	}
+	return mock.StatsFunc()
+}
+
+// StatsCalls gets all the calls that were made to Stats.
+// Check the length with:
+//
+//	len(mockedWorkerPool.StatsCalls())
+func (mock *Pool) StatsCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockStats.RLock()
+	calls = mock.calls.Stats
+	mock.lockStats.RUnlock()
+	return calls
+}
+
+// Submit calls SubmitFunc.
+func (mock *Pool) Submit(ctx context.Context, fn async.AsyncFunc) error {
+	callInfo := struct {
+		Ctx context.Context
+		Fn  async.AsyncFunc
+	}{
+		Ctx: ctx,
+		Fn:  fn,
+	}
+	mock.lockSubmit.Lock()
+	mock.calls.Submit = append(mock.calls.Submit, callInfo)
+	mock.lockSubmit.Unlock()
+	if mock.SubmitFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.SubmitFunc(ctx, fn)
+}
+
+// SubmitCalls gets all the calls that were made to Submit.
+// Check the length with:
+//
+//	len(mockedWorkerPool.SubmitCalls())
+func (mock *Pool) SubmitCalls() []struct {
+	Ctx context.Context
+	Fn  async.AsyncFunc
+} {
+	var calls []struct {
+		Ctx context.Context
+		Fn  async.AsyncFunc
+	}
+	mock.lockSubmit.RLock()
+	calls = mock.calls.Submit
+	mock.lockSubmit.RUnlock()
+	return calls
+}
+
+// SubmitKeyed calls SubmitKeyedFunc.
+func (mock *Pool) SubmitKeyed(ctx context.Context, key string, fn async.AsyncFunc) error {
+	callInfo := struct {
+		Ctx context.Context
+		Key string
+		Fn  async.AsyncFunc
+	}{
+		Ctx: ctx,
+		Key: key,
+		Fn:  fn,
+	}
+	mock.lockSubmitKeyed.Lock()
+	mock.calls.SubmitKeyed = append(mock.calls.SubmitKeyed, callInfo)
+	mock.lockSubmitKeyed.Unlock()
+	if mock.SubmitKeyedFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.SubmitKeyedFunc(ctx, key, fn)
+}
+
+// SubmitKeyedCalls gets all the calls that were made to SubmitKeyed.
+// Check the length with:
+//
+//	len(mockedWorkerPool.SubmitKeyedCalls())
+func (mock *Pool) SubmitKeyedCalls() []struct {
+	Ctx context.Context
+	Key string
+	Fn  async.AsyncFunc
+} {
+	var calls []struct {
+		Ctx context.Context
+		Key string
+		Fn  async.AsyncFunc
+	}
+	mock.lockSubmitKeyed.RLock()
+	calls = mock.calls.SubmitKeyed
+	mock.lockSubmitKeyed.RUnlock()
+	return calls
+}
+
+// SubmitWait calls SubmitWaitFunc.
+func (mock *Pool) SubmitWait(ctx context.Context, fn async.AsyncFunc) error {
+	callInfo := struct {
+		Ctx context.Context
+		Fn  async.AsyncFunc
+	}{
+		Ctx: ctx,
+		Fn:  fn,
+	}
+	mock.lockSubmitWait.Lock()
+	mock.calls.SubmitWait = append(mock.calls.SubmitWait, callInfo)
+	mock.lockSubmitWait.Unlock()
+	if mock.SubmitWaitFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.SubmitWaitFunc(ctx, fn)
+}
+
+// SubmitWaitCalls gets all the calls that were made to SubmitWait.
+// Check the length with:
+//
+//	len(mockedWorkerPool.SubmitWaitCalls())
+func (mock *Pool) SubmitWaitCalls() []struct {
+	Ctx context.Context
+	Fn  async.AsyncFunc
+} {
+	var calls []struct {
+		Ctx context.Context
+		Fn  async.AsyncFunc
+	}
+	mock.lockSubmitWait.RLock()
+	calls = mock.calls.SubmitWait
+	mock.lockSubmitWait.RUnlock()
+	return calls
+}
+
+// Ensure, that Lifecycle does implement async.Lifecycle.
+// If this is not the case, regenerate this file with moq.
+var _ async.Lifecycle = &Lifecycle{}
+
+// Lifecycle is a mock implementation of async.Lifecycle.
+//
+//	func TestSomethingThatUsesLifecycle(t *testing.T) {
+//
+//		// make and configure a mocked async.Lifecycle
+//		mockedLifecycle := &Lifecycle{
+//			GoFunc: func(name string, fn async.AsyncFunc, dependsOn ...string) error {
+//				panic("mock out the Go method")
+//			},
+//			RegisterFunc: func(name string, stop func(ctx context.Context) error, dependsOn ...string) error {
+//				panic("mock out the Register method")
+//			},
+//			ShutdownFunc: func(ctx context.Context) error {
+//				panic("mock out the Shutdown method")
+//			},
+//		}
+//
+//		// use mockedLifecycle in code that requires async.Lifecycle
+//		// and then make assertions.
+//
+//	}
+type Lifecycle struct {
+	// GoFunc mocks the Go method.
+	GoFunc func(name string, fn async.AsyncFunc, dependsOn ...string) error
+
+	// RegisterFunc mocks the Register method.
+	RegisterFunc func(name string, stop func(ctx context.Context) error, dependsOn ...string) error
+
+	// ShutdownFunc mocks the Shutdown method.
+	ShutdownFunc func(ctx context.Context) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Go holds details about calls to the Go method.
+		Go []struct {
+			// Name is the name argument value.
+			Name string
+			// Fn is the fn argument value.
+			Fn async.AsyncFunc
+			// DependsOn is the dependsOn argument value.
+			DependsOn []string
+		}
+		// Register holds details about calls to the Register method.
+		Register []struct {
+			// Name is the name argument value.
+			Name string
+			// Stop is the stop argument value.
+			Stop func(ctx context.Context) error
+			// DependsOn is the dependsOn argument value.
+			DependsOn []string
+		}
+		// Shutdown holds details about calls to the Shutdown method.
+		Shutdown []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+	}
+	lockGo       sync.RWMutex
+	lockRegister sync.RWMutex
+	lockShutdown sync.RWMutex
+}
+
+// Go calls GoFunc.
+func (mock *Lifecycle) Go(name string, fn async.AsyncFunc, dependsOn ...string) error {
+	callInfo := struct {
+		Name      string
+		Fn        async.AsyncFunc
+		DependsOn []string
+	}{
+		Name:      name,
+		Fn:        fn,
+		DependsOn: dependsOn,
+	}
+	mock.lockGo.Lock()
+	mock.calls.Go = append(mock.calls.Go, callInfo)
+	mock.lockGo.Unlock()
+	if mock.GoFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.GoFunc(name, fn, dependsOn...)
+}
+
+// GoCalls gets all the calls that were made to Go.
+// Check the length with:
+//
+//	len(mockedLifecycle.GoCalls())
+func (mock *Lifecycle) GoCalls() []struct {
+	Name      string
+	Fn        async.AsyncFunc
+	DependsOn []string
+} {
+	var calls []struct {
+		Name      string
+		Fn        async.AsyncFunc
+		DependsOn []string
+	}
+	mock.lockGo.RLock()
+	calls = mock.calls.Go
+	mock.lockGo.RUnlock()
+	return calls
+}
+
+// Register calls RegisterFunc.
+func (mock *Lifecycle) Register(name string, stop func(ctx context.Context) error, dependsOn ...string) error {
+	callInfo := struct {
+		Name      string
+		Stop      func(ctx context.Context) error
+		DependsOn []string
+	}{
+		Name:      name,
+		Stop:      stop,
+		DependsOn: dependsOn,
+	}
+	mock.lockRegister.Lock()
+	mock.calls.Register = append(mock.calls.Register, callInfo)
+	mock.lockRegister.Unlock()
+	if mock.RegisterFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.RegisterFunc(name, stop, dependsOn...)
+}
+
+// RegisterCalls gets all the calls that were made to Register.
+// Check the length with:
+//
+//	len(mockedLifecycle.RegisterCalls())
+func (mock *Lifecycle) RegisterCalls() []struct {
+	Name      string
+	Stop      func(ctx context.Context) error
+	DependsOn []string
+} {
+	var calls []struct {
+		Name      string
+		Stop      func(ctx context.Context) error
+		DependsOn []string
+	}
+	mock.lockRegister.RLock()
+	calls = mock.calls.Register
+	mock.lockRegister.RUnlock()
+	return calls
+}
+
+// Shutdown calls ShutdownFunc.
+func (mock *Lifecycle) Shutdown(ctx context.Context) error {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockShutdown.Lock()
+	mock.calls.Shutdown = append(mock.calls.Shutdown, callInfo)
+	mock.lockShutdown.Unlock()
+	if mock.ShutdownFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.ShutdownFunc(ctx)
+}
+
+// ShutdownCalls gets all the calls that were made to Shutdown.
+// Check the length with:
+//
+//	len(mockedLifecycle.ShutdownCalls())
+func (mock *Lifecycle) ShutdownCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockShutdown.RLock()
+	calls = mock.calls.Shutdown
+	mock.lockShutdown.RUnlock()
+	return calls
+}
+
+// Ensure, that ScheduledTask does implement async.ScheduledTask.
+// If this is not the case, regenerate this file with moq.
+var _ async.ScheduledTask = &ScheduledTask{}
+
+// ScheduledTask is a mock implementation of async.ScheduledTask.
+//
+//	func TestSomethingThatUsesScheduledTask(t *testing.T) {
+//
+//		// make and configure a mocked async.ScheduledTask
+//		mockedScheduledTask := &ScheduledTask{
+//			PauseFunc: func()  {
+//				panic("mock out the Pause method")
+//			},
+//			ResumeFunc: func()  {
+//				panic("mock out the Resume method")
+//			},
+//			StopFunc: func()  {
+//				panic("mock out the Stop method")
+//			},
+//		}
+//
+//		// use mockedScheduledTask in code that requires async.ScheduledTask
+//		// and then make assertions.
+//
+//	}
+type ScheduledTask struct {
+	// PauseFunc mocks the Pause method.
+	PauseFunc func()
+
+	// ResumeFunc mocks the Resume method.
+	ResumeFunc func()
+
+	// StopFunc mocks the Stop method.
+	StopFunc func()
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Pause holds details about calls to the Pause method.
+		Pause []struct {
+		}
+		// Resume holds details about calls to the Resume method.
+		Resume []struct {
+		}
+		// Stop holds details about calls to the Stop method.
+		Stop []struct {
+		}
+	}
+	lockPause  sync.RWMutex
+	lockResume sync.RWMutex
+	lockStop   sync.RWMutex
+}
+
+// Pause calls PauseFunc.
+func (mock *ScheduledTask) Pause() {
+	callInfo := struct {
+	}{}
+	mock.lockPause.Lock()
+	mock.calls.Pause = append(mock.calls.Pause, callInfo)
+	mock.lockPause.Unlock()
+	if mock.PauseFunc == nil {
+		return
+	}
+	mock.PauseFunc()
+}
+
+// PauseCalls gets all the calls that were made to Pause.
+// Check the length with:
+//
+//	len(mockedScheduledTask.PauseCalls())
+func (mock *ScheduledTask) PauseCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockPause.RLock()
+	calls = mock.calls.Pause
+	mock.lockPause.RUnlock()
+	return calls
+}
+
+// Resume calls ResumeFunc.
+func (mock *ScheduledTask) Resume() {
+	callInfo := struct {
+	}{}
+	mock.lockResume.Lock()
+	mock.calls.Resume = append(mock.calls.Resume, callInfo)
+	mock.lockResume.Unlock()
+	if mock.ResumeFunc == nil {
+		return
+	}
+	mock.ResumeFunc()
+}
+
+// ResumeCalls gets all the calls that were made to Resume.
+// Check the length with:
+//
+//	len(mockedScheduledTask.ResumeCalls())
+func (mock *ScheduledTask) ResumeCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockResume.RLock()
+	calls = mock.calls.Resume
+	mock.lockResume.RUnlock()
+	return calls
+}
+
+// Stop calls StopFunc.
+func (mock *ScheduledTask) Stop() {
+	callInfo := struct {
+	}{}
+	mock.lockStop.Lock()
+	mock.calls.Stop = append(mock.calls.Stop, callInfo)
+	mock.lockStop.Unlock()
+	if mock.StopFunc == nil {
+		return
+	}
+	mock.StopFunc()
+}
+
+// StopCalls gets all the calls that were made to Stop.
+// Check the length with:
+//
+//	len(mockedScheduledTask.StopCalls())
+func (mock *ScheduledTask) StopCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockStop.RLock()
+	calls = mock.calls.Stop
+	mock.lockStop.RUnlock()
+	return calls
+}
+
+// Ensure, that SupervisedTask does implement async.SupervisedTask.
+// If this is not the case, regenerate this file with moq.
+var _ async.SupervisedTask = &SupervisedTask{}
+
+// SupervisedTask is a mock implementation of async.SupervisedTask.
+//
+//	func TestSomethingThatUsesSupervisedTask(t *testing.T) {
+//
+//		// make and configure a mocked async.SupervisedTask
+//		mockedSupervisedTask := &SupervisedTask{
+//			DoneFunc: func() <-chan struct{} {
+//				panic("mock out the Done method")
+//			},
+//			StatsFunc: func() async.SupervisorStats {
+//				panic("mock out the Stats method")
+//			},
+//			StopFunc: func()  {
+//				panic("mock out the Stop method")
+//			},
+//		}
+//
+//		// use mockedSupervisedTask in code that requires async.SupervisedTask
+//		// and then make assertions.
+//
+//	}
+type SupervisedTask struct {
+	// DoneFunc mocks the Done method.
+	DoneFunc func() <-chan struct{}
+
+	// StatsFunc mocks the Stats method.
+	StatsFunc func() async.SupervisorStats
+
+	// StopFunc mocks the Stop method.
+	StopFunc func()
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Done holds details about calls to the Done method.
+		Done []struct {
+		}
+		// Stats holds details about calls to the Stats method.
+		Stats []struct {
+		}
+		// Stop holds details about calls to the Stop method.
+		Stop []struct {
+		}
+	}
+	lockDone  sync.RWMutex
+	lockStats sync.RWMutex
+	lockStop  sync.RWMutex
+}
+
+// Done calls DoneFunc.
+func (mock *SupervisedTask) Done() <-chan struct{} {
+	callInfo := struct {
+	}{}
+	mock.lockDone.Lock()
+	mock.calls.Done = append(mock.calls.Done, callInfo)
+	mock.lockDone.Unlock()
+	if mock.DoneFunc == nil {
+		var (
+			valChOut <-chan struct{}
+		)
+		return valChOut
+	}
+	return mock.DoneFunc()
+}
+
+// DoneCalls gets all the calls that were made to Done.
+// Check the length with:
+//
+//	len(mockedSupervisedTask.DoneCalls())
+func (mock *SupervisedTask) DoneCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockDone.RLock()
+	calls = mock.calls.Done
+	mock.lockDone.RUnlock()
+	return calls
+}
+
+// Stats calls StatsFunc.
+func (mock *SupervisedTask) Stats() async.SupervisorStats {
+	callInfo := struct {
+	}{}
+	mock.lockStats.Lock()
+	mock.calls.Stats = append(mock.calls.Stats, callInfo)
+	mock.lockStats.Unlock()
+	if mock.StatsFunc == nil {
+		var (
+			supervisorStatsOut async.SupervisorStats
+		)
+		return supervisorStatsOut
+	}
+	return mock.StatsFunc()
+}
+
+// StatsCalls gets all the calls that were made to Stats.
+// Check the length with:
+//
+//	len(mockedSupervisedTask.StatsCalls())
+func (mock *SupervisedTask) StatsCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockStats.RLock()
+	calls = mock.calls.Stats
+	mock.lockStats.RUnlock()
+	return calls
+}
+
+// Stop calls StopFunc.
+func (mock *SupervisedTask) Stop() {
+	callInfo := struct {
+	}{}
+	mock.lockStop.Lock()
+	mock.calls.Stop = append(mock.calls.Stop, callInfo)
+	mock.lockStop.Unlock()
+	if mock.StopFunc == nil {
+		return
+	}
+	mock.StopFunc()
+}
+
+// StopCalls gets all the calls that were made to Stop.
+// Check the length with:
+//
+//	len(mockedSupervisedTask.StopCalls())
+func (mock *SupervisedTask) StopCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockStop.RLock()
+	calls = mock.calls.Stop
+	mock.lockStop.RUnlock()
+	return calls
+}
@@ -0,0 +1,86 @@
+// Package asyncmock provides mocks of the async interfaces, generated with
+// moq, for tests of code that receives an AsyncRunner, an Async batch, a
+// WorkerPool or a Lifecycle and only needs to check how it uses them. Each
+// method calls the matching Func field, returning zero values when it is not
+// set, and records its call, which the matching Calls method returns.
+//
+// NewBatch, NewBuilder and NewRunner return mocks whose builder methods
+// return the mock itself, so the code under test can chain calls.
+//
+// To run the tasks of a batch for real while scripting some of them, use
+// asynctest.NewStubRunner instead.
+package asyncmock
+
+//go:generate moq -rm -stub -pkg asyncmock -out async_moq.go .. AsyncRunner:Runner Async:Batch BatchBuilder:Builder BatchExecutor:Executor WorkerPool:Pool Lifecycle:Lifecycle ScheduledTask:ScheduledTask SupervisedTask:SupervisedTask
+
+import (
+	"context"
+	"time"
+
+	async "github.com/andryhardiyanto/go-async"
+)
+
+// BatchBuilder is an async.BatchBuilder whose methods return the builder
+// itself, as implemented by the Builder returned by NewBuilder. Code generic
+// over the builder type, such as func[B async.BatchBuilder[B]](b B) B, accepts
+// both such a builder and a batch.
+type BatchBuilder interface {
+	async.BatchBuilder[BatchBuilder]
+}
+
+// NewBatch returns a Batch whose builder methods return the batch itself.
+func NewBatch() *Batch {
+	m := &Batch{}
+	m.TaskFunc = func(async.AsyncFunc) async.Async { return m }
+	m.TaskNamedFunc = func(string, async.AsyncFunc) async.Async { return m }
+	m.TaskIfFunc = func(func(context.Context) bool, async.AsyncFunc) async.Async { return m }
+	m.TaskOnFunc = func(string, async.AsyncFunc) async.Async { return m }
+	m.TaskDelayedFunc = func(time.Duration, async.AsyncFunc, ...async.ScheduleOption) async.Async { return m }
+	m.TaskJSONFunc = func(any, func(context.Context) ([]byte, error)) async.Async { return m }
+	m.TaskWithTimeoutFunc = func(time.Duration, async.AsyncFunc) async.Async { return m }
+	m.WithTimeoutFunc = func(time.Duration) async.Async { return m }
+	m.WithConcurrencyFunc = func(int) async.Async { return m }
+	m.WithMaxTasksFunc = func(int) async.Async { return m }
+	m.CriticalFunc = func() async.Async { return m }
+	m.WithQuorumFunc = func(int) async.Async { return m }
+	m.AllowSharedDestFunc = func() async.Async { return m }
+	m.WithWatchdogFunc = func(time.Duration, func(async.Report)) async.Async { return m }
+	m.WithCopyResultsFunc = func() async.Async { return m }
+	m.StrictFunc = func() async.Async { return m }
+	m.WithAssignerFunc = func(async.Assigner) async.Async { return m }
+	m.PhaseFunc = func() async.Async { return m }
+	m.ResetFunc = func() async.Async { return m }
+	return m
+}
+
+// NewBuilder returns a Builder whose methods return the builder itself.
+func NewBuilder() *Builder[BatchBuilder] {
+	m := &Builder[BatchBuilder]{}
+	m.TaskFunc = func(async.AsyncFunc) BatchBuilder { return m }
+	m.TaskNamedFunc = func(string, async.AsyncFunc) BatchBuilder { return m }
+	m.TaskIfFunc = func(func(context.Context) bool, async.AsyncFunc) BatchBuilder { return m }
+	m.TaskOnFunc = func(string, async.AsyncFunc) BatchBuilder { return m }
+	m.TaskDelayedFunc = func(time.Duration, async.AsyncFunc, ...async.ScheduleOption) BatchBuilder { return m }
+	m.TaskJSONFunc = func(any, func(context.Context) ([]byte, error)) BatchBuilder { return m }
+	m.TaskWithTimeoutFunc = func(time.Duration, async.AsyncFunc) BatchBuilder { return m }
+	m.WithTimeoutFunc = func(time.Duration) BatchBuilder { return m }
+	m.WithConcurrencyFunc = func(int) BatchBuilder { return m }
+	m.WithMaxTasksFunc = func(int) BatchBuilder { return m }
+	m.CriticalFunc = func() BatchBuilder { return m }
+	m.WithQuorumFunc = func(int) BatchBuilder { return m }
+	m.AllowSharedDestFunc = func() BatchBuilder { return m }
+	m.WithWatchdogFunc = func(time.Duration, func(async.Report)) BatchBuilder { return m }
+	m.WithCopyResultsFunc = func() BatchBuilder { return m }
+	m.StrictFunc = func() BatchBuilder { return m }
+	m.WithAssignerFunc = func(async.Assigner) BatchBuilder { return m }
+	m.PhaseFunc = func() BatchBuilder { return m }
+	return m
+}
+
+// NewRunner returns a Runner whose RunInAsync and RunInAsyncN return batch.
+func NewRunner(batch *Batch) *Runner {
+	return &Runner{
+		RunInAsyncFunc:  func() async.Async { return batch },
+		RunInAsyncNFunc: func(int) async.Async { return batch },
+	}
+}
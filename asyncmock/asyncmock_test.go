@@ -0,0 +1,116 @@
+package asyncmock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	async "github.com/andryhardiyanto/go-async"
+)
+
+// loadDashboard is the kind of code the mocks stand in for.
+func loadDashboard(ctx context.Context, runner async.AsyncRunner, fetch async.AsyncFunc) error {
+	return runner.RunInAsync().
+		WithTimeout(time.Second).
+		TaskNamed("widgets", fetch).
+		Go(ctx)
+}
+
+// addWidgets only builds, so it accepts both a batch and a Builder.
+func addWidgets[B async.BatchBuilder[B]](b B, fetch async.AsyncFunc) B {
+	return b.TaskNamed("widgets", fetch).WithConcurrency(2)
+}
+
+func TestRunner(t *testing.T) {
+	errDown := errors.New("down")
+	batch := NewBatch()
+	batch.GoFunc = func(ctx context.Context) error { return errDown }
+	runner := NewRunner(batch)
+
+	ran := false
+	err := loadDashboard(context.Background(), runner, func(ctx context.Context) error {
+		ran = true
+		return nil
+	})
+	if !errors.Is(err, errDown) {
+		t.Fatalf("Expected the scripted error, got %v", err)
+	}
+	if ran {
+		t.Error("Expected the mock not to run the task")
+	}
+
+	if calls := runner.RunInAsyncCalls(); len(calls) != 1 {
+		t.Errorf("Expected one batch, got %d", len(calls))
+	}
+	if calls := batch.WithTimeoutCalls(); len(calls) != 1 || calls[0].Timeout != time.Second {
+		t.Errorf("Unexpected WithTimeout calls %+v", calls)
+	}
+	if len(batch.GoCalls()) != 1 {
+		t.Errorf("Expected one Go call, got %d", len(batch.GoCalls()))
+	}
+	calls := batch.TaskNamedCalls()
+	if len(calls) != 1 || calls[0].Name != "widgets" {
+		t.Fatalf("Unexpected TaskNamed calls %+v", calls)
+	}
+
+	// The recorded tasks can still be run by the test
+	calls[0].Fn(context.Background())
+	if !ran {
+		t.Error("Expected the call to record the registered function")
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	fetch := func(ctx context.Context) error { return nil }
+
+	builder := NewBuilder()
+	if got := addWidgets[BatchBuilder](builder, fetch); got != builder {
+		t.Errorf("Expected the builder to be returned, got %v", got)
+	}
+	if calls := builder.TaskNamedCalls(); len(calls) != 1 || calls[0].Name != "widgets" {
+		t.Errorf("Unexpected TaskNamed calls %+v", calls)
+	}
+	if calls := builder.WithConcurrencyCalls(); len(calls) != 1 || calls[0].N != 2 {
+		t.Errorf("Unexpected WithConcurrency calls %+v", calls)
+	}
+
+	// The same code builds a real batch
+	batch := async.NewAsyncRunner().RunInAsync()
+	if err := addWidgets(batch, fetch).Go(context.Background()); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestPool(t *testing.T) {
+	pool := &Pool{}
+	var wp async.WorkerPool = pool
+	if err := wp.SubmitKeyed(context.Background(), "user-1", func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	wp.Shutdown(context.Background())
+
+	if calls := pool.SubmitKeyedCalls(); len(calls) != 1 || calls[0].Key != "user-1" {
+		t.Errorf("Unexpected SubmitKeyed calls %+v", calls)
+	}
+	if len(pool.ShutdownCalls()) != 1 {
+		t.Errorf("Expected one Shutdown call, got %d", len(pool.ShutdownCalls()))
+	}
+}
+
+func TestBatchExecutor(t *testing.T) {
+	// Code only running a batch can take the narrower interface
+	run := func(ctx context.Context, b async.BatchExecutor) error {
+		if err := b.Err(); err != nil {
+			return err
+		}
+		return b.Go(ctx)
+	}
+	batch := &Executor{ErrFunc: func() error { return async.ErrNilTask }}
+	if err := run(context.Background(), batch); !errors.Is(err, async.ErrNilTask) {
+		t.Errorf("Expected the build error, got %v", err)
+	}
+	if len(batch.GoCalls()) != 0 {
+		t.Error("Expected Go not to be called")
+	}
+}
@@ -0,0 +1,113 @@
+// Package asyncredis stores the results of async.TaskCached tasks in Redis,
+// so the instances of a service share one cache. It is a separate module,
+// keeping the Redis client out of the dependencies of the async package.
+package asyncredis
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	async "github.com/andryhardiyanto/go-async"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrPointerValue is returned by Set for a result of a pointer type, which
+// gob flattens: it would come back as the value pointed to, and the tasks of
+// the key would always miss. Cache the value instead.
+var ErrPointerValue = errors.New("asyncredis: pointer results can't be cached")
+
+// Client is the part of a Redis client the cache uses. *redis.Client,
+// *redis.ClusterClient and redis.UniversalClient all implement it.
+type Client interface {
+	Get(ctx context.Context, key string) *redis.StringCmd
+	Set(ctx context.Context, key string, value any, expiration time.Duration) *redis.StatusCmd
+	Del(ctx context.Context, keys ...string) *redis.IntCmd
+}
+
+// Option configures a Cache.
+type Option func(*Cache)
+
+// WithPrefix prefixes every key with prefix, so several services or
+// environments can share a Redis database.
+func WithPrefix(prefix string) Option {
+	return func(c *Cache) {
+		c.prefix = prefix
+	}
+}
+
+// Cache implements async.Cache on top of Redis. Entries are encoded with
+// encoding/gob, so the concrete types of the cached results must be
+// registered with gob.Register, and must be encodable by gob. Results of a
+// pointer type are rejected with ErrPointerValue, since gob decodes them as
+// the value pointed to. Cached errors come back as plain errors carrying the
+// original message.
+type Cache struct {
+	client Client
+	prefix string
+}
+
+var _ async.Cache = (*Cache)(nil)
+
+// New creates a Cache storing entries through client.
+func New(client Client, opts ...Option) *Cache {
+	c := &Cache{client: client}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// entry is the encoded form of an async.CacheEntry.
+type entry struct {
+	Value  any
+	Err    string
+	Failed bool
+	Stored time.Time
+}
+
+// Get returns the entry stored under key.
+func (c *Cache) Get(ctx context.Context, key string) (async.CacheEntry, bool, error) {
+	b, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return async.CacheEntry{}, false, nil
+	}
+	if err != nil {
+		return async.CacheEntry{}, false, err
+	}
+
+	var e entry
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&e); err != nil {
+		return async.CacheEntry{}, false, err
+	}
+	res := async.CacheEntry{Value: e.Value, Stored: e.Stored}
+	if e.Failed {
+		res.Err = errors.New(e.Err)
+	}
+	return res, true, nil
+}
+
+// Set stores entry under key, expiring it after ttl.
+func (c *Cache) Set(ctx context.Context, key string, en async.CacheEntry, ttl time.Duration) error {
+	if t := reflect.TypeOf(en.Value); t != nil && t.Kind() == reflect.Pointer {
+		return fmt.Errorf("%w: %s", ErrPointerValue, t)
+	}
+	e := entry{Value: en.Value, Stored: en.Stored}
+	if en.Err != nil {
+		e.Err, e.Failed = en.Err.Error(), true
+	}
+	var b bytes.Buffer
+	if err := gob.NewEncoder(&b).Encode(&e); err != nil {
+		return err
+	}
+	return c.client.Set(ctx, c.prefix+key, b.Bytes(), ttl).Err()
+}
+
+// Delete removes the entry stored under key.
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	return c.client.Del(ctx, c.prefix+key).Err()
+}
@@ -0,0 +1,113 @@
+package asyncredis
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	async "github.com/andryhardiyanto/go-async"
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeClient is an in-memory Client ignoring expirations.
+type fakeClient struct {
+	mu     sync.Mutex
+	values map[string]string
+	ttls   map[string]time.Duration
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{values: make(map[string]string), ttls: make(map[string]time.Duration)}
+}
+
+func (f *fakeClient) Get(_ context.Context, key string) *redis.StringCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	v, ok := f.values[key]
+	if !ok {
+		return redis.NewStringResult("", redis.Nil)
+	}
+	return redis.NewStringResult(v, nil)
+}
+
+func (f *fakeClient) Set(_ context.Context, key string, value any, expiration time.Duration) *redis.StatusCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.values[key] = string(value.([]byte))
+	f.ttls[key] = expiration
+	return redis.NewStatusResult("OK", nil)
+}
+
+func (f *fakeClient) Del(_ context.Context, keys ...string) *redis.IntCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, key := range keys {
+		delete(f.values, key)
+	}
+	return redis.NewIntResult(int64(len(keys)), nil)
+}
+
+type profile struct {
+	Name string
+}
+
+func init() {
+	gob.Register(profile{})
+}
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient()
+	c := New(client, WithPrefix("svc:"))
+
+	stored := time.Now().Round(0)
+	if err := c.Set(ctx, "user:1", async.CacheEntry{Value: profile{Name: "ada"}, Stored: stored}, time.Minute); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if client.ttls["svc:user:1"] != time.Minute {
+		t.Errorf("Expected the prefixed key to expire after the ttl, got %v", client.ttls)
+	}
+	e, ok, err := c.Get(ctx, "user:1")
+	if err != nil || !ok {
+		t.Fatalf("Expected a hit, got %v, %v", ok, err)
+	}
+	if e.Value != (profile{Name: "ada"}) || !e.Stored.Equal(stored) || e.Err != nil {
+		t.Errorf("Expected the stored entry, got %+v", e)
+	}
+
+	_ = c.Set(ctx, "user:2", async.CacheEntry{Err: errors.New("not found"), Stored: stored}, time.Second)
+	if e, _, _ := c.Get(ctx, "user:2"); e.Err == nil || e.Err.Error() != "not found" || e.Value != nil {
+		t.Errorf("Expected the cached failure, got %+v", e)
+	}
+
+	if err := c.Set(ctx, "user:3", async.CacheEntry{Value: &profile{Name: "ada"}, Stored: stored}, time.Minute); !errors.Is(err, ErrPointerValue) {
+		t.Errorf("Expected ErrPointerValue, got %v", err)
+	}
+
+	_ = c.Delete(ctx, "user:1")
+	if _, ok, err := c.Get(ctx, "user:1"); ok || err != nil {
+		t.Errorf("Expected a miss, got %v, %v", ok, err)
+	}
+}
+
+func TestCacheWithRunner(t *testing.T) {
+	runner := async.NewAsyncRunner(async.WithCache(New(newFakeClient())))
+	calls := 0
+	for range 2 {
+		var p profile
+		a := runner.RunInAsync()
+		async.TaskCached(a, "user:1", time.Minute, &p, func(ctx context.Context) (profile, error) {
+			calls++
+			return profile{Name: "ada"}, nil
+		})
+		if err := a.Go(context.Background()); err != nil || p.Name != "ada" {
+			t.Fatalf("Expected the profile, got %+v, %v", p, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected the second batch to hit the cache, got %d calls", calls)
+	}
+}
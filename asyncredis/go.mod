@@ -0,0 +1,14 @@
+module github.com/andryhardiyanto/go-async/asyncredis
+
+go 1.26.2
+
+require (
+	github.com/andryhardiyanto/go-async v1.0.0
+	github.com/redis/go-redis/v9 v9.7.0
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.2.0 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	golang.org/x/sync v0.20.0 // indirect
+)
@@ -0,0 +1,82 @@
+package asynctest
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	async "github.com/andryhardiyanto/go-async"
+)
+
+// ErrChaos is the error injected by WithChaos when ChaosConfig.Err is nil.
+var ErrChaos = errors.New("asynctest: injected failure")
+
+// ChaosConfig sets the faults injected into tasks by WithChaos.
+type ChaosConfig struct {
+	// ErrorRate is the probability, between 0 and 1, that a task fails with
+	// Err instead of running.
+	ErrorRate float64
+	// PanicRate is the probability, between 0 and 1, that a task panics
+	// instead of running.
+	PanicRate float64
+	// MinDelay and MaxDelay bound a random latency added before every task.
+	// The delay ends early if the task's context is done.
+	MinDelay, MaxDelay time.Duration
+	// Err is the injected error, ErrChaos if nil.
+	Err error
+	// Seed makes the injected faults reproducible. Zero picks a random seed.
+	Seed uint64
+}
+
+// WithChaos makes the runner inject random latency, errors and panics into
+// its tasks, to check that a batch configuration survives a flaky
+// dependency. Living in asynctest, it is only linked into test binaries.
+func WithChaos(cfg ChaosConfig) async.RunnerOption {
+	if cfg.Err == nil {
+		cfg.Err = ErrChaos
+	}
+	if cfg.MaxDelay < cfg.MinDelay {
+		cfg.MaxDelay = cfg.MinDelay
+	}
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	c := &chaos{cfg: cfg, rng: rand.New(rand.NewPCG(seed, seed))}
+	return async.WithMiddleware(c.inject)
+}
+
+type chaos struct {
+	cfg ChaosConfig
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// inject draws the faults of a task when it starts.
+func (c *chaos) inject(info async.TaskInfo, next async.AsyncFunc) async.AsyncFunc {
+	return func(ctx context.Context) error {
+		c.mu.Lock()
+		delay := c.cfg.MinDelay
+		if spread := c.cfg.MaxDelay - c.cfg.MinDelay; spread > 0 {
+			delay += time.Duration(c.rng.Int64N(int64(spread) + 1))
+		}
+		roll := c.rng.Float64()
+		c.mu.Unlock()
+
+		if delay > 0 {
+			if err := sleep(ctx, delay); err != nil {
+				return err
+			}
+		}
+		switch {
+		case roll < c.cfg.PanicRate:
+			panic(c.cfg.Err)
+		case roll < c.cfg.PanicRate+c.cfg.ErrorRate:
+			return c.cfg.Err
+		}
+		return next(ctx)
+	}
+}
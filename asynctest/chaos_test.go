@@ -0,0 +1,80 @@
+package asynctest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	async "github.com/andryhardiyanto/go-async"
+)
+
+func TestChaosErrors(t *testing.T) {
+	runner := async.NewAsyncRunner(WithChaos(ChaosConfig{ErrorRate: 1}))
+	ran := false
+	err := runner.RunInAsync().
+		Task(func(ctx context.Context) error {
+			ran = true
+			return nil
+		}).
+		Go(context.Background())
+	if !errors.Is(err, ErrChaos) {
+		t.Errorf("Expected ErrChaos, got %v", err)
+	}
+	if ran {
+		t.Error("Expected the failing task not to run")
+	}
+}
+
+func TestChaosPanics(t *testing.T) {
+	runner := async.NewAsyncRunner(WithChaos(ChaosConfig{PanicRate: 1}))
+	err := runner.RunInAsync().
+		Task(func(ctx context.Context) error { return nil }).
+		Go(context.Background())
+	var panicErr *async.PanicError
+	if !errors.As(err, &panicErr) || !errors.Is(err, ErrChaos) {
+		t.Errorf("Expected a *PanicError wrapping ErrChaos, got %v", err)
+	}
+}
+
+func TestChaosDelay(t *testing.T) {
+	runner := async.NewAsyncRunner(WithChaos(ChaosConfig{MinDelay: 20 * time.Millisecond, MaxDelay: 30 * time.Millisecond}))
+	start := time.Now()
+	err := runner.RunInAsync().
+		Task(func(ctx context.Context) error { return nil }).
+		Go(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Expected at least the minimum delay, took %v", elapsed)
+	}
+
+	// The delay gives up with the batch
+	err = runner.RunInAsync().
+		WithTimeout(time.Millisecond).
+		Task(func(ctx context.Context) error { return nil }).
+		Go(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the delay to end with the batch timeout, got %v", err)
+	}
+}
+
+func TestChaosSeed(t *testing.T) {
+	outcomes := func() []bool {
+		runner := async.NewAsyncRunner(WithChaos(ChaosConfig{ErrorRate: 0.5, Seed: 42}), async.WithSynchronous())
+		var failed []bool
+		for i := 0; i < 20; i++ {
+			err := runner.RunInAsync().Task(func(ctx context.Context) error { return nil }).Go(context.Background())
+			failed = append(failed, err != nil)
+		}
+		return failed
+	}
+
+	first, second := outcomes(), outcomes()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("Expected the same seed to inject the same faults, got %v and %v", first, second)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+// Package asynctest provides helpers for testing code built on the async package.
+package asynctest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	async "github.com/andryhardiyanto/go-async"
+)
+
+// Clock is a fake async.Clock whose time only moves when Advance is called,
+// so timeouts, delays and schedules can be triggered without sleeping. Use it
+// with async.WithClock.
+type Clock struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*timer
+}
+
+// NewClock returns a fake clock set to now.
+func NewClock(now time.Time) *Clock {
+	c := &Clock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the current fake time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer returns a timer firing once the clock has been advanced by d.
+func (c *Clock) NewTimer(d time.Duration) async.Timer {
+	t := &timer{clock: c, ch: make(chan time.Time, 1)}
+	t.Reset(d)
+	return t
+}
+
+// NewTicker returns a ticker firing every time the clock has been advanced by
+// d. Like time.Ticker, it holds at most one pending tick, so advancing by
+// several periods at once delivers a single tick.
+func (c *Clock) NewTicker(d time.Duration) async.Ticker {
+	if d <= 0 {
+		panic("asynctest: non-positive interval for NewTicker")
+	}
+	t := &timer{clock: c, ch: make(chan time.Time, 1), period: d}
+	c.mu.Lock()
+	t.at = c.now.Add(d)
+	c.add(t)
+	c.mu.Unlock()
+	return ticker{t}
+}
+
+// Advance moves the clock forward by d, firing every timer and ticker that
+// becomes due, in due order.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	now := c.now
+
+	var due []*timer
+	kept := c.timers[:0]
+	for _, t := range c.timers {
+		if t.at.After(now) {
+			kept = append(kept, t)
+			continue
+		}
+		due = append(due, t)
+		if t.period > 0 {
+			// Ticks missed in between are dropped, as with time.Ticker
+			for !t.at.After(now) {
+				t.at = t.at.Add(t.period)
+			}
+			kept = append(kept, t)
+		}
+	}
+	clear(c.timers[len(kept):])
+	c.timers = kept
+	c.mu.Unlock()
+
+	sort.SliceStable(due, func(i, j int) bool { return due[i].at.Before(due[j].at) })
+	for _, t := range due {
+		select {
+		case t.ch <- now:
+		default:
+		}
+	}
+}
+
+// BlockUntil waits until at least n timers and tickers are waiting to fire.
+// Background goroutines arm their timers asynchronously, so tests call it
+// before Advance to make sure the timers they expect to fire exist.
+func (c *Clock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+// add registers t, waking up BlockUntil.
+func (c *Clock) add(t *timer) {
+	c.timers = append(c.timers, t)
+	c.cond.Broadcast()
+}
+
+// remove unregisters t, reporting whether it was waiting to fire.
+func (c *Clock) remove(t *timer) bool {
+	for i, other := range c.timers {
+		if other == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// timer is a timer or, with a period, a ticker of a fake Clock.
+type timer struct {
+	clock  *Clock
+	ch     chan time.Time
+	at     time.Time
+	period time.Duration
+}
+
+func (t *timer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *timer) Reset(d time.Duration) bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	active := c.remove(t)
+	t.at = c.now.Add(d)
+	if d <= 0 {
+		select {
+		case t.ch <- c.now:
+		default:
+		}
+		return active
+	}
+	c.add(t)
+	return active
+}
+
+func (t *timer) Stop() bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.remove(t)
+}
+
+// ticker adapts a periodic timer to async.Ticker.
+type ticker struct {
+	*timer
+}
+
+func (t ticker) Stop() {
+	t.timer.Stop()
+}
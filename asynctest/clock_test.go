@@ -0,0 +1,127 @@
+package asynctest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	async "github.com/andryhardiyanto/go-async"
+)
+
+func TestClockBatchTimeout(t *testing.T) {
+	clock := NewClock(time.Now())
+	runner := async.NewAsyncRunner(async.WithClock(clock))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- runner.RunInAsync().
+			WithTimeout(time.Hour).
+			Task(func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}).
+			Go(context.Background())
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected deadline exceeded, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the batch to time out once the clock advanced")
+	}
+}
+
+func TestClockDelayedTask(t *testing.T) {
+	clock := NewClock(time.Now())
+	runner := async.NewAsyncRunner(async.WithClock(clock))
+
+	ran := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- runner.RunInAsync().
+			TaskDelayed(time.Minute, func(ctx context.Context) error {
+				close(ran)
+				return nil
+			}).
+			Go(context.Background())
+	}()
+
+	clock.BlockUntil(1)
+	select {
+	case <-ran:
+		t.Fatal("Expected the task to wait for the clock")
+	default:
+	}
+	clock.Advance(time.Minute)
+
+	if err := <-done; err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestClockEvery(t *testing.T) {
+	clock := NewClock(time.Now())
+	runner := async.NewAsyncRunner(async.WithClock(clock))
+
+	runs := make(chan struct{}, 3)
+	task := runner.Every(context.Background(), time.Minute, func(ctx context.Context) error {
+		runs <- struct{}{}
+		return nil
+	})
+	defer task.Stop()
+
+	for i := 0; i < 3; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(time.Minute)
+		select {
+		case <-runs:
+		case <-time.After(time.Second):
+			t.Fatalf("Expected run %d once the clock advanced", i)
+		}
+	}
+}
+
+func TestClockReportDuration(t *testing.T) {
+	clock := NewClock(time.Now())
+	runner := async.NewAsyncRunner(async.WithClock(clock))
+
+	a := runner.RunInAsync().Task(func(ctx context.Context) error {
+		clock.Advance(5 * time.Second)
+		return nil
+	})
+	if err := a.Go(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if d := a.Report().Tasks[0].Duration; d != 5*time.Second {
+		t.Errorf("Expected a duration of 5s on the fake clock, got %v", d)
+	}
+}
+
+func TestClockTickerDropsMissedTicks(t *testing.T) {
+	clock := NewClock(time.Now())
+
+	ticker := clock.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	clock.Advance(3 * time.Second)
+	<-ticker.C()
+	select {
+	case <-ticker.C():
+		t.Fatal("Expected missed ticks to be dropped")
+	default:
+	}
+
+	// The ticker keeps its period from the start, without drifting
+	clock.Advance(time.Second)
+	select {
+	case <-ticker.C():
+	default:
+		t.Fatal("Expected a tick after the next period")
+	}
+}
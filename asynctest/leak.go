@@ -0,0 +1,83 @@
+package asynctest
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// asyncFrame prefixes the stack frames of the async package, whose
+// goroutines VerifyNoLeaks tracks.
+const asyncFrame = "github.com/andryhardiyanto/go-async."
+
+// leakGrace is how long VerifyNoLeaks waits for goroutines that are about to
+// exit, such as workers of a pool that was just shut down.
+var leakGrace = time.Second
+
+// VerifyNoLeaks fails t if goroutines started by the async package after the
+// call, such as the workers of a batch or a pool, or the loop of a schedule,
+// are still running when the test ends. Call it first, so pools and schedules
+// stopped by the test's deferred calls and later cleanups are gone by the
+// time it checks. Tests running in parallel can make it report their
+// goroutines as well.
+func VerifyNoLeaks(t testing.TB) {
+	t.Helper()
+	before := make(map[string]bool)
+	for _, g := range asyncGoroutines() {
+		before[goroutineHeader(g)] = true
+	}
+
+	t.Cleanup(func() {
+		var leaked []string
+		deadline := time.Now().Add(leakGrace)
+		for wait := time.Millisecond; ; wait = min(2*wait, 100*time.Millisecond) {
+			leaked = leaked[:0]
+			for _, g := range asyncGoroutines() {
+				if !before[goroutineHeader(g)] {
+					leaked = append(leaked, g)
+				}
+			}
+			if len(leaked) == 0 || time.Now().After(deadline) {
+				break
+			}
+			time.Sleep(wait)
+		}
+		if len(leaked) > 0 {
+			t.Errorf("asynctest: %d goroutines started by async outlived the test:\n\n%s",
+				len(leaked), strings.Join(leaked, "\n\n"))
+		}
+	})
+}
+
+// asyncGoroutines returns the stacks of the goroutines running code of the
+// async package or created by it.
+func asyncGoroutines() []string {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	var stacks []string
+	for _, g := range bytes.Split(buf, []byte("\n\n")) {
+		for _, line := range strings.Split(string(g), "\n") {
+			if strings.HasPrefix(line, asyncFrame) || strings.HasPrefix(line, "created by "+asyncFrame) {
+				stacks = append(stacks, string(g))
+				break
+			}
+		}
+	}
+	return stacks
+}
+
+// goroutineHeader returns the "goroutine N" prefix identifying a stack.
+func goroutineHeader(stack string) string {
+	header, _, _ := strings.Cut(stack, " [")
+	return header
+}
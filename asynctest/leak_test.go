@@ -0,0 +1,46 @@
+package asynctest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	async "github.com/andryhardiyanto/go-async"
+)
+
+func TestVerifyNoLeaks(t *testing.T) {
+	VerifyNoLeaks(t)
+
+	err := async.NewAsyncRunner().RunInAsync().
+		Task(func(ctx context.Context) error { return nil }).
+		Task(func(ctx context.Context) error { return nil }).
+		Go(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	pool := async.NewWorkerPool(2)
+	defer pool.Shutdown(context.Background())
+	if err := pool.SubmitWait(context.Background(), func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestVerifyNoLeaksReportsLeak(t *testing.T) {
+	defer func(grace time.Duration) { leakGrace = grace }(leakGrace)
+	leakGrace = 10 * time.Millisecond
+
+	ft := &fakeT{}
+	VerifyNoLeaks(ft)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	task := async.NewAsyncRunner().Every(ctx, time.Hour, func(ctx context.Context) error { return nil })
+	defer task.Stop()
+
+	ft.end()
+	if len(ft.errors) != 1 || !strings.Contains(ft.errors[0], "outlived the test") {
+		t.Errorf("Expected the schedule loop to be reported, got %q", ft.errors)
+	}
+}
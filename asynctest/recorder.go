@@ -0,0 +1,158 @@
+package asynctest
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	async "github.com/andryhardiyanto/go-async"
+)
+
+// Run is a task execution captured by a Recorder.
+type Run struct {
+	// Index is the position of the task in its batch report.
+	Index int
+	// Name is the name given with TaskNamed, if any.
+	Name string
+	// Start and End are when the task function started and returned.
+	Start, End time.Time
+	// Err is the error returned by the task.
+	Err error
+	// Concurrent is how many recorded tasks were running when the task started,
+	// itself included.
+	Concurrent int
+}
+
+// Duration returns how long the task ran.
+func (r Run) Duration() time.Duration {
+	return r.End.Sub(r.Start)
+}
+
+// Recorder captures the tasks run by a runner's batches, with their order,
+// concurrency and durations, and asserts on them. Register it with
+// async.WithMiddleware(rec.Record).
+type Recorder struct {
+	t testing.TB
+
+	mu      sync.Mutex
+	runs    []Run
+	running int
+	peak    int
+	// gen counts the calls to Reset, so tasks started before one don't
+	// record their end into the runs recorded after it
+	gen int
+}
+
+// NewRecorder returns a recorder reporting failed assertions to t.
+func NewRecorder(t testing.TB) *Recorder {
+	return &Recorder{t: t}
+}
+
+// Record is the async.Middleware capturing every task that starts.
+func (r *Recorder) Record(info async.TaskInfo, next async.AsyncFunc) async.AsyncFunc {
+	return func(ctx context.Context) error {
+		r.mu.Lock()
+		r.running++
+		r.peak = max(r.peak, r.running)
+		i, gen := len(r.runs), r.gen
+		r.runs = append(r.runs, Run{Index: info.Index, Name: info.Name, Start: time.Now(), Concurrent: r.running})
+		r.mu.Unlock()
+
+		err := next(ctx)
+
+		r.mu.Lock()
+		r.running--
+		if r.gen == gen {
+			r.runs[i].End = time.Now()
+			r.runs[i].Err = err
+		}
+		r.mu.Unlock()
+		return err
+	}
+}
+
+// Runs returns the recorded tasks in the order they started. Tasks still
+// running have a zero End.
+func (r *Recorder) Runs() []Run {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.runs)
+}
+
+// Order returns the names of the recorded tasks in the order they started,
+// leaving out unnamed ones.
+func (r *Recorder) Order() []string {
+	var names []string
+	for _, run := range r.Runs() {
+		if run.Name != "" {
+			names = append(names, run.Name)
+		}
+	}
+	return names
+}
+
+// MaxConcurrency returns the highest number of recorded tasks that ran at once.
+func (r *Recorder) MaxConcurrency() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.peak
+}
+
+// Reset forgets the recorded tasks, for reuse across test cases. Tasks still
+// running are forgotten too, and are not recorded when they return.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.runs = nil
+	r.peak = r.running
+	r.gen++
+}
+
+// AssertRan fails the test unless a task with each of the names ran.
+func (r *Recorder) AssertRan(names ...string) {
+	r.t.Helper()
+	order := r.Order()
+	for _, name := range names {
+		if !slices.Contains(order, name) {
+			r.t.Errorf("asynctest: expected task %q to run, ran %q", name, order)
+		}
+	}
+}
+
+// AssertNotRan fails the test if a task with any of the names ran.
+func (r *Recorder) AssertNotRan(names ...string) {
+	r.t.Helper()
+	order := r.Order()
+	for _, name := range names {
+		if slices.Contains(order, name) {
+			r.t.Errorf("asynctest: expected task %q not to run", name)
+		}
+	}
+}
+
+// AssertOrder fails the test unless the tasks with the names started in that
+// order. Other tasks may have started in between.
+func (r *Recorder) AssertOrder(names ...string) {
+	r.t.Helper()
+	order := r.Order()
+	i := 0
+	for _, name := range order {
+		if i < len(names) && name == names[i] {
+			i++
+		}
+	}
+	if i < len(names) {
+		r.t.Errorf("asynctest: expected tasks to start in order %q, got %q", names, order)
+	}
+}
+
+// AssertMaxConcurrency fails the test if more than n recorded tasks ran at once.
+func (r *Recorder) AssertMaxConcurrency(n int) {
+	r.t.Helper()
+	if peak := r.MaxConcurrency(); peak > n {
+		r.t.Errorf("asynctest: expected at most %d tasks running at once, got %d", n, peak)
+	}
+}
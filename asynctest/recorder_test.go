@@ -0,0 +1,142 @@
+package asynctest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	async "github.com/andryhardiyanto/go-async"
+)
+
+// fakeT collects the failures of assertions expected to fail.
+type fakeT struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Cleanup(fn func()) {
+	f.cleanups = append(f.cleanups, fn)
+}
+
+// end runs the registered cleanups, as when the test ends.
+func (f *fakeT) end() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+func (f *fakeT) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRecorder(t *testing.T) {
+	rec := NewRecorder(t)
+	runner := async.NewAsyncRunner(async.WithMiddleware(rec.Record))
+
+	errFailed := errors.New("failed")
+	// Both tasks of the first phase wait for each other, so they overlap
+	var started sync.WaitGroup
+	started.Add(2)
+	sleep := func(ctx context.Context) error {
+		started.Done()
+		started.Wait()
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	}
+	err := runner.RunInAsync().
+		WithConcurrency(2).
+		TaskNamed("user", sleep).
+		TaskNamed("orders", sleep).
+		Phase().
+		TaskNamed("render", func(ctx context.Context) error { return errFailed }).
+		Go(context.Background())
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("Expected the task error, got %v", err)
+	}
+
+	rec.AssertRan("user", "orders", "render")
+	rec.AssertOrder("user", "render")
+	rec.AssertOrder("orders", "render")
+	rec.AssertMaxConcurrency(2)
+
+	runs := rec.Runs()
+	if len(runs) != 3 {
+		t.Fatalf("Expected 3 runs, got %d", len(runs))
+	}
+	if runs[0].Duration() < 5*time.Millisecond {
+		t.Errorf("Expected the duration to be recorded, got %v", runs[0].Duration())
+	}
+	if last := runs[2]; last.Name != "render" || last.Index != 2 || !errors.Is(last.Err, errFailed) {
+		t.Errorf("Expected the failed render task last, got %+v", last)
+	}
+	if rec.MaxConcurrency() != 2 {
+		t.Errorf("Expected both tasks of the first phase to overlap, got %d", rec.MaxConcurrency())
+	}
+}
+
+func TestRecorderAssertionsFail(t *testing.T) {
+	ft := &fakeT{}
+	rec := NewRecorder(ft)
+	runner := async.NewAsyncRunner(async.WithMiddleware(rec.Record))
+
+	err := runner.RunInAsync().
+		WithConcurrency(1).
+		TaskNamed("first", func(ctx context.Context) error { return nil }).
+		TaskNamed("second", func(ctx context.Context) error { return nil }).
+		Go(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	rec.AssertRan("third")
+	rec.AssertNotRan("first")
+	rec.AssertOrder("second", "first")
+	rec.AssertMaxConcurrency(0)
+	if len(ft.errors) != 4 {
+		t.Errorf("Expected 4 failed assertions, got %q", ft.errors)
+	}
+
+	rec.Reset()
+	if len(rec.Runs()) != 0 || rec.MaxConcurrency() != 0 {
+		t.Error("Expected Reset to forget the runs")
+	}
+}
+
+func TestRecorderResetWhileRunning(t *testing.T) {
+	rec := NewRecorder(t)
+	runner := async.NewAsyncRunner(async.WithMiddleware(rec.Record))
+	errSlow := errors.New("slow")
+	started, release := make(chan struct{}), make(chan struct{})
+
+	a := runner.RunInAsync().
+		WithConcurrency(1).
+		TaskNamed("first", func(ctx context.Context) error { return nil }).
+		TaskNamed("slow", func(ctx context.Context) error {
+			close(started)
+			<-release
+			return errSlow
+		})
+	done := make(chan error, 1)
+	go func() { done <- a.Go(context.Background()) }()
+	<-started
+
+	rec.Reset()
+	if err := runner.RunInAsync().TaskNamed("next", func(ctx context.Context) error { return nil }).Go(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	close(release)
+	if err := <-done; !errors.Is(err, errSlow) {
+		t.Fatalf("Expected the slow task to fail, got %v", err)
+	}
+
+	runs := rec.Runs()
+	if len(runs) != 1 || runs[0].Name != "next" || runs[0].End.IsZero() || runs[0].Err != nil {
+		t.Errorf("Expected only the run started after Reset, got %+v", runs)
+	}
+}
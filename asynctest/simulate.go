@@ -0,0 +1,89 @@
+package asynctest
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	async "github.com/andryhardiyanto/go-async"
+)
+
+// ErrSimulated is the error returned by the failing runs of a SimulatedTask.
+var ErrSimulated = errors.New("asynctest: simulated failure")
+
+// Latency draws the duration of a simulated call. It must be safe for
+// concurrent use.
+type Latency func() time.Duration
+
+// Fixed returns a latency of exactly d.
+func Fixed(d time.Duration) Latency {
+	return func() time.Duration {
+		return d
+	}
+}
+
+// Uniform returns a latency spread evenly between lo and hi.
+func Uniform(lo, hi time.Duration) Latency {
+	if hi <= lo {
+		return Fixed(lo)
+	}
+	return func() time.Duration {
+		return lo + time.Duration(rand.Int64N(int64(hi-lo)+1))
+	}
+}
+
+// Normal returns a normally distributed latency, never below zero.
+func Normal(mean, stddev time.Duration) Latency {
+	return func() time.Duration {
+		return max(0, mean+time.Duration(rand.NormFloat64()*float64(stddev)))
+	}
+}
+
+// LogNormal returns a long-tailed latency with the given median and 99th
+// percentile, the shape network calls usually have.
+func LogNormal(p50, p99 time.Duration) Latency {
+	if p99 <= p50 || p50 <= 0 {
+		return Fixed(p50)
+	}
+	mu := math.Log(float64(p50))
+	// 2.326 is the 99th percentile of the standard normal distribution
+	sigma := (math.Log(float64(p99)) - mu) / 2.326
+	return func() time.Duration {
+		return time.Duration(math.Exp(mu + sigma*rand.NormFloat64()))
+	}
+}
+
+// SimulatedTask returns a task standing in for a remote call: every run waits
+// for a duration drawn from latency, then fails with ErrSimulated with
+// probability errRate. It gives up early with the context's error. Used as
+// task bodies in benchmarks, it shows how concurrency limits, timeouts and
+// retries behave against realistic latencies before they reach production.
+func SimulatedTask(latency Latency, errRate float64) async.AsyncFunc {
+	return func(ctx context.Context) error {
+		if err := sleep(ctx, latency()); err != nil {
+			return err
+		}
+		if rand.Float64() < errRate {
+			return ErrSimulated
+		}
+		return nil
+	}
+}
+
+// sleep waits for d, or returns ctx's error if it is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,61 @@
+package asynctest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	async "github.com/andryhardiyanto/go-async"
+)
+
+func TestLatencies(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		if d := Uniform(time.Millisecond, 2*time.Millisecond)(); d < time.Millisecond || d > 2*time.Millisecond {
+			t.Fatalf("Expected a uniform latency within bounds, got %v", d)
+		}
+		if d := Normal(time.Millisecond, 10*time.Millisecond)(); d < 0 {
+			t.Fatalf("Expected a non-negative normal latency, got %v", d)
+		}
+	}
+
+	// Roughly half the draws fall below the median of a log-normal latency
+	below := 0
+	latency := LogNormal(10*time.Millisecond, 100*time.Millisecond)
+	for i := 0; i < 1000; i++ {
+		if latency() < 10*time.Millisecond {
+			below++
+		}
+	}
+	if below < 400 || below > 600 {
+		t.Errorf("Expected about 500 draws below the median, got %d", below)
+	}
+}
+
+func TestSimulatedTask(t *testing.T) {
+	err := async.NewAsyncRunner().RunInAsync().
+		Task(SimulatedTask(Fixed(time.Millisecond), 0)).
+		Task(SimulatedTask(Fixed(time.Millisecond), 1)).
+		Go(context.Background())
+	if !errors.Is(err, ErrSimulated) {
+		t.Errorf("Expected ErrSimulated, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if err := SimulatedTask(Fixed(time.Hour), 0)(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the task to give up with the context, got %v", err)
+	}
+}
+
+func BenchmarkSimulatedFanOut(b *testing.B) {
+	task := SimulatedTask(LogNormal(100*time.Microsecond, time.Millisecond), 0.01)
+	runner := async.NewAsyncRunner()
+	for b.Loop() {
+		a := runner.RunInAsync().WithConcurrency(8)
+		for i := 0; i < 32; i++ {
+			a.Task(task)
+		}
+		a.Go(context.Background())
+	}
+}
@@ -0,0 +1,124 @@
+package asynctest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	async "github.com/andryhardiyanto/go-async"
+)
+
+// ErrNoResult is returned by a task stubbed with a value whose function
+// stores no result, so the value would be lost.
+var ErrNoResult = errors.New("asynctest: stubbed value for a task without a result")
+
+// StubRunner is an async.AsyncRunner whose named tasks can be scripted, so
+// code building batches can be tested against specific task outcomes without
+// calling the real dependencies. Tasks without a stub run as usual.
+type StubRunner struct {
+	async.AsyncRunner
+
+	mu    sync.Mutex
+	stubs map[string]*Stub
+}
+
+// NewStubRunner returns a runner created with opts whose tasks named with
+// async.TaskNamed, or by async.Populate, follow the stubs registered with On.
+func NewStubRunner(opts ...async.RunnerOption) *StubRunner {
+	r := &StubRunner{stubs: make(map[string]*Stub)}
+	opts = append(opts[:len(opts):len(opts)], async.WithMiddleware(r.intercept))
+	r.AsyncRunner = async.NewAsyncRunner(opts...)
+	return r
+}
+
+// On returns the stub of the tasks named name, registering it if needed. A
+// stub without a scripted outcome makes its tasks succeed without running.
+func (r *StubRunner) On(name string) *Stub {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.stubs[name]
+	if !ok {
+		s = &Stub{}
+		r.stubs[name] = s
+	}
+	return s
+}
+
+// intercept replaces the function of stubbed tasks.
+func (r *StubRunner) intercept(info async.TaskInfo, next async.AsyncFunc) async.AsyncFunc {
+	if info.Name == "" {
+		return next
+	}
+	r.mu.Lock()
+	s, ok := r.stubs[info.Name]
+	r.mu.Unlock()
+	if !ok {
+		return next
+	}
+	return func(ctx context.Context) error {
+		return s.call(ctx, info, next)
+	}
+}
+
+// Stub scripts the outcome of the tasks with a given name.
+type Stub struct {
+	mu    sync.Mutex
+	value any
+	err   error
+	fn    async.AsyncFunc
+	calls int
+}
+
+// Return makes the tasks fail with err, or succeed with value when err is
+// nil. The value is stored by tasks built with Bind, BindPtr, BindFunc or
+// Populate; other tasks fail with ErrNoResult, so stub them with a nil value.
+// The function of a stubbed task never runs.
+func (s *Stub) Return(value any, err error) *Stub {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.value, s.err, s.fn = value, err, nil
+	return s
+}
+
+// Do makes the tasks run fn instead of their function, for outcomes that
+// depend on the context, such as blocking until the batch times out.
+func (s *Stub) Do(fn async.AsyncFunc) *Stub {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.value, s.err, s.fn = nil, nil, fn
+	return s
+}
+
+// Calls returns how many tasks the stub has replaced.
+func (s *Stub) Calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// call runs the scripted outcome of the task described by info, whose
+// function is next.
+func (s *Stub) call(ctx context.Context, info async.TaskInfo, next async.AsyncFunc) error {
+	s.mu.Lock()
+	s.calls++
+	value, err, fn := s.value, s.err, s.fn
+	s.mu.Unlock()
+
+	switch {
+	case fn != nil:
+		return fn(ctx)
+	case err != nil:
+		return err
+	case value != nil && !info.Result:
+		return fmt.Errorf("%w: task %q", ErrNoResult, info.Name)
+	case value != nil:
+		// Stores value without calling the task's function
+		return next(async.StubResult(ctx, value))
+	default:
+		return nil
+	}
+}
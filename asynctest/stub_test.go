@@ -0,0 +1,105 @@
+package asynctest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	async "github.com/andryhardiyanto/go-async"
+)
+
+type profile struct {
+	User   string `async:"user"`
+	Orders int    `async:"orders"`
+}
+
+func TestStubRunner(t *testing.T) {
+	errDown := errors.New("orders service down")
+	runner := NewStubRunner()
+	runner.On("user").Return("alice", nil)
+	runner.On("orders").Return(nil, errDown)
+
+	called := false
+	fetch := func(ctx context.Context) (string, error) {
+		called = true
+		return "bob", nil
+	}
+
+	var user string
+	batch := runner.RunInAsync().
+		WithConcurrency(1).
+		TaskNamed("user", async.Bind(&user, fetch)).
+		TaskNamed("orders", func(ctx context.Context) error {
+			called = true
+			return nil
+		})
+	if err := batch.Go(context.Background()); !errors.Is(err, errDown) {
+		t.Fatalf("Expected the scripted error, got %v", err)
+	}
+	if called {
+		t.Error("Expected the stubbed functions not to be called")
+	}
+	if user != "alice" {
+		t.Errorf("Expected the scripted user, got %q", user)
+	}
+	if runner.On("user").Calls() != 1 || runner.On("orders").Calls() != 1 {
+		t.Error("Expected each stub to be called once")
+	}
+}
+
+func TestStubRunnerPlainTask(t *testing.T) {
+	runner := NewStubRunner()
+	runner.On("notify").Return("sent", nil)
+
+	called := false
+	err := runner.RunInAsync().
+		TaskNamed("notify", func(ctx context.Context) error {
+			called = true
+			return nil
+		}).
+		Go(context.Background())
+	if !errors.Is(err, ErrNoResult) {
+		t.Errorf("Expected ErrNoResult, got %v", err)
+	}
+	if called {
+		t.Error("Expected the stubbed function not to be called")
+	}
+}
+
+func TestStubRunnerPopulate(t *testing.T) {
+	runner := NewStubRunner()
+	runner.On("orders").Return(3, nil)
+
+	var p profile
+	err := async.Populate(runner.RunInAsync(), &p, map[string]async.ValueFunc{
+		"user": async.Value(func(ctx context.Context) (string, error) {
+			return "alice", nil
+		}),
+		"orders": async.Value(func(ctx context.Context) (int, error) {
+			return 0, errors.New("not stubbed")
+		}),
+	}).Go(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if p.User != "alice" || p.Orders != 3 {
+		t.Errorf("Expected the real user and the scripted orders, got %+v", p)
+	}
+}
+
+func TestStubRunnerDo(t *testing.T) {
+	runner := NewStubRunner()
+	runner.On("slow").Do(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	err := runner.RunInAsync().
+		WithTimeout(10*time.Millisecond).
+		TaskNamed("slow", func(ctx context.Context) error { return nil }).
+		Go(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the scripted task to time out, got %v", err)
+	}
+}
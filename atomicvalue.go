@@ -0,0 +1,49 @@
+package async
+
+import "sync"
+
+// AtomicValue is a destination that may be read while the batch is still
+// running, so callers can consume results as they become available. Feed it
+// with BindFunc(v.Store, fn). The zero value is ready to use.
+type AtomicValue[T any] struct {
+	mu    sync.Mutex
+	val   T
+	ok    bool
+	ready chan struct{}
+}
+
+// Store sets the value, waking up the waiters of Ready.
+func (v *AtomicValue[T]) Store(val T) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.val = val
+	if !v.ok {
+		v.ok = true
+		if v.ready != nil {
+			close(v.ready)
+		}
+	}
+}
+
+// Load returns the value, and whether it was stored yet.
+func (v *AtomicValue[T]) Load() (T, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.val, v.ok
+}
+
+// Ready returns a channel closed once a value has been stored.
+func (v *AtomicValue[T]) Ready() <-chan struct{} {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.ready == nil {
+		v.ready = make(chan struct{})
+		if v.ok {
+			close(v.ready)
+		}
+	}
+	return v.ready
+}
@@ -0,0 +1,48 @@
+package async
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAtomicValue(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var fast, slow AtomicValue[int]
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- runner.RunInAsync().
+			Task(BindFunc(fast.Store, func(ctx context.Context) (int, error) {
+				return 1, nil
+			})).
+			Task(BindFunc(slow.Store, func(ctx context.Context) (int, error) {
+				<-release
+				return 2, nil
+			})).
+			Go(context.Background())
+	}()
+
+	// The fast result is readable while the batch still runs
+	select {
+	case <-fast.Ready():
+	case <-time.After(time.Second):
+		t.Fatal("Expected the fast result to become ready")
+	}
+	if v, ok := fast.Load(); !ok || v != 1 {
+		t.Errorf("Expected 1, got %d (%v)", v, ok)
+	}
+	if _, ok := slow.Load(); ok {
+		t.Error("Expected the slow result not to be stored yet")
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	<-slow.Ready()
+	if v, _ := slow.Load(); v != 2 {
+		t.Errorf("Expected 2, got %d", v)
+	}
+}
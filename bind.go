@@ -0,0 +1,124 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+)
+
+var (
+	// ErrSharedDest is returned by a Bind task whose destination was already
+	// claimed by another task of the same batch run.
+	ErrSharedDest = errors.New("async: destination shared by multiple tasks")
+	// ErrNilResult is returned by a Bind task of a strict batch whose function
+	// returned a nil pointer, slice, map, channel, function or interface without an error.
+	ErrNilResult = errors.New("async: nil result")
+)
+
+type bindKey struct{}
+
+// bindState is how Bind tasks store results during one phase of a batch run.
+type bindState struct {
+	// shared disables destination claims
+	shared bool
+	// copy stores deep copies of the results
+	copy bool
+	// strict rejects nil results
+	strict bool
+	// assign, if set, stores results instead of a plain assignment
+	assign Assigner
+	// runner backs the keyed tasks, such as TaskCached
+	runner *asyncRunner
+
+	mu     sync.Mutex
+	claims map[any]struct{}
+}
+
+// bindStateFrom returns the bind state of the batch owning ctx, if any.
+func bindStateFrom(ctx context.Context) *bindState {
+	s, _ := ctx.Value(bindKey{}).(*bindState)
+	return s
+}
+
+// runnerOf returns the runner executing the batch, if any.
+func (s *bindState) runnerOf() *asyncRunner {
+	if s == nil {
+		return nil
+	}
+	return s.runner
+}
+
+// cacheOf returns the result cache of the runner, if any.
+func (s *bindState) cacheOf() *resultCache {
+	if s == nil {
+		return nil
+	}
+	return s.runner.cache
+}
+
+// flightsOf returns the calls shared by the TaskCoalesced tasks of the runner.
+func (s *bindState) flightsOf() *flightGroup[string] {
+	if s == nil {
+		return nil
+	}
+	return s.runner.flights
+}
+
+// idempotencyOf returns the idempotency keys of the runner, if any.
+func (s *bindState) idempotencyOf() *idempotency {
+	if s == nil {
+		return nil
+	}
+	return s.runner.idempotency
+}
+
+// claim reserves dest for the calling task. It fails if another task of the
+// phase already claimed it, since both would race on the write.
+func (s *bindState) claim(dest any) error {
+	if s == nil || s.shared {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.claims[dest]; ok {
+		return detailed(ErrSharedDest, "%T", dest)
+	}
+	if s.claims == nil {
+		s.claims = make(map[any]struct{})
+	}
+	s.claims[dest] = struct{}{}
+	return nil
+}
+
+// store assigns res to dest, applying the batch's result policies.
+func store[T any](s *bindState, dest *T, res T) error {
+	if s != nil && s.strict && isNil(res) {
+		return detailed(ErrNilResult, "%T", res)
+	}
+	if s != nil && s.assign != nil {
+		return s.assign.Assign(dest, res)
+	}
+	if s != nil && s.copy {
+		res = clone(res)
+	}
+	*dest = res
+	return nil
+}
+
+// isNil reports whether v is a nil value of a nillable kind.
+func isNil[T any](v T) bool {
+	return isNilValue(reflect.ValueOf(&v).Elem())
+}
+
+// isNilValue reports whether v is a nil value of a nillable kind.
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface, reflect.Chan, reflect.Func:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
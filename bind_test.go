@@ -0,0 +1,190 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSharedDest(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	fetch := func(v int) func(ctx context.Context) (int, error) {
+		return func(ctx context.Context) (int, error) {
+			return v, nil
+		}
+	}
+
+	var result int
+	err := runner.RunInAsync().
+		Task(Bind(&result, fetch(1))).
+		Task(Bind(&result, fetch(2))).
+		Go(context.Background())
+	if !errors.Is(err, ErrSharedDest) {
+		t.Errorf("Expected ErrSharedDest, got %v", err)
+	}
+
+	// Sequential phases may reuse a destination
+	err = runner.RunInAsync().
+		Task(Bind(&result, fetch(1))).
+		Phase().
+		Task(Bind(&result, fetch(2))).
+		Go(context.Background())
+	if err != nil || result != 2 {
+		t.Errorf("Expected phases to reuse the destination, got %d and %v", result, err)
+	}
+
+	// Discarded results never conflict
+	err = runner.RunInAsync().
+		Task(Bind[int](nil, fetch(1))).
+		Task(Bind[int](nil, fetch(2))).
+		Go(context.Background())
+	if err != nil {
+		t.Errorf("Expected nil destinations not to conflict, got %v", err)
+	}
+
+	err = runner.RunInAsync().
+		AllowSharedDest().
+		Task(Bind(&result, fetch(1))).
+		Task(Bind(&result, fetch(1))).
+		Go(context.Background())
+	if err != nil || result != 1 {
+		t.Errorf("Expected AllowSharedDest to permit the shared destination, got %d and %v", result, err)
+	}
+}
+
+func TestBindNilResult(t *testing.T) {
+	type user struct{ Name string }
+
+	none := func(ctx context.Context) (*user, error) {
+		return nil, nil
+	}
+
+	// By default the nil result is stored as returned
+	dest := &user{Name: "stale"}
+	err := NewAsyncRunner().RunInAsync().
+		Task(Bind(&dest, none)).
+		Go(context.Background())
+	if err != nil || dest != nil {
+		t.Errorf("Expected the nil result to be stored, got %v and %v", dest, err)
+	}
+
+	dest = &user{Name: "stale"}
+	var count int
+	err = NewAsyncRunner().RunInAsync().
+		Strict().
+		Task(Bind(&dest, none)).
+		Task(Bind(&count, func(ctx context.Context) (int, error) {
+			return 0, nil
+		})).
+		Go(context.Background())
+	if !errors.Is(err, ErrNilResult) {
+		t.Errorf("Expected ErrNilResult, got %v", err)
+	}
+	if dest == nil || dest.Name != "stale" {
+		t.Errorf("Expected the destination to be left untouched, got %v", dest)
+	}
+}
+
+func TestBindFunc(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	results := make(chan int, 3)
+	a := runner.RunInAsync()
+	for i := 1; i <= 3; i++ {
+		a.Task(BindFunc(func(v int) { results <- v }, func(ctx context.Context) (int, error) {
+			return i, nil
+		}))
+	}
+	if err := a.Go(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	close(results)
+
+	sum := 0
+	for v := range results {
+		sum += v
+	}
+	if sum != 6 {
+		t.Errorf("Expected every result handed to the sink, got sum %d", sum)
+	}
+
+	called := false
+	boom := errors.New("boom")
+	err := runner.RunInAsync().
+		Task(BindFunc(func(v *int) { called = true }, func(ctx context.Context) (*int, error) {
+			return nil, boom
+		})).
+		Go(context.Background())
+	if !errors.Is(err, boom) || called {
+		t.Errorf("Expected boom without calling the sink, got %v (called %v)", err, called)
+	}
+
+	err = runner.RunInAsync().
+		Strict().
+		Task(BindFunc(func(v *int) { called = true }, func(ctx context.Context) (*int, error) {
+			return nil, nil
+		})).
+		Go(context.Background())
+	if !errors.Is(err, ErrNilResult) || called {
+		t.Errorf("Expected ErrNilResult without calling the sink, got %v (called %v)", err, called)
+	}
+}
+
+func TestBindPtr(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var found, skipped *int
+	err := runner.RunInAsync().
+		Task(BindPtr(&found, func(ctx context.Context) (int, error) {
+			return 0, nil
+		})).
+		TaskIf(func(ctx context.Context) bool { return false }, BindPtr(&skipped, func(ctx context.Context) (int, error) {
+			return 1, nil
+		})).
+		Go(context.Background())
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if found == nil || *found != 0 {
+		t.Errorf("Expected a pointer to the zero result, got %v", found)
+	}
+	if skipped != nil {
+		t.Errorf("Expected the skipped result to stay nil, got %v", *skipped)
+	}
+}
+
+func TestStrictNamedTask(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var amount any
+	a := runner.RunInAsync().
+		Strict().
+		TaskNamed("amount", Bind(&amount, func(ctx context.Context) (any, error) {
+			return nil, nil
+		}))
+	err := a.Go(context.Background())
+
+	if !errors.Is(err, ErrNilResult) {
+		t.Fatalf("Expected ErrNilResult, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `task "amount"`) {
+		t.Errorf("Expected the error to name the task, got %q", err.Error())
+	}
+	if r := a.Report().Tasks[0]; r.Name != "amount" {
+		t.Errorf("Expected the report to carry the task name, got %q", r.Name)
+	}
+
+	// Populate names its tasks after the fields
+	var view struct{ Total int }
+	err = Populate(runner.RunInAsync().Strict(), &view, map[string]ValueFunc{
+		"Total": func(ctx context.Context) (any, error) {
+			return nil, nil
+		},
+	}).Go(context.Background())
+	if !errors.Is(err, ErrNilResult) || !strings.Contains(err.Error(), `task "Total"`) {
+		t.Errorf("Expected ErrNilResult naming the field, got %v", err)
+	}
+}
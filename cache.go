@@ -0,0 +1,246 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNoCache is returned by a TaskCached task of a runner created without WithCache.
+var ErrNoCache = errors.New("async: no cache configured")
+
+// Cache stores the results of TaskCached tasks. NewLRUCache is an in-memory
+// implementation; shared implementations let the instances of a service reuse
+// each other's results.
+type Cache interface {
+	// Get returns the entry stored under key, and whether there is one.
+	Get(ctx context.Context, key string) (CacheEntry, bool, error)
+	// Set stores entry under key, expiring it after ttl. A zero ttl never expires.
+	Set(ctx context.Context, key string, entry CacheEntry, ttl time.Duration) error
+	// Delete removes the entry stored under key, if any.
+	Delete(ctx context.Context, key string) error
+}
+
+// CacheEntry is a result stored in a Cache.
+type CacheEntry struct {
+	// Value is the result of the task.
+	Value any
+	// Err is the error of the task, for a failure cached with CacheErrors.
+	Err error
+	// Stored is when the result was stored, on the runner's clock.
+	Stored time.Time
+}
+
+// CacheStats counts how the TaskCached tasks of a runner were served.
+type CacheStats struct {
+	// Hits is the number of tasks served a fresh entry.
+	Hits int64
+	// StaleHits is the number of tasks served a stale entry under
+	// StaleWhileRevalidate.
+	StaleHits int64
+	// NegativeHits is the number of tasks served an error cached with CacheErrors.
+	NegativeHits int64
+	// Misses is the number of tasks that found no usable entry.
+	Misses int64
+}
+
+// WithCache makes the runner store the results of TaskCached tasks in cache.
+func WithCache(cache Cache) RunnerOption {
+	return func(c *runnerConfig) {
+		c.cache = cache
+	}
+}
+
+// CacheOption configures TaskCached.
+type CacheOption func(*cacheConfig)
+
+type cacheConfig struct {
+	ttl      time.Duration
+	maxStale time.Duration
+	// errTTL is how long failures accepted by cacheable are cached
+	errTTL    time.Duration
+	cacheable func(error) bool
+}
+
+// StaleWhileRevalidate keeps entries for maxStale past their ttl. A task
+// finding such a stale entry gets it right away while the function is called
+// in the background to refresh it, so latency-sensitive fetches such as
+// configuration and feature flags never wait on the source once warm. The
+// refresh runs without the task's cancellation, and a failed one leaves the
+// stale entry in place for the next task to retry.
+func StaleWhileRevalidate(maxStale time.Duration) CacheOption {
+	return func(c *cacheConfig) {
+		c.maxStale = maxStale
+	}
+}
+
+// CacheErrors caches the failures for which cacheable reports true for ttl,
+// so a hard-down dependency isn't hammered by every batch: until the entry
+// expires, tasks fail with the cached error without calling the function. A
+// nil cacheable caches every failure. Cancellations and expired deadlines are
+// never cached, since they say nothing about the dependency.
+func CacheErrors(ttl time.Duration, cacheable func(error) bool) CacheOption {
+	return func(c *cacheConfig) {
+		c.errTTL = ttl
+		c.cacheable = cacheable
+		if c.cacheable == nil {
+			c.cacheable = func(error) bool { return true }
+		}
+	}
+}
+
+// cachesError reports whether err is cached.
+func (c cacheConfig) cachesError(err error) bool {
+	return c.errTTL > 0 && !isContextError(err) && c.cacheable(err)
+}
+
+// storeTTL returns how long the cache keeps entries, stale ones included.
+func (c cacheConfig) storeTTL() time.Duration {
+	if c.ttl <= 0 {
+		return 0
+	}
+	return c.ttl + c.maxStale
+}
+
+// TaskCached adds fn to the batch like Bind, storing its result in the
+// runner's cache under key for ttl. While the entry is fresh, fn is skipped
+// and dest receives the stored result instead. Concurrent tasks of the runner
+// with the same key share a single call of fn, even across batches. Errors
+// are only cached with CacheErrors, and a cache failing to answer counts as a
+// miss. Go fails with ErrNoCache if the runner has no cache.
+func TaskCached[T any](a Async, key string, ttl time.Duration, dest *T, fn func(ctx context.Context) (T, error), opts ...CacheOption) Async {
+	if fn == nil {
+		return a.Task(nil)
+	}
+	cfg := cacheConfig{ttl: ttl}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return a.Task(Bind(dest, func(ctx context.Context) (T, error) {
+		return cached(ctx, bindStateFrom(ctx).cacheOf(), key, cfg, fn)
+	}))
+}
+
+// resultCache backs the TaskCached tasks of a runner.
+type resultCache struct {
+	cache Cache
+	clock Clock
+	// onPanic receives the panics of background refreshes
+	onPanic func(*PanicError)
+	flights *flightGroup[string]
+
+	hits, staleHits, negativeHits, misses atomic.Int64
+}
+
+func newResultCache(cache Cache, clock Clock, onPanic func(*PanicError), flights *flightGroup[string]) *resultCache {
+	return &resultCache{
+		cache:   cache,
+		clock:   clock,
+		onPanic: onPanic,
+		flights: flights,
+	}
+}
+
+// stats returns the counters of the cache.
+func (c *resultCache) stats() CacheStats {
+	return CacheStats{
+		Hits:         c.hits.Load(),
+		StaleHits:    c.staleHits.Load(),
+		NegativeHits: c.negativeHits.Load(),
+		Misses:       c.misses.Load(),
+	}
+}
+
+// CacheStats returns how the runner's TaskCached tasks were served so far.
+func (a *asyncRunner) CacheStats() CacheStats {
+	if a.cache == nil {
+		return CacheStats{}
+	}
+	return a.cache.stats()
+}
+
+// cached returns the result stored under key, or calls fn once for all the
+// concurrent callers and stores its result.
+func cached[T any](ctx context.Context, c *resultCache, key string, cfg cacheConfig, fn func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	if c == nil {
+		return zero, ErrNoCache
+	}
+	for missed := false; ; missed = true {
+		if e, ok, err := c.cache.Get(ctx, key); err == nil && ok {
+			age := c.clock.Now().Sub(e.Stored)
+			if e.Err != nil {
+				if age < cfg.errTTL {
+					c.negativeHits.Add(1)
+					return zero, e.Err
+				}
+			} else if v, ok := e.Value.(T); ok {
+				// An entry of another type, such as one left by an older
+				// version of the task, is a miss
+				if cfg.ttl <= 0 || age < cfg.ttl {
+					c.hits.Add(1)
+					return v, nil
+				}
+				if age < cfg.storeTTL() {
+					c.staleHits.Add(1)
+					revalidate(ctx, c, key, cfg, fn)
+					return v, nil
+				}
+			}
+		}
+		if !missed {
+			// Retries after a cancelled leader are the same miss
+			c.misses.Add(1)
+		}
+
+		f, leader := c.flights.join(key)
+		if leader {
+			return lead(ctx, c, f, key, cfg, fn)
+		}
+		res, retry, err := wait[T](ctx, f, key)
+		if retry {
+			continue
+		}
+		return res, err
+	}
+}
+
+// revalidate refreshes the stale entry under key in the background, unless
+// a call for key is already in flight.
+func revalidate[T any](ctx context.Context, c *resultCache, key string, cfg cacheConfig, fn func(ctx context.Context) (T, error)) {
+	f, leader := c.flights.join(key)
+	if !leader {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil && c.onPanic != nil {
+				c.onPanic(&PanicError{Value: r, Stack: debug.Stack()})
+			}
+		}()
+		_, _ = lead(ctx, c, f, key, cfg, fn)
+	}()
+}
+
+// lead calls fn on behalf of the waiters of f and stores its result.
+func lead[T any](ctx context.Context, c *resultCache, f *flight, key string, cfg cacheConfig, fn func(ctx context.Context) (T, error)) (T, error) {
+	return shareCall(ctx, c.flights, f, key, func(ctx context.Context) (T, error) {
+		res, err := fn(ctx)
+		// Stored even if ctx is done meanwhile, since the result is complete
+		switch {
+		case err == nil:
+			_ = c.cache.Set(context.WithoutCancel(ctx), key, CacheEntry{Value: res, Stored: c.clock.Now()}, cfg.storeTTL())
+		case cfg.cachesError(err):
+			_ = c.cache.Set(context.WithoutCancel(ctx), key, CacheEntry{Err: err, Stored: c.clock.Now()}, cfg.errTTL)
+		}
+		return res, err
+	})
+}
+
+// isContextError reports whether err comes from a cancelled or expired context.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
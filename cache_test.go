@@ -0,0 +1,261 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTaskCached(t *testing.T) {
+	runner := NewAsyncRunner(WithCache(NewLRUCache(10)))
+	var calls atomic.Int32
+	fetch := func(ctx context.Context) (string, error) {
+		calls.Add(1)
+		return "flags", nil
+	}
+
+	for range 2 {
+		var flags string
+		a := runner.RunInAsync()
+		if err := TaskCached(a, "flags", time.Minute, &flags, fetch).Go(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if flags != "flags" {
+			t.Errorf("Expected the result, got %q", flags)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected the second batch to hit the cache, got %d calls", n)
+	}
+
+	// Errors are not cached
+	errDown := errors.New("down")
+	for range 2 {
+		var v int
+		err := TaskCached(runner.RunInAsync(), "broken", time.Minute, &v, func(ctx context.Context) (int, error) {
+			calls.Add(1)
+			return 0, errDown
+		}).Go(context.Background())
+		if !errors.Is(err, errDown) {
+			t.Errorf("Expected the failure, got %v", err)
+		}
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("Expected failures to be retried, got %d calls", n)
+	}
+}
+
+func TestTaskCachedCoalesces(t *testing.T) {
+	runner := NewAsyncRunner(WithCache(NewLRUCache(10)))
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) (int, error) {
+		calls.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	const n = 5
+	var wg sync.WaitGroup
+	results := make([]int, n)
+	for i := range n {
+		wg.Go(func() {
+			if err := TaskCached(runner.RunInAsync(), "answer", time.Minute, &results[i], fetch).Go(context.Background()); err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		})
+	}
+	// Leaves the batches time to join the call in flight
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if c := calls.Load(); c != 1 {
+		t.Errorf("Expected concurrent batches to share one call, got %d", c)
+	}
+	for i, v := range results {
+		if v != 42 {
+			t.Errorf("Expected batch %d to get the shared result, got %d", i, v)
+		}
+	}
+}
+
+func TestTaskCachedLeaderCanceled(t *testing.T) {
+	runner := NewAsyncRunner(WithCache(NewLRUCache(10)))
+	started := make(chan struct{})
+	var calls atomic.Int32
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leader := make(chan error, 1)
+	go func() {
+		var v int
+		leader <- TaskCached(runner.RunInAsync(), "key", time.Minute, &v, func(ctx context.Context) (int, error) {
+			calls.Add(1)
+			close(started)
+			<-ctx.Done()
+			return 0, ctx.Err()
+		}).Go(ctx)
+	}()
+	<-started
+
+	follower := make(chan error, 1)
+	var v int
+	go func() {
+		follower <- TaskCached(runner.RunInAsync(), "key", time.Minute, &v, func(ctx context.Context) (int, error) {
+			calls.Add(1)
+			return 7, nil
+		}).Go(context.Background())
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	if err := <-leader; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the leader to be canceled, got %v", err)
+	}
+	if err := <-follower; err != nil || v != 7 {
+		t.Errorf("Expected the follower to retry the call, got %d, %v", v, err)
+	}
+}
+
+func TestTaskCachedNoCache(t *testing.T) {
+	var v int
+	err := TaskCached(NewAsyncRunner().RunInAsync(), "key", time.Minute, &v, func(ctx context.Context) (int, error) {
+		return 1, nil
+	}).Go(context.Background())
+	if !errors.Is(err, ErrNoCache) {
+		t.Errorf("Expected ErrNoCache, got %v", err)
+	}
+}
+
+func TestTaskCachedStaleWhileRevalidate(t *testing.T) {
+	runner := NewAsyncRunner(WithCache(NewLRUCache(10)))
+	var version atomic.Int32
+	refreshing := make(chan struct{}, 1)
+	release := make(chan struct{}, 1)
+	fetch := func(ctx context.Context) (int32, error) {
+		v := version.Add(1)
+		if v > 1 {
+			refreshing <- struct{}{}
+			<-release
+		}
+		return v, nil
+	}
+	get := func() int32 {
+		var v int32
+		a := runner.RunInAsync()
+		if err := TaskCached(a, "config", 20*time.Millisecond, &v, fetch, StaleWhileRevalidate(time.Hour)).Go(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return v
+	}
+
+	if v := get(); v != 1 {
+		t.Fatalf("Expected the first version, got %d", v)
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	// The stale entry is returned without waiting for the refresh
+	if v := get(); v != 1 {
+		t.Errorf("Expected the stale version, got %d", v)
+	}
+	<-refreshing
+	if v := get(); v != 1 {
+		t.Errorf("Expected the stale version while the refresh runs, got %d", v)
+	}
+	release <- struct{}{}
+
+	deadline := time.Now().Add(time.Second)
+	for get() != 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the refreshed version")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestTaskCachedMaxStale(t *testing.T) {
+	runner := NewAsyncRunner(WithCache(NewLRUCache(10)))
+	var calls atomic.Int32
+	fetch := func(ctx context.Context) (int32, error) {
+		return calls.Add(1), nil
+	}
+	get := func() int32 {
+		var v int32
+		_ = TaskCached(runner.RunInAsync(), "config", 5*time.Millisecond, &v, fetch, StaleWhileRevalidate(5*time.Millisecond)).Go(context.Background())
+		return v
+	}
+
+	get()
+	time.Sleep(20 * time.Millisecond)
+	// Past the max-stale bound, the task waits for a fresh result
+	if v := get(); v != 2 {
+		t.Errorf("Expected a fresh result, got %d", v)
+	}
+}
+
+func TestTaskCachedErrors(t *testing.T) {
+	errDown := errors.New("down")
+	errBadRequest := errors.New("bad request")
+	runner := NewAsyncRunner(WithCache(NewLRUCache(10)))
+	var calls atomic.Int32
+	get := func(key string, err error) error {
+		var v int
+		return TaskCached(runner.RunInAsync(), key, time.Minute, &v, func(ctx context.Context) (int, error) {
+			calls.Add(1)
+			return 0, err
+		}, CacheErrors(20*time.Millisecond, func(err error) bool {
+			return errors.Is(err, errDown)
+		})).Go(context.Background())
+	}
+
+	for range 3 {
+		if err := get("down", errDown); !errors.Is(err, errDown) {
+			t.Errorf("Expected the cached failure, got %v", err)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected the failure to be cached, got %d calls", n)
+	}
+	time.Sleep(30 * time.Millisecond)
+	_ = get("down", errDown)
+	if n := calls.Load(); n != 2 {
+		t.Errorf("Expected the cached failure to expire, got %d calls", n)
+	}
+
+	// Failures the classifier rejects are not cached
+	_ = get("invalid", errBadRequest)
+	_ = get("invalid", errBadRequest)
+	if n := calls.Load(); n != 4 {
+		t.Errorf("Expected uncacheable failures to be retried, got %d calls", n)
+	}
+
+	want := CacheStats{NegativeHits: 2, Misses: 4}
+	if got := runner.CacheStats(); got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestCacheStats(t *testing.T) {
+	runner := NewAsyncRunner(WithCache(NewLRUCache(10)))
+	fetch := func(ctx context.Context) (int, error) { return 1, nil }
+	get := func() {
+		var v int
+		_ = TaskCached(runner.RunInAsync(), "key", 10*time.Millisecond, &v, fetch, StaleWhileRevalidate(time.Hour)).Go(context.Background())
+	}
+
+	get()
+	get()
+	time.Sleep(20 * time.Millisecond)
+	get()
+
+	got := runner.CacheStats()
+	if got.Misses != 1 || got.Hits != 1 || got.StaleHits != 1 || got.NegativeHits != 0 {
+		t.Errorf("Expected a miss, a hit and a stale hit, got %+v", got)
+	}
+	if got := NewAsyncRunner().CacheStats(); got != (CacheStats{}) {
+		t.Errorf("Expected no stats without a cache, got %+v", got)
+	}
+}
@@ -0,0 +1,75 @@
+package async
+
+import "time"
+
+// Clock is the source of time of a runner. Tests inject a fake clock with
+// WithClock to trigger timeouts, delays and schedules instantly instead of
+// sleeping; asynctest.Clock is one.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer returns a timer sending the current time on its channel once d
+	// has elapsed.
+	NewTimer(d time.Duration) Timer
+	// NewTicker returns a ticker sending the current time on its channel every
+	// d, dropping ticks for slow receivers like time.Ticker.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is a single event timer of a Clock, like time.Timer.
+type Timer interface {
+	// C returns the channel the time is sent on.
+	C() <-chan time.Time
+	// Reset changes the timer to expire after d, reporting whether it was active.
+	Reset(d time.Duration) bool
+	// Stop prevents the timer from firing, reporting whether it was active.
+	Stop() bool
+}
+
+// Ticker is a periodic timer of a Clock, like time.Ticker.
+type Ticker interface {
+	// C returns the channel the ticks are sent on.
+	C() <-chan time.Time
+	// Stop turns the ticker off.
+	Stop()
+}
+
+// WithClock makes the runner measure time with c: batch timeouts, task
+// timeouts, delayed tasks, Every and At schedules, watchdogs and report
+// durations all follow it. Worker pools and triggers keep the real time.
+func WithClock(c Clock) RunnerOption {
+	return func(cfg *runnerConfig) {
+		cfg.clock = c
+	}
+}
+
+// realClock is the Clock backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
@@ -0,0 +1,75 @@
+package async
+
+import (
+	"context"
+	"testing"
+)
+
+type cloneNode struct {
+	Name     string
+	Tags     []string
+	Meta     map[string]any
+	Next     *cloneNode
+	internal []int
+}
+
+func TestClone(t *testing.T) {
+	n := &cloneNode{
+		Name:     "a",
+		Tags:     []string{"x"},
+		Meta:     map[string]any{"k": []int{1}},
+		internal: []int{1},
+	}
+	n.Next = n
+
+	c := clone(n)
+	if c == n || c.Next != c {
+		t.Fatal("Expected a distinct copy preserving the cycle")
+	}
+
+	c.Tags[0] = "y"
+	c.Meta["k"].([]int)[0] = 2
+	if n.Tags[0] != "x" || n.Meta["k"].([]int)[0] != 1 {
+		t.Errorf("Expected the original to be unchanged, got %v and %v", n.Tags, n.Meta)
+	}
+	if &c.internal[0] != &n.internal[0] {
+		t.Error("Expected unexported fields to be copied as is")
+	}
+
+	var err error
+	if clone(err) != nil {
+		t.Error("Expected a nil interface to stay nil")
+	}
+}
+
+func TestWithCopyResults(t *testing.T) {
+	shared := []int{1, 2, 3}
+	fetch := func(ctx context.Context) ([]int, error) {
+		return shared, nil
+	}
+
+	var plain, copied []int
+	err := NewAsyncRunner().RunInAsync().
+		Task(Bind(&plain, fetch)).
+		Go(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	err = NewAsyncRunner().RunInAsync().
+		WithCopyResults().
+		Task(Bind(&copied, fetch)).
+		Go(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	copied[0] = 100
+	if shared[0] != 1 {
+		t.Errorf("Expected the copied result not to alias the source, got %v", shared)
+	}
+	plain[0] = 100
+	if shared[0] != 100 {
+		t.Errorf("Expected results to be stored as is by default, got %v", shared)
+	}
+}
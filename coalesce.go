@@ -0,0 +1,191 @@
+package async
+
+import (
+	"context"
+	"reflect"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// WithCoalescing makes the keyed tasks of the runner — TaskCoalesced,
+// TaskCached and TaskIdempotent — share the outcome of a call with the tasks
+// of the same key arriving up to window after it started, even once it
+// returned, instead of only while it is in flight. Merging the identical
+// tasks of a traffic spike into one call cuts the duplicate load on the
+// dependency, at the cost of results up to window old.
+func WithCoalescing(window time.Duration) RunnerOption {
+	return func(c *runnerConfig) {
+		c.coalesce = window
+	}
+}
+
+// TaskCoalesced adds fn to the batch like Bind, sharing a single call of fn
+// between the concurrent tasks of the runner with the same key, even across
+// batches: tasks arriving while a call is in flight, or within the window set
+// by WithCoalescing, get its result or error instead of calling fn. If the
+// batch of the task making the call is cancelled, the others call fn again.
+// A task sharing the call of a task with another result type fails with
+// ErrFieldType, so give such tasks distinct keys.
+func TaskCoalesced[T any](a Async, key string, dest *T, fn func(ctx context.Context) (T, error)) Async {
+	if fn == nil {
+		return a.Task(nil)
+	}
+	return a.Task(Bind(dest, func(ctx context.Context) (T, error) {
+		return coalesce(ctx, bindStateFrom(ctx).flightsOf(), key, fn)
+	}))
+}
+
+// coalesce returns the outcome of the call shared under key, making it if
+// there is none.
+func coalesce[K comparable, T any](ctx context.Context, g *flightGroup[K], key K, fn func(ctx context.Context) (T, error)) (T, error) {
+	if g == nil {
+		return fn(ctx)
+	}
+	for {
+		f, leader := g.join(key)
+		if leader {
+			return shareCall(ctx, g, f, key, fn)
+		}
+		res, retry, err := wait[T](ctx, f, key)
+		if retry {
+			continue
+		}
+		return res, err
+	}
+}
+
+// flightGroup shares a single call per key between the tasks of a runner.
+type flightGroup[K comparable] struct {
+	// window keeps the outcome of a call for the tasks arriving up to window
+	// after it started
+	window time.Duration
+	delays *delayQueue
+
+	mu sync.Mutex
+	// calls holds the calls in flight, or finished within the window, by key
+	calls map[K]*flight
+	// made, saved and maxFanOut count the calls made, the calls saved by
+	// tasks joining them, and the most tasks sharing one call
+	made, saved, maxFanOut int64
+}
+
+// flight is a call shared by the tasks waiting for its key.
+type flight struct {
+	done chan struct{}
+	// started is set when the group has a window
+	started time.Time
+	// fanOut is the number of tasks sharing the call, its leader included
+	fanOut   int64
+	finished bool
+	val      any
+	err      error
+}
+
+func newFlightGroup[K comparable](window time.Duration, delays *delayQueue) *flightGroup[K] {
+	return &flightGroup[K]{
+		window: window,
+		delays: delays,
+		calls:  make(map[K]*flight),
+	}
+}
+
+// join returns the call shared under key, starting one if there is none, in
+// which case leader is set and the caller must finish it.
+func (g *flightGroup[K]) join(key K) (f *flight, leader bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if f, ok := g.calls[key]; ok && g.shares(f) {
+		f.fanOut++
+		g.saved++
+		g.maxFanOut = max(g.maxFanOut, f.fanOut)
+		return f, false
+	}
+	g.made++
+	g.maxFanOut = max(g.maxFanOut, 1)
+	f = &flight{done: make(chan struct{}), fanOut: 1}
+	if g.window > 0 {
+		f.started = g.delays.clock.Now()
+	}
+	g.calls[key] = f
+	return f, true
+}
+
+// shares reports whether tasks joining f get its outcome: while it is in
+// flight, and once it finished until the window elapsed, unless it was
+// cancelled. It must be called with g.mu held.
+func (g *flightGroup[K]) shares(f *flight) bool {
+	if !f.finished {
+		return true
+	}
+	return g.window > 0 && !isContextError(f.err) && g.delays.clock.Now().Sub(f.started) < g.window
+}
+
+// finish records the outcome of f and hands it to its waiters.
+func (g *flightGroup[K]) finish(key K, f *flight, val any, err error) {
+	g.mu.Lock()
+	f.val, f.err, f.finished = val, err, true
+	if g.shares(f) {
+		// Kept for the tasks arriving within the window
+		g.delays.schedule(f.started.Add(g.window), func() {
+			g.mu.Lock()
+			if g.calls[key] == f {
+				delete(g.calls, key)
+			}
+			g.mu.Unlock()
+		})
+	} else if g.calls[key] == f {
+		delete(g.calls, key)
+	}
+	g.mu.Unlock()
+
+	close(f.done)
+}
+
+// stats adds the counters of the group to s.
+func (g *flightGroup[K]) stats(s *DedupStats) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	s.Calls += g.made
+	s.Saved += g.saved
+	s.MaxFanOut = max(s.MaxFanOut, g.maxFanOut)
+}
+
+// shareCall calls fn on behalf of the waiters of f, handing them its outcome.
+func shareCall[K comparable, T any](ctx context.Context, g *flightGroup[K], f *flight, key K, fn func(ctx context.Context) (T, error)) (res T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			// The waiters get the panic as an error, while the batch of the
+			// leader handles it as usual
+			g.finish(key, f, nil, &PanicError{Value: r, Stack: debug.Stack()})
+			panic(r)
+		}
+		g.finish(key, f, res, err)
+	}()
+	return fn(ctx)
+}
+
+// wait waits for the outcome of f, shared under key. It reports retry if the
+// call was cancelled along with the batch of its leader while ctx is still
+// live, since the call did not really fail. It fails with ErrFieldType if the
+// call returned another type than T, as keys are shared across result types.
+func wait[T any, K comparable](ctx context.Context, f *flight, key K) (res T, retry bool, err error) {
+	select {
+	case <-f.done:
+	case <-ctx.Done():
+		return res, false, ctx.Err()
+	}
+	if f.err != nil {
+		if isContextError(f.err) && ctx.Err() == nil {
+			return res, true, nil
+		}
+		return res, false, f.err
+	}
+	res, ok := f.val.(T)
+	if !ok && (f.val != nil || reflect.TypeFor[T]().Kind() != reflect.Interface) {
+		return res, false, detailed(ErrFieldType, "key %v: shared %T to %s", key, f.val, reflect.TypeFor[T]())
+	}
+	return res, false, nil
+}
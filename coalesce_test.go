@@ -0,0 +1,108 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTaskCoalesced(t *testing.T) {
+	runner := NewAsyncRunner()
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) (int32, error) {
+		<-release
+		return calls.Add(1), nil
+	}
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Go(func() {
+			var v int32
+			if err := TaskCoalesced(runner.RunInAsync(), "user:1", &v, fetch).Go(context.Background()); err != nil || v != 1 {
+				t.Errorf("Expected the shared result, got %d, %v", v, err)
+			}
+		})
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// Without a window, a finished call is not shared
+	var v int32
+	_ = TaskCoalesced(runner.RunInAsync(), "user:1", &v, fetch).Go(context.Background())
+	if v != 2 {
+		t.Errorf("Expected a new call, got %d", v)
+	}
+}
+
+func TestWithCoalescing(t *testing.T) {
+	errDown := errors.New("down")
+	runner := NewAsyncRunner(WithCoalescing(30 * time.Millisecond))
+	var calls atomic.Int32
+	get := func(key string, err error) (int32, error) {
+		var v int32
+		e := TaskCoalesced(runner.RunInAsync(), key, &v, func(ctx context.Context) (int32, error) {
+			return calls.Add(1), err
+		}).Go(context.Background())
+		return v, e
+	}
+
+	if v, _ := get("a", nil); v != 1 {
+		t.Fatalf("Expected the first call, got %d", v)
+	}
+	// Within the window, the finished call is shared
+	if v, _ := get("a", nil); v != 1 {
+		t.Errorf("Expected the shared result, got %d", v)
+	}
+	time.Sleep(40 * time.Millisecond)
+	if v, _ := get("a", nil); v != 2 {
+		t.Errorf("Expected a new call once the window elapsed, got %d", v)
+	}
+
+	// Failures are shared as well
+	if _, err := get("b", errDown); !errors.Is(err, errDown) {
+		t.Fatalf("Expected the failure, got %v", err)
+	}
+	if _, err := get("b", nil); !errors.Is(err, errDown) {
+		t.Errorf("Expected the shared failure, got %v", err)
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("Expected 3 calls, got %d", n)
+	}
+
+	// Cancelled calls are not
+	ctx, cancel := context.WithCancel(context.Background())
+	var v int32
+	err := TaskCoalesced(runner.RunInAsync(), "c", &v, func(ctx context.Context) (int32, error) {
+		cancel()
+		return 0, context.Canceled
+	}).Go(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the call to be cancelled, got %v", err)
+	}
+	if v, err := get("c", nil); err != nil || v != 4 {
+		t.Errorf("Expected a new call after a cancelled one, got %d, %v", v, err)
+	}
+}
+
+func TestTaskCoalescedResultType(t *testing.T) {
+	runner := NewAsyncRunner(WithCoalescing(time.Minute))
+	var a int
+	if err := TaskCoalesced(runner.RunInAsync(), "user:1", &a, func(ctx context.Context) (int, error) {
+		return 1, nil
+	}).Go(context.Background()); err != nil || a != 1 {
+		t.Fatalf("Expected the first call, got %d, %v", a, err)
+	}
+
+	b := "unset"
+	err := TaskCoalesced(runner.RunInAsync(), "user:1", &b, func(ctx context.Context) (string, error) {
+		return "bob", nil
+	}).Go(context.Background())
+	if !errors.Is(err, ErrFieldType) || b != "unset" {
+		t.Errorf("Expected ErrFieldType leaving the destination alone, got %q, %v", b, err)
+	}
+}
@@ -0,0 +1,225 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// errStop is used internally to cancel sibling goroutines once a result is known.
+var errStop = errors.New("async: stop")
+
+// MapKV applies fn concurrently to every entry of m and returns the results keyed
+// by the same keys. It returns the first error encountered, cancelling the
+// remaining calls.
+func MapKV[K comparable, V, R any](ctx context.Context, m map[K]V, fn func(ctx context.Context, key K, value V) (R, error)) (map[K]R, error) {
+	g, ctx := errgroup.WithContext(ctx)
+
+	var mu sync.Mutex
+	out := make(map[K]R, len(m))
+
+	for k, v := range m {
+		g.Go(func() error {
+			return safeCall(ctx, func(ctx context.Context) error {
+				res, err := fn(ctx, k, v)
+				if err != nil {
+					return err
+				}
+				mu.Lock()
+				out[k] = res
+				mu.Unlock()
+				return nil
+			})
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FlatMapSlice expands every item concurrently into zero or more results and
+// concatenates them in input order.
+func FlatMapSlice[T, R any](ctx context.Context, items []T, fn func(ctx context.Context, item T) ([]R, error)) ([]R, error) {
+	g, ctx := errgroup.WithContext(ctx)
+
+	// Each goroutine owns its slot, so no locking is needed
+	parts := make([][]R, len(items))
+
+	for i, item := range items {
+		g.Go(func() error {
+			return safeCall(ctx, func(ctx context.Context) error {
+				res, err := fn(ctx, item)
+				if err != nil {
+					return err
+				}
+				parts[i] = res
+				return nil
+			})
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	total := 0
+	for _, p := range parts {
+		total += len(p)
+	}
+
+	out := make([]R, 0, total)
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out, nil
+}
+
+// GroupBy derives the key of every item concurrently and groups the items by it.
+// Items within a group keep their input order.
+func GroupBy[T any, K comparable](ctx context.Context, items []T, keyFn func(ctx context.Context, item T) (K, error)) (map[K][]T, error) {
+	g, ctx := errgroup.WithContext(ctx)
+
+	keys := make([]K, len(items))
+
+	for i, item := range items {
+		g.Go(func() error {
+			return safeCall(ctx, func(ctx context.Context) error {
+				key, err := keyFn(ctx, item)
+				if err != nil {
+					return err
+				}
+				keys[i] = key
+				return nil
+			})
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	out := make(map[K][]T)
+	for i, item := range items {
+		out[keys[i]] = append(out[keys[i]], item)
+	}
+	return out, nil
+}
+
+// Find probes items concurrently and returns the first item, in completion order,
+// for which pred reports true. Remaining probes are cancelled once a match is found.
+// An error from any probe aborts the search unless a match was already found.
+func Find[T any](ctx context.Context, items []T, pred func(ctx context.Context, item T) (bool, error)) (T, bool, error) {
+	g, ctx := errgroup.WithContext(ctx)
+
+	var (
+		once  sync.Once
+		found T
+	)
+
+	for _, item := range items {
+		g.Go(func() error {
+			return safeCall(ctx, func(ctx context.Context) error {
+				ok, err := pred(ctx, item)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return nil
+				}
+				once.Do(func() {
+					found = item
+				})
+				// Returning errStop cancels the remaining probes
+				return errStop
+			})
+		})
+	}
+
+	var zero T
+	if err := g.Wait(); err != nil {
+		if errors.Is(err, errStop) {
+			return found, true, nil
+		}
+		return zero, false, err
+	}
+	return zero, false, nil
+}
+
+// CountIf evaluates pred concurrently for every item and returns how many items matched.
+func CountIf[T any](ctx context.Context, items []T, pred func(ctx context.Context, item T) (bool, error)) (int, error) {
+	g, ctx := errgroup.WithContext(ctx)
+
+	var count atomic.Int64
+
+	for _, item := range items {
+		g.Go(func() error {
+			return safeCall(ctx, func(ctx context.Context) error {
+				ok, err := pred(ctx, item)
+				if err != nil {
+					return err
+				}
+				if ok {
+					count.Add(1)
+				}
+				return nil
+			})
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return 0, err
+	}
+	return int(count.Load()), nil
+}
+
+// Any reports whether pred holds for at least one item. Sibling evaluations are
+// cancelled as soon as one returns true.
+func Any[T any](ctx context.Context, items []T, pred func(ctx context.Context, item T) (bool, error)) (bool, error) {
+	_, ok, err := Find(ctx, items, pred)
+	return ok, err
+}
+
+// All reports whether pred holds for every item. Sibling evaluations are
+// cancelled as soon as one returns false.
+func All[T any](ctx context.Context, items []T, pred func(ctx context.Context, item T) (bool, error)) (bool, error) {
+	_, failed, err := Find(ctx, items, func(ctx context.Context, item T) (bool, error) {
+		ok, err := pred(ctx, item)
+		return !ok, err
+	})
+	if err != nil {
+		return false, err
+	}
+	return !failed, nil
+}
+
+// Collect runs fns concurrently and returns their results in argument order.
+// It returns the first error encountered, cancelling the remaining calls.
+func Collect[T any](ctx context.Context, fns ...func(ctx context.Context) (T, error)) ([]T, error) {
+	g, ctx := errgroup.WithContext(ctx)
+
+	// Each goroutine owns its slot, so no locking is needed
+	out := make([]T, len(fns))
+
+	for i, fn := range fns {
+		g.Go(func() error {
+			return safeCall(ctx, func(ctx context.Context) error {
+				res, err := fn(ctx)
+				if err != nil {
+					return err
+				}
+				out[i] = res
+				return nil
+			})
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,230 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMapKV(t *testing.T) {
+	regions := map[string]int{"eu": 1, "us": 2, "ap": 3}
+
+	res, err := MapKV(context.Background(), regions, func(ctx context.Context, key string, value int) (string, error) {
+		return strings.Repeat(key, value), nil
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := map[string]string{"eu": "eu", "us": "usus", "ap": "apapap"}
+	if len(res) != len(expected) {
+		t.Fatalf("Expected %d results, got %d", len(expected), len(res))
+	}
+	for k, v := range expected {
+		if res[k] != v {
+			t.Errorf("Expected %s for key %s, got %s", v, k, res[k])
+		}
+	}
+}
+
+func TestMapKVError(t *testing.T) {
+	m := map[int]int{1: 1, 2: 2}
+
+	res, err := MapKV(context.Background(), m, func(ctx context.Context, key int, value int) (int, error) {
+		if key == 2 {
+			return 0, errors.New("tenant failed")
+		}
+		return value, nil
+	})
+
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if res != nil {
+		t.Errorf("Expected nil result on error, got %v", res)
+	}
+}
+
+func TestFlatMapSlice(t *testing.T) {
+	users := []int{3, 1, 2}
+
+	orders, err := FlatMapSlice(context.Background(), users, func(ctx context.Context, user int) ([]string, error) {
+		res := make([]string, 0, user)
+		for i := 0; i < user; i++ {
+			res = append(res, strings.Repeat("o", user))
+		}
+		return res, nil
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{"ooo", "ooo", "ooo", "o", "oo", "oo"}
+	if len(orders) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, orders)
+	}
+	for i := range expected {
+		if orders[i] != expected[i] {
+			t.Errorf("Expected %s at index %d, got %s", expected[i], i, orders[i])
+		}
+	}
+}
+
+func TestFlatMapSliceError(t *testing.T) {
+	_, err := FlatMapSlice(context.Background(), []int{1, 2}, func(ctx context.Context, item int) ([]int, error) {
+		if item == 2 {
+			return nil, errors.New("expand failed")
+		}
+		return []int{item}, nil
+	})
+
+	if err == nil || err.Error() != "expand failed" {
+		t.Errorf("Expected 'expand failed', got %v", err)
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	words := []string{"apple", "kiwi", "avocado", "banana", "blueberry"}
+
+	groups, err := GroupBy(context.Background(), words, func(ctx context.Context, word string) (byte, error) {
+		return word[0], nil
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if got := strings.Join(groups['a'], ","); got != "apple,avocado" {
+		t.Errorf("Expected 'apple,avocado', got %s", got)
+	}
+	if got := strings.Join(groups['b'], ","); got != "banana,blueberry" {
+		t.Errorf("Expected 'banana,blueberry', got %s", got)
+	}
+	if got := strings.Join(groups['k'], ","); got != "kiwi" {
+		t.Errorf("Expected 'kiwi', got %s", got)
+	}
+}
+
+func TestFind(t *testing.T) {
+	replicas := []string{"r1", "r2", "r3"}
+
+	replica, ok, err := Find(context.Background(), replicas, func(ctx context.Context, r string) (bool, error) {
+		return r == "r2", nil
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !ok || replica != "r2" {
+		t.Errorf("Expected to find r2, got %q (found=%v)", replica, ok)
+	}
+}
+
+func TestFindNoMatch(t *testing.T) {
+	res, ok, err := Find(context.Background(), []int{1, 2, 3}, func(ctx context.Context, i int) (bool, error) {
+		return false, nil
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ok || res != 0 {
+		t.Errorf("Expected no match, got %d (found=%v)", res, ok)
+	}
+}
+
+func TestFindCancelsRemainingProbes(t *testing.T) {
+	_, ok, err := Find(context.Background(), []int{1, 2}, func(ctx context.Context, i int) (bool, error) {
+		if i == 1 {
+			return true, nil
+		}
+		// The slow probe only returns once it has been cancelled
+		<-ctx.Done()
+		return false, ctx.Err()
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !ok {
+		t.Error("Expected a match")
+	}
+}
+
+func TestCountIf(t *testing.T) {
+	count, err := CountIf(context.Background(), []int{1, 2, 3, 4, 5}, func(ctx context.Context, i int) (bool, error) {
+		return i%2 == 1, nil
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3, got %d", count)
+	}
+}
+
+func TestAnyAndAll(t *testing.T) {
+	items := []int{2, 4, 5}
+	isEven := func(ctx context.Context, i int) (bool, error) {
+		return i%2 == 0, nil
+	}
+
+	anyEven, err := Any(context.Background(), items, isEven)
+	if err != nil || !anyEven {
+		t.Errorf("Expected Any to be true, got %v (err=%v)", anyEven, err)
+	}
+
+	allEven, err := All(context.Background(), items, isEven)
+	if err != nil || allEven {
+		t.Errorf("Expected All to be false, got %v (err=%v)", allEven, err)
+	}
+
+	allEven, err = All(context.Background(), items[:2], isEven)
+	if err != nil || !allEven {
+		t.Errorf("Expected All to be true, got %v (err=%v)", allEven, err)
+	}
+}
+
+func TestAllShortCircuits(t *testing.T) {
+	ok, err := All(context.Background(), []int{0, 1}, func(ctx context.Context, i int) (bool, error) {
+		if i == 0 {
+			return false, nil
+		}
+		<-ctx.Done()
+		return true, ctx.Err()
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ok {
+		t.Error("Expected All to be false")
+	}
+}
+
+func TestCollect(t *testing.T) {
+	res, err := Collect(context.Background(),
+		func(ctx context.Context) (int, error) { return 1, nil },
+		func(ctx context.Context) (int, error) { return 2, nil },
+		func(ctx context.Context) (int, error) { return 3, nil },
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(res) != 3 || res[0] != 1 || res[1] != 2 || res[2] != 3 {
+		t.Errorf("Expected [1 2 3], got %v", res)
+	}
+
+	boom := errors.New("boom")
+	_, err = Collect(context.Background(),
+		func(ctx context.Context) (int, error) { return 0, boom },
+	)
+	if !errors.Is(err, boom) {
+		t.Errorf("Expected boom, got %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package async
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrPoolDeadlock is returned when a task running on a pool waits for work
+// queued on the same pool while no other worker is free to run it.
+var ErrPoolDeadlock = errors.New("async: task waits on its own saturated pool")
+
+type poolKey struct{}
+
+// onPool marks ctx as belonging to a task running on p.
+func onPool(ctx context.Context, p *workerPool) context.Context {
+	return context.WithValue(ctx, poolKey{}, p)
+}
+
+// checkSelfWait fails when the task owning ctx runs on p and would wait for p
+// to run more work, but every worker is busy and the pool cannot grow. The
+// waiting task holds a worker itself, so if all its siblings do the same the
+// pool never makes progress again.
+func (p *workerPool) checkSelfWait(ctx context.Context) error {
+	if running, _ := ctx.Value(poolKey{}).(*workerPool); running != p {
+		return nil
+	}
+	p.scaleMu.Lock()
+	defer p.scaleMu.Unlock()
+
+	if p.size < p.cfg.maxWorkers || int(p.running.Load()) < p.size {
+		return nil
+	}
+	return ErrPoolDeadlock
+}
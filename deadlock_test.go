@@ -0,0 +1,47 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPoolSelfDeadlock(t *testing.T) {
+	pool := NewWorkerPool(1)
+	defer pool.Shutdown(context.Background())
+
+	err := pool.SubmitWait(context.Background(), func(ctx context.Context) error {
+		return pool.SubmitWait(ctx, func(ctx context.Context) error {
+			return nil
+		})
+	})
+	if !errors.Is(err, ErrPoolDeadlock) {
+		t.Errorf("Expected ErrPoolDeadlock from a nested SubmitWait, got %v", err)
+	}
+
+	err = pool.RunInAsync().
+		Task(func(ctx context.Context) error {
+			return pool.RunInAsync().
+				Task(func(ctx context.Context) error { return nil }).
+				Go(ctx)
+		}).
+		Go(context.Background())
+	if !errors.Is(err, ErrPoolDeadlock) {
+		t.Errorf("Expected ErrPoolDeadlock from a nested batch, got %v", err)
+	}
+}
+
+func TestPoolNestedWaitWithFreeWorker(t *testing.T) {
+	pool := NewWorkerPool(1, WithMaxWorkers(2))
+	defer pool.Shutdown(context.Background())
+
+	// The pool can grow, so waiting on it from one of its tasks is safe
+	err := pool.SubmitWait(context.Background(), func(ctx context.Context) error {
+		return pool.SubmitWait(ctx, func(ctx context.Context) error {
+			return nil
+		})
+	})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
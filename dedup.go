@@ -0,0 +1,50 @@
+package async
+
+// DedupStats counts the work the keyed tasks of a runner — TaskCoalesced,
+// TaskCached and TaskIdempotent — saved by sharing calls and caching results.
+type DedupStats struct {
+	// Calls is the number of calls the keyed tasks made.
+	Calls int64
+	// Saved is the number of tasks that got the outcome of a call made by
+	// another task, in flight or within the WithCoalescing window, instead of
+	// making their own.
+	Saved int64
+	// MaxFanOut is the largest number of tasks that shared a single call.
+	MaxFanOut int64
+	// Cache counts how the TaskCached tasks were served.
+	Cache CacheStats
+}
+
+// FanOut returns the average number of tasks sharing a call, or zero if no
+// call was made.
+func (s DedupStats) FanOut() float64 {
+	if s.Calls == 0 {
+		return 0
+	}
+	return float64(s.Calls+s.Saved) / float64(s.Calls)
+}
+
+// HitRate returns the share of the TaskCached tasks served from the cache,
+// stale and negative hits included, or zero if there was none.
+func (s CacheStats) HitRate() float64 {
+	hits := s.Hits + s.StaleHits + s.NegativeHits
+	if hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+s.Misses)
+}
+
+// DedupStats returns the work the runner's keyed tasks saved so far, for
+// metrics exporters polling it and for tests asserting on deduplication.
+func (a *asyncRunner) DedupStats() DedupStats {
+	var s DedupStats
+	a.flights.stats(&s)
+	if a.cache != nil {
+		a.cache.flights.stats(&s)
+		s.Cache = a.cache.stats()
+	}
+	if a.idempotency != nil {
+		a.idempotency.flights.stats(&s)
+	}
+	return s
+}
@@ -0,0 +1,51 @@
+package async
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDedupStats(t *testing.T) {
+	runner := NewAsyncRunner(WithCache(NewLRUCache(0)))
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) (int, error) {
+		<-release
+		return 1, nil
+	}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() {
+			var v int
+			_ = TaskCoalesced(runner.RunInAsync(), "user:1", &v, fetch).Go(context.Background())
+		})
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for range 3 {
+		var v int
+		_ = TaskCached(runner.RunInAsync(), "user:2", time.Minute, &v, fetch).Go(context.Background())
+	}
+
+	stats := runner.DedupStats()
+	if stats.Calls != 2 || stats.Saved != 3 || stats.MaxFanOut != 4 {
+		t.Errorf("Expected 2 calls saving 3 with a fan-out of 4, got %+v", stats)
+	}
+	if got := stats.FanOut(); got != 2.5 {
+		t.Errorf("Expected an average fan-out of 2.5, got %v", got)
+	}
+	if stats.Cache.Hits != 2 || stats.Cache.Misses != 1 {
+		t.Errorf("Expected 2 hits and 1 miss, got %+v", stats.Cache)
+	}
+	if got := stats.Cache.HitRate(); got < 0.66 || got > 0.67 {
+		t.Errorf("Expected a hit rate of 2/3, got %v", got)
+	}
+
+	if (DedupStats{}).FanOut() != 0 || (CacheStats{}).HitRate() != 0 {
+		t.Error("Expected zero rates without calls")
+	}
+}
@@ -0,0 +1,147 @@
+package async
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// delayItem is an entry of the delay queue. fire must not block, as it runs on
+// the queue's own goroutine.
+type delayItem struct {
+	at    time.Time
+	fire  func()
+	index int
+}
+
+// delayHeap orders delay items by due time.
+type delayHeap []*delayItem
+
+func (h delayHeap) Len() int           { return len(h) }
+func (h delayHeap) Less(i, j int) bool { return h[i].at.Before(h[j].at) }
+func (h delayHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *delayHeap) Push(x any) {
+	item := x.(*delayItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *delayHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.index = -1
+	*h = old[:n-1]
+	return item
+}
+
+// delayQueue fires scheduled callbacks from a single goroutine and timer, so that
+// many pending delayed tasks don't each hold their own timer. The goroutine is
+// only alive while the queue is non-empty.
+type delayQueue struct {
+	clock   Clock
+	mu      sync.Mutex
+	items   delayHeap
+	running bool
+	wake    chan struct{}
+}
+
+func newDelayQueue(clock Clock) *delayQueue {
+	return &delayQueue{
+		clock: clock,
+		wake:  make(chan struct{}, 1),
+	}
+}
+
+// schedule registers fire to be called once at is reached.
+func (q *delayQueue) schedule(at time.Time, fire func()) *delayItem {
+	item := &delayItem{at: at, fire: fire}
+
+	q.mu.Lock()
+	heap.Push(&q.items, item)
+	first := item.index == 0
+	if !q.running {
+		q.running = true
+		go q.loop()
+	} else if first {
+		// The new item is due before the one the loop is waiting for
+		select {
+		case q.wake <- struct{}{}:
+		default:
+		}
+	}
+	q.mu.Unlock()
+
+	return item
+}
+
+// cancel removes a pending item, reporting whether it was removed before firing.
+func (q *delayQueue) cancel(item *delayItem) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if item.index < 0 {
+		return false
+	}
+	heap.Remove(&q.items, item.index)
+	return true
+}
+
+// sleep blocks for d or until ctx is done, whichever happens first.
+func (q *delayQueue) sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	ch := make(chan struct{})
+	item := q.schedule(q.clock.Now().Add(d), func() {
+		close(ch)
+	})
+
+	select {
+	case <-ctx.Done():
+		q.cancel(item)
+		return ctx.Err()
+	case <-ch:
+		return nil
+	}
+}
+
+// loop fires due items until the queue is empty.
+func (q *delayQueue) loop() {
+	timer := q.clock.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		q.mu.Lock()
+		if len(q.items) == 0 {
+			q.running = false
+			q.mu.Unlock()
+			return
+		}
+
+		next := q.items[0]
+		wait := next.at.Sub(q.clock.Now())
+		if wait <= 0 {
+			heap.Pop(&q.items)
+			q.mu.Unlock()
+			next.fire()
+			continue
+		}
+		q.mu.Unlock()
+
+		timer.Reset(wait)
+		select {
+		case <-timer.C():
+		case <-q.wake:
+			timer.Stop()
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDelayQueueFiresInOrder(t *testing.T) {
+	q := newDelayQueue(realClock{})
+
+	var mu sync.Mutex
+	var order []int
+	var wg sync.WaitGroup
+
+	now := time.Now()
+	for i, d := range []time.Duration{30, 10, 20} {
+		wg.Add(1)
+		q.schedule(now.Add(d*time.Millisecond), func() {
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+			wg.Done()
+		})
+	}
+	wg.Wait()
+
+	expected := []int{1, 2, 0}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("Expected firing order %v, got %v", expected, order)
+		}
+	}
+}
+
+func TestDelayQueueCancel(t *testing.T) {
+	q := newDelayQueue(realClock{})
+
+	fired := make(chan struct{}, 1)
+	item := q.schedule(time.Now().Add(20*time.Millisecond), func() {
+		fired <- struct{}{}
+	})
+
+	if !q.cancel(item) {
+		t.Fatal("Expected pending item to be cancelled")
+	}
+	if q.cancel(item) {
+		t.Error("Expected second cancel to report false")
+	}
+
+	select {
+	case <-fired:
+		t.Error("Expected cancelled item not to fire")
+	case <-time.After(40 * time.Millisecond):
+	}
+}
+
+func TestDelayQueueManyPendingItems(t *testing.T) {
+	q := newDelayQueue(realClock{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 1000)
+	for i := 0; i < 1000; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- q.sleep(ctx, time.Duration(i%10)*time.Millisecond)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+}
+
+func TestDelayQueueSleepCancelled(t *testing.T) {
+	q := newDelayQueue(realClock{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := q.sleep(ctx, time.Second); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	q.mu.Lock()
+	pending := len(q.items)
+	q.mu.Unlock()
+	if pending != 0 {
+		t.Errorf("Expected cancelled sleep to leave no pending items, got %d", pending)
+	}
+}
@@ -0,0 +1,26 @@
+package async
+
+import "fmt"
+
+// detailError wraps a sentinel error with detail that is only formatted when
+// the message is requested. Errors built while tasks run use it instead of
+// fmt.Errorf, which formats eagerly and allocates on every failure.
+type detailError struct {
+	err    error
+	format string
+	args   []any
+}
+
+// detailed returns err annotated with format applied to args, such as
+// detailed(ErrTooManyTasks, "limit is %d", n).
+func detailed(err error, format string, args ...any) error {
+	return &detailError{err: err, format: format, args: args}
+}
+
+func (e *detailError) Error() string {
+	return e.err.Error() + ": " + fmt.Sprintf(e.format, e.args...)
+}
+
+func (e *detailError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,22 @@
+package async
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDetailError(t *testing.T) {
+	err := detailed(ErrTooManyTasks, "limit is %d", 3)
+
+	if !errors.Is(err, ErrTooManyTasks) {
+		t.Errorf("Expected error to wrap ErrTooManyTasks, got %v", err)
+	}
+	if got, want := err.Error(), "async: too many tasks: limit is 3"; got != want {
+		t.Errorf("Expected message %q, got %q", want, got)
+	}
+
+	err = detailed(ErrFieldType, "%s to %s", "int", "string")
+	if got, want := err.Error(), "async: result not assignable to field: int to string"; got != want {
+		t.Errorf("Expected message %q, got %q", want, got)
+	}
+}
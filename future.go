@@ -0,0 +1,127 @@
+package async
+
+import (
+	"context"
+	"reflect"
+	"runtime/debug"
+	"sync"
+)
+
+// Future holds the typed result of a function added to a batch with
+// TaskFuture. Its value is available once the batch's Go has returned, or
+// once Done is closed.
+type Future[T any] struct {
+	mu       sync.Mutex
+	val      T
+	ok       bool
+	err      error
+	finished bool
+	// done is only allocated by Done, so futures nobody selects on stay cheap
+	done chan struct{}
+}
+
+// AnyFuture is a future of any result type, as accepted by Select.
+type AnyFuture interface {
+	// Done returns a channel closed once the function finished.
+	Done() <-chan struct{}
+	// Result returns the result of the function and its error. It must only
+	// be called once Done is closed.
+	Result() (any, error)
+}
+
+var _ AnyFuture = (*Future[int])(nil)
+
+// TaskFuture adds fn to the batch and returns a Future receiving its result.
+// Unlike Bind, the result travels back as a typed value instead of through a
+// destination claimed by the batch, which saves the claim bookkeeping.
+func TaskFuture[T any](a Async, fn func(ctx context.Context) (T, error)) *Future[T] {
+	f := new(Future[T])
+	if fn == nil {
+		a.Task(nil)
+		return f
+	}
+	a.Task(func(ctx context.Context) (err error) {
+		var res T
+		defer func() {
+			// Recovered here rather than by the batch, so Done is closed with the panic
+			if r := recover(); r != nil {
+				err = &PanicError{Value: r, Stack: debug.Stack()}
+			}
+			f.finish(res, err)
+		}()
+		res, err = fn(ctx)
+		return err
+	})
+	return f
+}
+
+// finish records the outcome of the function and closes Done.
+func (f *Future[T]) finish(res T, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err == nil {
+		f.val, f.ok = res, true
+	}
+	f.err = err
+	f.finished = true
+	if f.done != nil {
+		close(f.done)
+	}
+}
+
+// Get returns the result of the function, and whether it completed
+// successfully. It must only be called after the batch's Go has returned, or
+// once Done is closed.
+func (f *Future[T]) Get() (T, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.val, f.ok
+}
+
+// Done returns a channel closed once the function finished, successfully or
+// not. A function the batch cancelled before it started never finishes.
+func (f *Future[T]) Done() <-chan struct{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.done == nil {
+		f.done = make(chan struct{})
+		if f.finished {
+			close(f.done)
+		}
+	}
+	return f.done
+}
+
+// Result returns the result of the function as any, along with its error.
+func (f *Future[T]) Result() (any, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.val, f.err
+}
+
+// Select waits for the first of futures to finish and returns its index in
+// the arguments, its result and its error, leaving the others running, like
+// a select statement over a dynamic set of operations. If ctx is done first,
+// it returns -1 and the context's error. Without futures, it returns
+// ErrNoFuncs.
+func Select(ctx context.Context, futures ...AnyFuture) (int, any, error) {
+	if len(futures) == 0 {
+		return -1, nil, ErrNoFuncs
+	}
+
+	// The number of futures is only known at run time, hence reflect.Select
+	cases := make([]reflect.SelectCase, len(futures)+1)
+	for i, f := range futures {
+		cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(f.Done())}
+	}
+	cases[len(futures)] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}
+
+	i, _, _ := reflect.Select(cases)
+	if i == len(futures) {
+		return -1, nil, ctx.Err()
+	}
+	v, err := futures[i].Result()
+	return i, v, err
+}
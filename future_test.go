@@ -0,0 +1,111 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTaskFuture(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	a := runner.RunInAsync()
+	user := TaskFuture(a, func(ctx context.Context) (string, error) {
+		return "alice", nil
+	})
+	count := TaskFuture(a, func(ctx context.Context) (int, error) {
+		return 42, nil
+	})
+	if err := a.Go(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if v, ok := user.Get(); !ok || v != "alice" {
+		t.Errorf("Expected alice, got %q (%v)", v, ok)
+	}
+	if v, ok := count.Get(); !ok || v != 42 {
+		t.Errorf("Expected 42, got %d (%v)", v, ok)
+	}
+}
+
+func TestTaskFutureError(t *testing.T) {
+	runner := NewAsyncRunner()
+	boom := errors.New("boom")
+
+	a := runner.RunInAsync()
+	f := TaskFuture(a, func(ctx context.Context) (int, error) {
+		return 1, boom
+	})
+	if err := a.Go(context.Background()); !errors.Is(err, boom) {
+		t.Fatalf("Expected boom, got %v", err)
+	}
+	if _, ok := f.Get(); ok {
+		t.Error("Expected the future of a failed function not to be completed")
+	}
+
+	b := runner.RunInAsync()
+	TaskFuture[int](b, nil)
+	if err := b.Go(context.Background()); !errors.Is(err, ErrNilTask) {
+		t.Errorf("Expected ErrNilTask, got %v", err)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+
+	a := NewAsyncRunner().RunInAsync()
+	slow := TaskFuture(a, func(ctx context.Context) (string, error) {
+		close(started)
+		<-release
+		return "slow", nil
+	})
+	fast := TaskFuture(a, func(ctx context.Context) (int, error) {
+		<-started
+		return 7, nil
+	})
+	go a.Go(context.Background())
+
+	i, v, err := Select(context.Background(), slow, fast)
+	if i != 1 || v != 7 || err != nil {
+		t.Fatalf("Expected the fast future, got %d, %v and %v", i, v, err)
+	}
+	select {
+	case <-slow.Done():
+		t.Error("Expected the slow future to keep running")
+	default:
+	}
+
+	// A finished future is selected right away
+	if i, v, _ := Select(context.Background(), fast); i != 0 || v != 7 {
+		t.Errorf("Expected the finished future, got %d and %v", i, v)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if i, _, err := Select(ctx, slow); i != -1 || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the context error, got %d and %v", i, err)
+	}
+	if _, _, err := Select(context.Background()); !errors.Is(err, ErrNoFuncs) {
+		t.Errorf("Expected ErrNoFuncs, got %v", err)
+	}
+}
+
+func TestSelectFailure(t *testing.T) {
+	a := NewAsyncRunner().RunInAsync()
+	f := TaskFuture(a, func(ctx context.Context) (int, error) {
+		panic("boom")
+	})
+	_ = a.Go(context.Background())
+
+	_, _, err := Select(context.Background(), f)
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Errorf("Expected the panic, got %v", err)
+	}
+	if _, ok := f.Get(); ok {
+		t.Error("Expected the future of a failed function not to be completed")
+	}
+}
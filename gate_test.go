@@ -0,0 +1,81 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var errNotLeader = errors.New("not leader")
+
+// leaderGate is a Gate admitting runs while leader is set.
+type leaderGate struct {
+	leader   atomic.Bool
+	acquired atomic.Int32
+	released atomic.Int32
+}
+
+func (g *leaderGate) Acquire(ctx context.Context) error {
+	if !g.leader.Load() {
+		return errNotLeader
+	}
+	g.acquired.Add(1)
+	return nil
+}
+
+func (g *leaderGate) Release() {
+	g.released.Add(1)
+}
+
+func TestScheduleWithGate(t *testing.T) {
+	gate := new(leaderGate)
+	var runs atomic.Int32
+	ran := make(chan struct{}, 1)
+	task := NewAsyncRunner().Every(context.Background(), time.Millisecond, func(ctx context.Context) error {
+		runs.Add(1)
+		select {
+		case ran <- struct{}{}:
+		default:
+		}
+		return nil
+	}, WithGate(gate))
+
+	time.Sleep(10 * time.Millisecond)
+	if n := runs.Load(); n != 0 {
+		t.Fatalf("Expected no run without leadership, got %d", n)
+	}
+
+	gate.leader.Store(true)
+	<-ran
+	task.Stop()
+	if a, r := gate.acquired.Load(), gate.released.Load(); a != runs.Load() || r != a {
+		t.Errorf("Expected every run to acquire and release the gate, got %d runs, %d acquired and %d released", runs.Load(), a, r)
+	}
+}
+
+func TestSuperviseWithGate(t *testing.T) {
+	gate := new(leaderGate)
+	task := NewAsyncRunner().Supervise(context.Background(), func(ctx context.Context) error {
+		return nil
+	}, RestartPolicy{Backoff: time.Millisecond}, WithGate(gate))
+
+	time.Sleep(10 * time.Millisecond)
+	if !task.Stats().LastStart.IsZero() {
+		t.Fatal("Expected the task not to start without leadership")
+	}
+
+	gate.leader.Store(true)
+	select {
+	case <-task.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected the task to run once leader")
+	}
+	if gate.acquired.Load() != 1 || gate.released.Load() != 1 {
+		t.Errorf("Expected the gate to be acquired and released once, got %d and %d", gate.acquired.Load(), gate.released.Load())
+	}
+	if n := task.Stats().Restarts; n != 0 {
+		t.Errorf("Expected gate retries not to count as restarts, got %d", n)
+	}
+}
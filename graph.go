@@ -0,0 +1,676 @@
+package async
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Graph defines the contract for building and executing a set of named tasks
+// with dependencies between them.
+type Graph interface {
+	// Node adds a named function to the graph.
+	Node(name string, fn AsyncFunc, opts ...NodeOption) Graph
+	// NodeValue adds a named function producing an output, which is handed to
+	// the nodes depending on it.
+	NodeValue(name string, fn NodeFunc, opts ...NodeOption) Graph
+	// Run executes every node once all of its dependencies have completed,
+	// running independent nodes concurrently.
+	Run(ctx context.Context) error
+	// RunTargets executes only the given nodes and their transitive dependencies.
+	RunTargets(ctx context.Context, targets ...string) error
+	// Output returns the output produced by the named node during the last run.
+	Output(name string) (any, bool)
+	// Validate checks the graph for missing dependencies and cycles without running it.
+	Validate() error
+	// Explain returns a human-readable description of the graph's execution levels,
+	// or of the reason it cannot be executed.
+	Explain() string
+	// DOT exports the graph in Graphviz DOT format, including per-node status
+	// and duration once the graph has run.
+	DOT() string
+	// Mermaid exports the graph as a Mermaid flowchart, including per-node
+	// status and duration once the graph has run.
+	Mermaid() string
+	// Report returns the per-node outcomes of the last run.
+	Report() GraphReport
+}
+
+// NodeReport is the outcome of a single graph node.
+type NodeReport struct {
+	// Name is the node name.
+	Name string
+	// DependsOn lists the node's dependencies.
+	DependsOn []string
+	// Status is the node's lifecycle status.
+	Status TaskStatus
+	// Err is the error returned by the node, if any.
+	Err error
+	// Start and End delimit the node's execution.
+	Start, End time.Time
+	// Duration is how long the node ran.
+	Duration time.Duration
+}
+
+// GraphReport describes the outcome of a graph run.
+type GraphReport struct {
+	// Nodes holds one entry per node, in registration order.
+	Nodes []NodeReport
+}
+
+// CriticalPath returns the chain of dependent nodes that determined the total
+// latency of the run, from the first node to start to the last one to finish.
+// Speeding up nodes off this path does not make the graph complete sooner.
+func (r GraphReport) CriticalPath() []NodeReport {
+	byName := make(map[string]NodeReport, len(r.Nodes))
+	var last *NodeReport
+	for i := range r.Nodes {
+		n := &r.Nodes[i]
+		byName[n.Name] = *n
+		if !n.End.IsZero() && (last == nil || n.End.After(last.End)) {
+			last = n
+		}
+	}
+	if last == nil {
+		return nil
+	}
+
+	// Walk back through the dependency that finished last, i.e. the one the
+	// current node had to wait for
+	path := []NodeReport{*last}
+	for cur := *last; ; {
+		var next *NodeReport
+		for _, dep := range cur.DependsOn {
+			d, ok := byName[dep]
+			if !ok || d.End.IsZero() {
+				continue
+			}
+			if next == nil || d.End.After(next.End) {
+				next = &d
+			}
+		}
+		if next == nil {
+			break
+		}
+		path = append(path, *next)
+		cur = *next
+	}
+
+	// Reverse so the path reads in execution order
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// NodeFunc is a graph node function. It receives the outputs of the node's
+// dependencies and returns its own output.
+type NodeFunc func(ctx context.Context, deps Deps) (any, error)
+
+// Deps gives a node read access to the outputs of its dependencies.
+type Deps struct {
+	values map[string]any
+}
+
+// Get returns the output of the named dependency, or nil if the node doesn't depend on it.
+func (d Deps) Get(name string) any {
+	return d.values[name]
+}
+
+// DepValue returns the output of the named dependency as a T. The boolean is
+// false when the dependency is unknown or its output is not a T.
+func DepValue[T any](deps Deps, name string) (T, bool) {
+	v, ok := deps.values[name].(T)
+	return v, ok
+}
+
+// NodeOption configures a graph node.
+type NodeOption func(*node)
+
+// DependsOn declares that a node may only start once the named nodes have completed.
+func DependsOn(names ...string) NodeOption {
+	return func(n *node) {
+		n.deps = append(n.deps, names...)
+	}
+}
+
+// When makes a node conditional: it only runs if pred reports true once its
+// dependencies have completed. A skipped node produces a nil output and does
+// not prevent its dependents from running.
+func When(pred func(ctx context.Context) bool) NodeOption {
+	return func(n *node) {
+		n.cond = pred
+	}
+}
+
+// CacheKey shares a node's execution with every other node declaring the same
+// key: within one run, the function of whichever of them starts first is called
+// once and its output and error are handed to all of them.
+func CacheKey(key string) NodeOption {
+	return func(n *node) {
+		n.cacheKey = key
+	}
+}
+
+// CycleError is returned when the graph's dependencies form a cycle.
+type CycleError struct {
+	// Path lists the nodes of the cycle, each depending on the next one.
+	// The first node is repeated at the end to close the cycle.
+	Path []string
+}
+
+func (e *CycleError) Error() string {
+	return "async: dependency cycle: " + strings.Join(e.Path, " -> ")
+}
+
+// MissingDependencyError is returned when a node depends on a node that was never registered.
+type MissingDependencyError struct {
+	// Node is the node declaring the dependency.
+	Node string
+	// Missing is the name of the unknown dependency.
+	Missing string
+}
+
+func (e *MissingDependencyError) Error() string {
+	return fmt.Sprintf("async: node %q depends on unknown node %q", e.Node, e.Missing)
+}
+
+// node is a single task of the graph.
+type node struct {
+	name     string
+	fn       NodeFunc
+	deps     []string
+	cond     func(ctx context.Context) bool
+	cacheKey string
+}
+
+// sharedCall holds the single execution shared by nodes with the same cache key.
+type sharedCall struct {
+	once sync.Once
+	out  any
+	err  error
+}
+
+// graph implements the Graph interface.
+type graph struct {
+	nodes map[string]*node
+	order []string
+	err   error
+
+	mu      sync.Mutex
+	outputs map[string]any
+	reports map[string]*NodeReport
+	shared  map[string]*sharedCall
+}
+
+// NewGraph creates an empty dependency graph.
+func NewGraph() Graph {
+	return &graph{
+		nodes: make(map[string]*node),
+	}
+}
+
+// Node registers a node without output. Registering the same name twice makes Run fail.
+func (g *graph) Node(name string, fn AsyncFunc, opts ...NodeOption) Graph {
+	if fn == nil {
+		return g.NodeValue(name, nil, opts...)
+	}
+	return g.NodeValue(name, func(ctx context.Context, _ Deps) (any, error) {
+		return nil, fn(ctx)
+	}, opts...)
+}
+
+// NodeValue registers a node producing an output. Registering the same name twice makes Run fail.
+func (g *graph) NodeValue(name string, fn NodeFunc, opts ...NodeOption) Graph {
+	if _, ok := g.nodes[name]; ok {
+		if g.err == nil {
+			g.err = fmt.Errorf("async: duplicate graph node %q", name)
+		}
+		return g
+	}
+	if fn == nil && g.err == nil {
+		g.err = fmt.Errorf("%w for graph node %q", ErrNilTask, name)
+	}
+
+	n := &node{name: name, fn: fn}
+	for _, opt := range opts {
+		opt(n)
+	}
+	g.nodes[name] = n
+	g.order = append(g.order, name)
+	return g
+}
+
+// Validate checks that every dependency exists and that there are no cycles.
+func (g *graph) Validate() error {
+	if g.err != nil {
+		return g.err
+	}
+
+	for _, name := range g.order {
+		for _, dep := range g.nodes[name].deps {
+			if _, ok := g.nodes[dep]; !ok {
+				return &MissingDependencyError{Node: name, Missing: dep}
+			}
+		}
+	}
+
+	if path := g.findCycle(); path != nil {
+		return &CycleError{Path: path}
+	}
+	return nil
+}
+
+// findCycle returns the path of the first dependency cycle found, or nil.
+func (g *graph) findCycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(g.nodes))
+	var stack []string
+
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		state[name] = visiting
+		stack = append(stack, name)
+
+		for _, dep := range g.nodes[name].deps {
+			switch state[dep] {
+			case visiting:
+				// Back edge: the cycle is the stack from dep onwards
+				for i, n := range stack {
+					if n == dep {
+						path := append([]string(nil), stack[i:]...)
+						return append(path, dep)
+					}
+				}
+			case unvisited:
+				if path := visit(dep); path != nil {
+					return path
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+		return nil
+	}
+
+	for _, name := range g.order {
+		if state[name] == unvisited {
+			if path := visit(name); path != nil {
+				return path
+			}
+		}
+	}
+	return nil
+}
+
+// levels groups the nodes of a valid graph by their depth in the dependency order.
+func (g *graph) levels() [][]string {
+	depth := make(map[string]int, len(g.nodes))
+
+	var visit func(name string) int
+	visit = func(name string) int {
+		if d, ok := depth[name]; ok {
+			return d
+		}
+		d := 0
+		for _, dep := range g.nodes[name].deps {
+			d = max(d, visit(dep)+1)
+		}
+		depth[name] = d
+		return d
+	}
+
+	var out [][]string
+	for _, name := range g.order {
+		d := visit(name)
+		for len(out) <= d {
+			out = append(out, nil)
+		}
+		out[d] = append(out[d], name)
+	}
+	return out
+}
+
+// Explain describes how the graph would be executed, level by level. Nodes of
+// the same level run concurrently once the previous levels have completed.
+func (g *graph) Explain() string {
+	if err := g.Validate(); err != nil {
+		return "graph cannot run: " + err.Error()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "graph with %d nodes in %d levels\n", len(g.nodes), len(g.levels()))
+	for i, level := range g.levels() {
+		fmt.Fprintf(&b, "level %d:\n", i)
+		for _, name := range level {
+			deps := g.nodes[name].deps
+			if len(deps) == 0 {
+				fmt.Fprintf(&b, "  %s\n", name)
+				continue
+			}
+			fmt.Fprintf(&b, "  %s (after %s)\n", name, strings.Join(deps, ", "))
+		}
+	}
+	return b.String()
+}
+
+// DOT renders the graph in Graphviz DOT format, with edges pointing from a
+// dependency to its dependents. After a run, nodes are labelled with their
+// status and duration.
+func (g *graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph async {\n")
+	for _, name := range g.order {
+		if label := g.nodeLabel(name); label != name {
+			fmt.Fprintf(&b, "  %q [label=%q];\n", name, label)
+			continue
+		}
+		fmt.Fprintf(&b, "  %q;\n", name)
+	}
+	for _, name := range g.order {
+		for _, dep := range g.nodes[name].deps {
+			fmt.Fprintf(&b, "  %q -> %q;\n", dep, name)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart. After a run, nodes are
+// labelled with their status and duration.
+func (g *graph) Mermaid() string {
+	ids := make(map[string]string, len(g.order))
+
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	for i, name := range g.order {
+		ids[name] = fmt.Sprintf("n%d", i)
+		label := strings.ReplaceAll(g.nodeLabel(name), "\n", "<br/>")
+		fmt.Fprintf(&b, "  %s[%q]\n", ids[name], label)
+	}
+	for _, name := range g.order {
+		for _, dep := range g.nodes[name].deps {
+			fmt.Fprintf(&b, "  %s --> %s\n", ids[dep], ids[name])
+		}
+	}
+	return b.String()
+}
+
+// dependents maps every node to the nodes depending on it.
+func (g *graph) dependents() map[string][]string {
+	out := make(map[string][]string, len(g.nodes))
+	for _, name := range g.order {
+		for _, dep := range g.nodes[name].deps {
+			out[dep] = append(out[dep], name)
+		}
+	}
+	return out
+}
+
+// Run schedules nodes in topological order with maximum parallelism.
+func (g *graph) Run(ctx context.Context) error {
+	if err := g.Validate(); err != nil {
+		return err
+	}
+	return g.run(ctx, g.order)
+}
+
+// RunTargets runs the target nodes and their transitive dependencies only.
+func (g *graph) RunTargets(ctx context.Context, targets ...string) error {
+	if err := g.Validate(); err != nil {
+		return err
+	}
+
+	needed := make(map[string]bool, len(g.nodes))
+	var visit func(name string)
+	visit = func(name string) {
+		if needed[name] {
+			return
+		}
+		needed[name] = true
+		for _, dep := range g.nodes[name].deps {
+			visit(dep)
+		}
+	}
+	for _, target := range targets {
+		if _, ok := g.nodes[target]; !ok {
+			return fmt.Errorf("async: unknown target node %q", target)
+		}
+		visit(target)
+	}
+
+	// Keep registration order for deterministic scheduling and reports
+	selected := make([]string, 0, len(needed))
+	for _, name := range g.order {
+		if needed[name] {
+			selected = append(selected, name)
+		}
+	}
+	return g.run(ctx, selected)
+}
+
+// run executes the selected nodes, which must include all their dependencies.
+func (g *graph) run(ctx context.Context, selected []string) error {
+	eg, ctx := errgroup.WithContext(ctx)
+
+	sp, ctx := newGroupSpawner(ctx, eg, safeCall)
+
+	g.mu.Lock()
+	g.outputs = make(map[string]any, len(selected))
+	g.reports = make(map[string]*NodeReport, len(selected))
+	g.shared = make(map[string]*sharedCall)
+	for _, name := range selected {
+		g.reports[name] = &NodeReport{Name: name, DependsOn: g.nodes[name].deps}
+	}
+	g.mu.Unlock()
+
+	var mu sync.Mutex
+	dependents := g.dependents()
+	remaining := make(map[string]int, len(selected))
+	for _, name := range selected {
+		remaining[name] = len(g.nodes[name].deps)
+	}
+
+	var start func(name string)
+	start = func(name string) {
+		n := g.nodes[name]
+		eg.Go(func() error {
+			if err := g.runNode(ctx, n); err != nil {
+				return err
+			}
+
+			// Release the dependents whose dependencies are now all complete
+			mu.Lock()
+			var ready []string
+			for _, d := range dependents[name] {
+				if _, ok := remaining[d]; !ok {
+					// Not part of this run
+					continue
+				}
+				remaining[d]--
+				if remaining[d] == 0 {
+					ready = append(ready, d)
+				}
+			}
+			mu.Unlock()
+
+			for _, d := range ready {
+				start(d)
+			}
+			return nil
+		})
+	}
+
+	// Collect the roots before starting anything, as started nodes update remaining
+	var roots []string
+	for _, name := range selected {
+		if remaining[name] == 0 {
+			roots = append(roots, name)
+		}
+	}
+	for _, name := range roots {
+		start(name)
+	}
+
+	err := sp.wait()
+
+	// Nodes that never started were held back by a failure
+	g.mu.Lock()
+	for _, r := range g.reports {
+		if r.Status == StatusPending {
+			r.Status = StatusCanceled
+		}
+	}
+	g.mu.Unlock()
+
+	return err
+}
+
+// runNode executes a single node, recording its output and outcome.
+func (g *graph) runNode(ctx context.Context, n *node) error {
+	deps := g.deps(n)
+
+	var (
+		start   time.Time
+		skipped bool
+	)
+	err := safeCall(ctx, func(ctx context.Context) error {
+		if n.cond != nil && !n.cond(ctx) {
+			skipped = true
+			return nil
+		}
+		start = time.Now()
+		g.setStatus(n.name, StatusRunning, start)
+
+		out, err := g.call(ctx, n, deps)
+		if err != nil {
+			return err
+		}
+		g.mu.Lock()
+		g.outputs[n.name] = out
+		g.mu.Unlock()
+		return nil
+	})
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	r := g.reports[n.name]
+	r.Err = err
+	switch {
+	case skipped:
+		r.Status = StatusSkipped
+	case start.IsZero():
+		r.Status = StatusCanceled
+	case err != nil:
+		r.Status = StatusFailed
+	default:
+		r.Status = StatusSucceeded
+	}
+	if !start.IsZero() {
+		r.End = time.Now()
+		r.Duration = r.End.Sub(start)
+	}
+	return err
+}
+
+// call invokes the node function, deduplicating it by cache key within the run.
+func (g *graph) call(ctx context.Context, n *node, deps Deps) (any, error) {
+	if n.cacheKey == "" {
+		return n.fn(ctx, deps)
+	}
+
+	g.mu.Lock()
+	sc, ok := g.shared[n.cacheKey]
+	if !ok {
+		sc = &sharedCall{}
+		g.shared[n.cacheKey] = sc
+	}
+	g.mu.Unlock()
+
+	sc.once.Do(func() {
+		// A panic would leave the nodes sharing the call with no output and
+		// no error, so it is recovered here for all of them
+		defer func() {
+			if r := recover(); r != nil {
+				sc.err = &PanicError{Value: r, Stack: debug.Stack()}
+			}
+		}()
+		sc.out, sc.err = n.fn(ctx, deps)
+	})
+	return sc.out, sc.err
+}
+
+// setStatus updates the status of a node.
+func (g *graph) setStatus(name string, status TaskStatus, start time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.reports[name].Status = status
+	g.reports[name].Start = start
+}
+
+// Report returns the per-node outcomes of the last run, in registration order.
+// Nodes left out of a RunTargets call are not included.
+func (g *graph) Report() GraphReport {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var out GraphReport
+	if g.reports == nil {
+		return out
+	}
+	for _, name := range g.order {
+		if r, ok := g.reports[name]; ok {
+			out.Nodes = append(out.Nodes, *r)
+		}
+	}
+	return out
+}
+
+// nodeLabel returns the label of a node, annotated with its last run outcome.
+func (g *graph) nodeLabel(name string) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	r, ok := g.reports[name]
+	if !ok {
+		return name
+	}
+	if r.Status == StatusSucceeded || r.Status == StatusFailed {
+		return fmt.Sprintf("%s\n%s %s", name, r.Status, r.Duration.Round(time.Microsecond))
+	}
+	return fmt.Sprintf("%s\n%s", name, r.Status)
+}
+
+// deps snapshots the outputs of the node's dependencies, which have all completed.
+func (g *graph) deps(n *node) Deps {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	values := make(map[string]any, len(n.deps))
+	for _, dep := range n.deps {
+		values[dep] = g.outputs[dep]
+	}
+	return Deps{values: values}
+}
+
+// Output returns the output of the named node from the last run.
+func (g *graph) Output(name string) (any, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	v, ok := g.outputs[name]
+	return v, ok
+}
@@ -0,0 +1,445 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGraphRunsInDependencyOrder(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) AsyncFunc {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	err := NewGraph().
+		Node("summary", record("summary"), DependsOn("orders", "profile")).
+		Node("user", record("user")).
+		Node("orders", record("orders"), DependsOn("user")).
+		Node("profile", record("profile"), DependsOn("user")).
+		Run(context.Background())
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	pos := make(map[string]int)
+	for i, name := range order {
+		pos[name] = i
+	}
+	if len(pos) != 4 {
+		t.Fatalf("Expected 4 nodes to run, got %v", order)
+	}
+	if pos["user"] > pos["orders"] || pos["user"] > pos["profile"] {
+		t.Errorf("Expected user to run first, got %v", order)
+	}
+	if pos["summary"] != 3 {
+		t.Errorf("Expected summary to run last, got %v", order)
+	}
+}
+
+func TestGraphRunsIndependentNodesConcurrently(t *testing.T) {
+	start := time.Now()
+	sleepy := func(ctx context.Context) error {
+		time.Sleep(30 * time.Millisecond)
+		return nil
+	}
+
+	err := NewGraph().
+		Node("a", sleepy).
+		Node("b", sleepy).
+		Node("c", sleepy).
+		Run(context.Background())
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 80*time.Millisecond {
+		t.Errorf("Expected independent nodes to run concurrently, took %v", elapsed)
+	}
+}
+
+func TestGraphCycle(t *testing.T) {
+	noop := func(ctx context.Context) error { return nil }
+
+	err := NewGraph().
+		Node("a", noop, DependsOn("c")).
+		Node("b", noop, DependsOn("a")).
+		Node("c", noop, DependsOn("b")).
+		Node("d", noop).
+		Run(context.Background())
+
+	var cycleErr *CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected *CycleError, got %v", err)
+	}
+
+	expected := "async: dependency cycle: a -> c -> b -> a"
+	if err.Error() != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, err.Error())
+	}
+}
+
+func TestGraphMissingDependency(t *testing.T) {
+	err := NewGraph().
+		Node("a", func(ctx context.Context) error { return nil }, DependsOn("ghost")).
+		Run(context.Background())
+
+	var missingErr *MissingDependencyError
+	if !errors.As(err, &missingErr) {
+		t.Fatalf("Expected *MissingDependencyError, got %v", err)
+	}
+	if missingErr.Node != "a" || missingErr.Missing != "ghost" {
+		t.Errorf("Expected a -> ghost, got %s -> %s", missingErr.Node, missingErr.Missing)
+	}
+}
+
+func TestGraphExplainAndDOT(t *testing.T) {
+	noop := func(ctx context.Context) error { return nil }
+
+	g := NewGraph().
+		Node("user", noop).
+		Node("orders", noop, DependsOn("user")).
+		Node("profile", noop, DependsOn("user")).
+		Node("summary", noop, DependsOn("orders", "profile"))
+
+	explain := `graph with 4 nodes in 3 levels
+level 0:
+  user
+level 1:
+  orders (after user)
+  profile (after user)
+level 2:
+  summary (after orders, profile)
+`
+	if got := g.Explain(); got != explain {
+		t.Errorf("Unexpected explanation:\n%s", got)
+	}
+
+	dot := `digraph async {
+  "user";
+  "orders";
+  "profile";
+  "summary";
+  "user" -> "orders";
+  "user" -> "profile";
+  "orders" -> "summary";
+  "profile" -> "summary";
+}
+`
+	if got := g.DOT(); got != dot {
+		t.Errorf("Unexpected DOT output:\n%s", got)
+	}
+
+	g.Node("user-again", noop, DependsOn("summary"))
+	g.Node("loop", noop, DependsOn("loop"))
+	if got := g.Explain(); got != "graph cannot run: async: dependency cycle: loop -> loop" {
+		t.Errorf("Expected explanation of the cycle, got %q", got)
+	}
+}
+
+func TestGraphErrorStopsDependents(t *testing.T) {
+	called := false
+
+	err := NewGraph().
+		Node("user", func(ctx context.Context) error {
+			return errors.New("user not found")
+		}).
+		Node("orders", func(ctx context.Context) error {
+			called = true
+			return nil
+		}, DependsOn("user")).
+		Run(context.Background())
+
+	if err == nil || err.Error() != "user not found" {
+		t.Errorf("Expected 'user not found', got %v", err)
+	}
+	if called {
+		t.Error("Expected dependent node not to run")
+	}
+}
+
+func TestGraphPassesDependencyOutputs(t *testing.T) {
+	type User struct {
+		ID   int
+		Name string
+	}
+
+	g := NewGraph().
+		NodeValue("user", func(ctx context.Context, deps Deps) (any, error) {
+			return User{ID: 7, Name: "Alice"}, nil
+		}).
+		NodeValue("orders", func(ctx context.Context, deps Deps) (any, error) {
+			user, ok := DepValue[User](deps, "user")
+			if !ok {
+				return nil, errors.New("user output missing")
+			}
+			return []int{user.ID * 10, user.ID * 100}, nil
+		}, DependsOn("user")).
+		NodeValue("summary", func(ctx context.Context, deps Deps) (any, error) {
+			user := deps.Get("user").(User)
+			orders := deps.Get("orders").([]int)
+			return fmt.Sprintf("%s:%v", user.Name, orders), nil
+		}, DependsOn("user", "orders"))
+
+	if err := g.Run(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	summary, ok := g.Output("summary")
+	if !ok {
+		t.Fatal("Expected summary output")
+	}
+	if summary != "Alice:[70 700]" {
+		t.Errorf("Expected 'Alice:[70 700]', got %v", summary)
+	}
+}
+
+func TestGraphDepsOnlyExposeDeclaredDependencies(t *testing.T) {
+	var seen any = "unset"
+
+	err := NewGraph().
+		NodeValue("a", func(ctx context.Context, deps Deps) (any, error) {
+			return 1, nil
+		}).
+		NodeValue("b", func(ctx context.Context, deps Deps) (any, error) {
+			seen = deps.Get("a")
+			return nil, nil
+		}).
+		Run(context.Background())
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if seen != nil {
+		t.Errorf("Expected undeclared dependency to be nil, got %v", seen)
+	}
+}
+
+func TestGraphConditionalNode(t *testing.T) {
+	var ran bool
+
+	g := NewGraph().
+		NodeValue("flag", func(ctx context.Context, deps Deps) (any, error) {
+			return "beta", nil
+		}, When(func(ctx context.Context) bool { return false })).
+		NodeValue("after", func(ctx context.Context, deps Deps) (any, error) {
+			ran = true
+			return deps.Get("flag"), nil
+		}, DependsOn("flag"))
+
+	if err := g.Run(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !ran {
+		t.Error("Expected dependent of a skipped node to run")
+	}
+	if out, _ := g.Output("after"); out != nil {
+		t.Errorf("Expected skipped node output to be nil, got %v", out)
+	}
+}
+
+func TestGraphReportAndAnnotatedExports(t *testing.T) {
+	g := NewGraph().
+		Node("user", func(ctx context.Context) error {
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		}).
+		Node("orders", func(ctx context.Context) error {
+			return errors.New("orders down")
+		}, DependsOn("user")).
+		Node("summary", func(ctx context.Context) error {
+			return nil
+		}, DependsOn("orders")).
+		Node("beta", func(ctx context.Context) error {
+			return nil
+		}, When(func(ctx context.Context) bool { return false }))
+
+	if err := g.Run(context.Background()); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	expected := map[string]TaskStatus{
+		"user":    StatusSucceeded,
+		"orders":  StatusFailed,
+		"summary": StatusCanceled,
+		"beta":    StatusSkipped,
+	}
+	report := g.Report()
+	if len(report.Nodes) != len(expected) {
+		t.Fatalf("Expected %d node reports, got %d", len(expected), len(report.Nodes))
+	}
+	for _, n := range report.Nodes {
+		if n.Status != expected[n.Name] {
+			t.Errorf("Expected %s to be %v, got %v", n.Name, expected[n.Name], n.Status)
+		}
+	}
+	if report.Nodes[0].Duration < 5*time.Millisecond {
+		t.Errorf("Expected user duration of at least 5ms, got %v", report.Nodes[0].Duration)
+	}
+
+	dot := g.DOT()
+	if !strings.Contains(dot, `"user" [label="user\nsucceeded `) {
+		t.Errorf("Expected DOT to include the user status, got:\n%s", dot)
+	}
+	if !strings.Contains(dot, `"summary" [label="summary\ncanceled"]`) {
+		t.Errorf("Expected DOT to include the summary status, got:\n%s", dot)
+	}
+
+	mermaid := g.Mermaid()
+	if !strings.HasPrefix(mermaid, "flowchart TD\n  n0[\"user<br/>succeeded ") {
+		t.Errorf("Unexpected Mermaid output:\n%s", mermaid)
+	}
+	if !strings.Contains(mermaid, "  n0 --> n1\n") {
+		t.Errorf("Expected Mermaid edge from user to orders, got:\n%s", mermaid)
+	}
+}
+
+func TestGraphCacheKeySharesExecution(t *testing.T) {
+	var calls atomic.Int32
+	fetchConfig := func(ctx context.Context, deps Deps) (any, error) {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return "config-v2", nil
+	}
+
+	g := NewGraph().
+		NodeValue("pricingConfig", fetchConfig, CacheKey("config")).
+		NodeValue("shippingConfig", fetchConfig, CacheKey("config")).
+		NodeValue("other", func(ctx context.Context, deps Deps) (any, error) {
+			calls.Add(1)
+			return "other", nil
+		})
+
+	if err := g.Run(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 calls (one shared), got %d", calls.Load())
+	}
+	pricing, _ := g.Output("pricingConfig")
+	shipping, _ := g.Output("shippingConfig")
+	if pricing != "config-v2" || shipping != "config-v2" {
+		t.Errorf("Expected both nodes to get the shared output, got %v and %v", pricing, shipping)
+	}
+
+	// The cache only lives for a single run
+	if err := g.Run(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if calls.Load() != 4 {
+		t.Errorf("Expected the shared call to run again on the next run, got %d calls", calls.Load())
+	}
+}
+
+func TestGraphCacheKeyPanic(t *testing.T) {
+	boom := func(ctx context.Context, deps Deps) (any, error) {
+		panic("boom")
+	}
+	g := NewGraph().
+		NodeValue("first", boom, CacheKey("k")).
+		NodeValue("second", boom, CacheKey("k")).(*graph)
+	g.shared = make(map[string]*sharedCall)
+
+	// The node that ran the call and the one reusing it both see the panic
+	for _, name := range []string{"first", "second"} {
+		out, err := g.call(context.Background(), g.nodes[name], Deps{})
+		var panicErr *PanicError
+		if !errors.As(err, &panicErr) || panicErr.Value != "boom" {
+			t.Errorf("Expected %s to get the *PanicError, got %v", name, err)
+		}
+		if out != nil {
+			t.Errorf("Expected no output for %s, got %v", name, out)
+		}
+	}
+
+	if err := g.Run(context.Background()); !errors.As(err, new(*PanicError)) {
+		t.Errorf("Expected Run to fail with the panic, got %v", err)
+	}
+}
+
+func TestGraphRunTargets(t *testing.T) {
+	var mu sync.Mutex
+	ran := make(map[string]bool)
+	record := func(name string) AsyncFunc {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			ran[name] = true
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	g := NewGraph().
+		Node("user", record("user")).
+		Node("orders", record("orders"), DependsOn("user")).
+		Node("ordersSummary", record("ordersSummary"), DependsOn("orders")).
+		Node("recommendations", record("recommendations"), DependsOn("user"))
+
+	if err := g.RunTargets(context.Background(), "ordersSummary"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, name := range []string{"user", "orders", "ordersSummary"} {
+		if !ran[name] {
+			t.Errorf("Expected %s to run", name)
+		}
+	}
+	if ran["recommendations"] {
+		t.Error("Expected recommendations not to run")
+	}
+	if n := len(g.Report().Nodes); n != 3 {
+		t.Errorf("Expected 3 node reports, got %d", n)
+	}
+
+	if err := g.RunTargets(context.Background(), "ghost"); err == nil {
+		t.Error("Expected error for unknown target, got nil")
+	}
+}
+
+func TestGraphCriticalPath(t *testing.T) {
+	work := func(d time.Duration) AsyncFunc {
+		return func(ctx context.Context) error {
+			time.Sleep(d)
+			return nil
+		}
+	}
+
+	g := NewGraph().
+		Node("user", work(5*time.Millisecond)).
+		Node("orders", work(30*time.Millisecond), DependsOn("user")).
+		Node("profile", work(1*time.Millisecond), DependsOn("user")).
+		Node("config", work(1*time.Millisecond)).
+		Node("summary", work(5*time.Millisecond), DependsOn("orders", "profile", "config"))
+
+	if err := g.Run(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	path := g.Report().CriticalPath()
+
+	var names []string
+	for _, n := range path {
+		names = append(names, n.Name)
+	}
+	if got := strings.Join(names, " -> "); got != "user -> orders -> summary" {
+		t.Errorf("Expected critical path 'user -> orders -> summary', got '%s'", got)
+	}
+
+	if len(GraphReport{}.CriticalPath()) != 0 {
+		t.Error("Expected empty critical path for an empty report")
+	}
+}
@@ -0,0 +1,181 @@
+package async
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+)
+
+// executor runs functions on behalf of a group. It returns an error when the
+// function could not be scheduled, in which case it is never called; waiting
+// to schedule it gives up once ctx is done. If a scheduled function is
+// dropped before it starts, abandon is called instead with the reason.
+type executor interface {
+	execute(ctx context.Context, fn func(), abandon func(error)) error
+}
+
+// inline is an executor running functions on the calling goroutine.
+type inline struct{}
+
+func (inline) execute(ctx context.Context, fn func(), abandon func(error)) error {
+	fn()
+	return nil
+}
+
+// sequential is an executor queueing functions until drain runs them one
+// after another on the calling goroutine.
+type sequential struct {
+	mu    sync.Mutex
+	queue []func()
+	// rng, if set, makes drain pick the next function at random
+	rng *rand.Rand
+}
+
+func (s *sequential) execute(ctx context.Context, fn func(), abandon func(error)) error {
+	s.mu.Lock()
+	s.queue = append(s.queue, fn)
+	s.mu.Unlock()
+	return nil
+}
+
+// drain runs queued functions in order, or in the order drawn from rng,
+// including those queued meanwhile, until the queue is empty.
+func (s *sequential) drain() {
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			s.mu.Unlock()
+			return
+		}
+		if s.rng != nil {
+			i := s.rng.IntN(len(s.queue))
+			s.queue[0], s.queue[i] = s.queue[i], s.queue[0]
+		}
+		fn := s.queue[0]
+		s.queue[0] = nil
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+
+		fn()
+	}
+}
+
+// group is a minimal errgroup whose functions may be run by an executor
+// instead of fresh goroutines. The first error cancels the group's context.
+type group struct {
+	ctx    context.Context
+	exec   executor
+	cancel context.CancelFunc
+	// sem bounds the number of functions running at once, if set
+	sem chan struct{}
+
+	// Without an executor, a limited group queues its functions for at most
+	// cap(sem) goroutines, so memory scales with the limit, not the task count
+	qmu     sync.Mutex
+	queue   []func()
+	workers int
+
+	wg   sync.WaitGroup
+	once sync.Once
+	err  error
+}
+
+// newGroup returns a group running its functions on exec, or on new goroutines
+// if exec is nil. A positive limit bounds how many functions run at once.
+func newGroup(ctx context.Context, exec executor, limit int) (*group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	g := &group{ctx: ctx, exec: exec, cancel: cancel}
+	if limit > 0 {
+		g.sem = make(chan struct{}, limit)
+	}
+	return g, ctx
+}
+
+// Go runs fn as part of the group.
+func (g *group) Go(fn func() error) {
+	g.GoOn(g.exec, fn)
+}
+
+// GoOn runs fn as part of the group on exec instead of the group's executor.
+func (g *group) GoOn(exec executor, fn func() error) {
+	g.wg.Add(1)
+
+	run := func() {
+		defer g.wg.Done()
+		if g.sem != nil {
+			// Once the group is cancelled fn still runs, to observe the cancellation
+			select {
+			case g.sem <- struct{}{}:
+				defer func() { <-g.sem }()
+			case <-g.ctx.Done():
+			}
+		}
+		if err := fn(); err != nil {
+			g.fail(err)
+		}
+	}
+
+	if exec == nil {
+		if g.sem != nil {
+			g.enqueue(run)
+			return
+		}
+		go run()
+		return
+	}
+	abandon := func(err error) {
+		defer g.wg.Done()
+		g.fail(err)
+	}
+	if err := exec.execute(g.ctx, run, abandon); err != nil {
+		g.wg.Done()
+		g.fail(err)
+	}
+}
+
+// enqueue queues run, starting a goroutine to pull it if the limit allows.
+func (g *group) enqueue(run func()) {
+	g.qmu.Lock()
+	g.queue = append(g.queue, run)
+	if g.workers == cap(g.sem) {
+		g.qmu.Unlock()
+		return
+	}
+	g.workers++
+	g.qmu.Unlock()
+
+	go g.work()
+}
+
+// work runs queued functions until the queue is empty.
+func (g *group) work() {
+	for {
+		g.qmu.Lock()
+		if len(g.queue) == 0 {
+			g.workers--
+			g.qmu.Unlock()
+			return
+		}
+		run := g.queue[0]
+		g.queue[0] = nil
+		g.queue = g.queue[1:]
+		g.qmu.Unlock()
+
+		run()
+	}
+}
+
+// fail records the first error and cancels the group.
+func (g *group) fail(err error) {
+	g.once.Do(func() {
+		g.err = err
+		g.cancel()
+	})
+}
+
+// Wait blocks until all functions have returned and returns the first error.
+func (g *group) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+	return g.err
+}
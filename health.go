@@ -0,0 +1,76 @@
+package async
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// Kinds of background tasks reported by Health.
+const (
+	KindScheduled  = "scheduled"
+	KindSupervised = "supervised"
+)
+
+// Health is a point-in-time snapshot of a runner's background work, for
+// liveness and readiness probes.
+type Health struct {
+	// ShuttingDown reports whether Shutdown has begun.
+	ShuttingDown bool
+	// Pools holds the stats of the pools registered with WithPool, by name.
+	Pools map[string]PoolStats
+	// Background holds the scheduled and supervised tasks still running, in
+	// the order they were created.
+	Background []BackgroundHealth
+}
+
+// BackgroundHealth is the health of a scheduled or supervised task.
+type BackgroundHealth struct {
+	// Name is the name set with WithName, if any.
+	Name string
+	// Kind is KindScheduled or KindSupervised.
+	Kind string
+	// Running reports whether a run is in progress.
+	Running bool
+	// Paused reports whether a scheduled task is paused.
+	Paused bool
+	// Runs is the number of runs started so far.
+	Runs int
+	// LastRun is when the last run started.
+	LastRun time.Time
+	// LastErr is the error of the last failed run, if any.
+	LastErr error
+	// LastFailure is when the last run failed.
+	LastFailure time.Time
+	// Restarts is the number of times a supervised task was restarted.
+	Restarts int
+}
+
+// Health returns a snapshot of the runner's pools and background tasks.
+func (a *asyncRunner) Health() Health {
+	h := Health{Pools: make(map[string]PoolStats, len(a.cfg.pools))}
+	for name, pool := range a.cfg.pools {
+		h.Pools[name] = pool.Stats()
+	}
+
+	d := a.drain
+	d.mu.Lock()
+	h.ShuttingDown = d.closing
+	type entry struct {
+		seq uint64
+		t   background
+	}
+	entries := make([]entry, 0, len(d.background))
+	for t, seq := range d.background {
+		entries = append(entries, entry{seq, t})
+	}
+	d.mu.Unlock()
+
+	slices.SortFunc(entries, func(x, y entry) int {
+		return cmp.Compare(x.seq, y.seq)
+	})
+	for _, e := range entries {
+		h.Background = append(h.Background, e.t.snapshot())
+	}
+	return h
+}
@@ -0,0 +1,63 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRunnerHealth(t *testing.T) {
+	errBroken := errors.New("broken")
+	runner := NewAsyncRunner(WithPool("db", NewWorkerPool(2)))
+
+	failed := make(chan struct{})
+	report := runner.At(context.Background(), runner.(*asyncRunner).cfg.clock.Now(), func(ctx context.Context) error {
+		return errBroken
+	}, WithName("sync"), WithErrorHandler(func(error) { close(failed) }))
+	<-failed
+	report.Stop()
+
+	started := make(chan struct{})
+	consumer := runner.Supervise(context.Background(), func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}, RestartPolicy{}, WithName("consumer"))
+	<-started
+
+	h := runner.Health()
+	if h.ShuttingDown {
+		t.Error("Expected the runner not to be shutting down")
+	}
+	if stats, ok := h.Pools["db"]; !ok || stats.Workers != 2 {
+		t.Errorf("Expected the db pool stats, got %+v", h.Pools)
+	}
+	if len(h.Background) != 1 {
+		t.Fatalf("Expected the finished schedule to be dropped, got %+v", h.Background)
+	}
+	if b := h.Background[0]; b.Name != "consumer" || b.Kind != KindSupervised || !b.Running || b.Runs != 1 {
+		t.Errorf("Expected the running consumer, got %+v", b)
+	}
+
+	consumer.Stop()
+	_ = runner.Shutdown(context.Background())
+	if h := runner.Health(); !h.ShuttingDown || len(h.Background) != 0 {
+		t.Errorf("Expected an empty shutting down runner, got %+v", h)
+	}
+}
+
+func TestScheduleHealth(t *testing.T) {
+	errBroken := errors.New("broken")
+	s, ctx := newScheduledTask(context.Background(), func(ctx context.Context) error {
+		return errBroken
+	}, []ScheduleOption{WithName("report")})
+	s.run(ctx)
+
+	h := s.snapshot()
+	if h.Name != "report" || h.Kind != KindScheduled || h.Runs != 1 || h.Running {
+		t.Errorf("Expected one finished run, got %+v", h)
+	}
+	if !errors.Is(h.LastErr, errBroken) || h.LastRun.IsZero() || h.LastFailure.IsZero() {
+		t.Errorf("Expected the last failure, got %+v", h)
+	}
+}
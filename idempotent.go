@@ -0,0 +1,171 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ErrNoIdempotencyStore is returned by a TaskIdempotent task of a runner
+// created without WithIdempotencyStore.
+var ErrNoIdempotencyStore = errors.New("async: no idempotency store configured")
+
+// IdempotencyStore records the keys of the TaskIdempotent tasks that
+// completed, with their results. NewMemoryIdempotencyStore is an in-memory
+// implementation; a shared one makes the keys hold across instances.
+type IdempotencyStore interface {
+	// Load returns the result recorded for key, and whether key completed.
+	Load(ctx context.Context, key string) (any, bool, error)
+	// Save records that key completed with result.
+	Save(ctx context.Context, key string, result any) error
+}
+
+// WithIdempotencyStore makes the runner record the completed TaskIdempotent
+// keys in store.
+func WithIdempotencyStore(store IdempotencyStore) RunnerOption {
+	return func(c *runnerConfig) {
+		c.idempotencyStore = store
+	}
+}
+
+// TaskIdempotent adds fn to the batch like Bind, recording key in the
+// runner's idempotency store once fn succeeds. A task whose key already
+// completed skips fn and stores the recorded result in dest instead, so a
+// retried or re-run batch doesn't charge a card or send an email twice.
+// Concurrent tasks of the runner with the same key share a single call.
+// Failures are not recorded, so the next run calls fn again. The task fails
+// if the store can't tell whether key completed, or can't record that it did,
+// and with ErrFieldType if the recorded result is not a T; Go fails with
+// ErrNoIdempotencyStore if the runner has no store.
+func TaskIdempotent[T any](a Async, key string, dest *T, fn func(ctx context.Context) (T, error)) Async {
+	if fn == nil {
+		return a.Task(nil)
+	}
+	return a.Task(Bind(dest, func(ctx context.Context) (T, error) {
+		return once(ctx, bindStateFrom(ctx).idempotencyOf(), key, fn)
+	}))
+}
+
+// idempotency backs the TaskIdempotent tasks of a runner.
+type idempotency struct {
+	store   IdempotencyStore
+	flights *flightGroup[string]
+}
+
+// once returns the result recorded for key, or calls fn once for all the
+// concurrent callers and records its result.
+func once[T any](ctx context.Context, idem *idempotency, key string, fn func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	if idem == nil {
+		return zero, ErrNoIdempotencyStore
+	}
+	for {
+		f, leader := idem.flights.join(key)
+		if !leader {
+			res, retry, err := wait[T](ctx, f, key)
+			if retry {
+				continue
+			}
+			return res, err
+		}
+		// Looked up by the leader only, so a key completing meanwhile is
+		// never missed by a task that then calls fn again
+		return completeOnce(ctx, idem, f, key, fn)
+	}
+}
+
+// completeOnce returns the result recorded for key, or calls fn on behalf of
+// the waiters of f and records its result.
+func completeOnce[T any](ctx context.Context, idem *idempotency, f *flight, key string, fn func(ctx context.Context) (T, error)) (T, error) {
+	return shareCall(ctx, idem.flights, f, key, func(ctx context.Context) (res T, err error) {
+		v, done, err := idem.store.Load(ctx, key)
+		if err != nil {
+			return res, fmt.Errorf("async: loading idempotency key %q: %w", key, err)
+		}
+		if done {
+			res, ok := v.(T)
+			if !ok && (v != nil || reflect.TypeFor[T]().Kind() != reflect.Interface) {
+				// A silent zero would pass for the recorded result
+				return res, detailed(ErrFieldType, "idempotency key %q: recorded %T to %s", key, v, reflect.TypeFor[T]())
+			}
+			return res, nil
+		}
+
+		res, err = fn(ctx)
+		if err != nil {
+			return res, err
+		}
+		// Recorded even if ctx is done meanwhile, since the side effect happened
+		if err := idem.store.Save(context.WithoutCancel(ctx), key, res); err != nil {
+			return res, fmt.Errorf("async: recording idempotency key %q: %w", key, err)
+		}
+		return res, nil
+	})
+}
+
+// memoryIdempotencyStore implements the IdempotencyStore interface in memory.
+type memoryIdempotencyStore struct {
+	retention time.Duration
+
+	mu      sync.Mutex
+	results map[string]idempotentResult
+	// swept is the number of keys left by the last sweep
+	swept int
+}
+
+// idempotentResult is a key recorded by a memoryIdempotencyStore.
+type idempotentResult struct {
+	result any
+	saved  time.Time
+}
+
+var _ IdempotencyStore = (*memoryIdempotencyStore)(nil)
+
+// NewMemoryIdempotencyStore creates an in-memory IdempotencyStore keeping
+// keys for retention, or forever if retention is zero. It only deduplicates
+// the runs of a single process.
+func NewMemoryIdempotencyStore(retention time.Duration) IdempotencyStore {
+	return &memoryIdempotencyStore{
+		retention: retention,
+		results:   make(map[string]idempotentResult),
+	}
+}
+
+// Load returns the result recorded for key, unless it is past retention.
+func (s *memoryIdempotencyStore) Load(_ context.Context, key string) (any, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.results[key]
+	if !ok || s.expired(r, time.Now()) {
+		return nil, false, nil
+	}
+	return r.result, true, nil
+}
+
+// Save records key, dropping the expired keys whenever the store doubled
+// since the last time it did.
+func (s *memoryIdempotencyStore) Save(_ context.Context, key string, result any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.results[key] = idempotentResult{result: result, saved: now}
+	if s.retention > 0 && len(s.results) > 2*max(s.swept, 64) {
+		for k, r := range s.results {
+			if s.expired(r, now) {
+				delete(s.results, k)
+			}
+		}
+		s.swept = len(s.results)
+	}
+	return nil
+}
+
+// expired reports whether r is past retention at now.
+func (s *memoryIdempotencyStore) expired(r idempotentResult, now time.Time) bool {
+	return s.retention > 0 && now.Sub(r.saved) >= s.retention
+}
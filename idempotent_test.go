@@ -0,0 +1,143 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTaskIdempotent(t *testing.T) {
+	errDeclined := errors.New("declined")
+	runner := NewAsyncRunner(WithIdempotencyStore(NewMemoryIdempotencyStore(0)))
+	var charges atomic.Int32
+	fail := true
+	charge := func(ctx context.Context) (string, error) {
+		charges.Add(1)
+		return "receipt-1", nil
+	}
+
+	// The first run fails after the charge, so the batch is retried
+	for attempt := range 3 {
+		var receipt string
+		a := runner.RunInAsync()
+		TaskIdempotent(a, "order-1", &receipt, charge)
+		a.Phase().Task(func(ctx context.Context) error {
+			if fail {
+				fail = false
+				return errDeclined
+			}
+			return nil
+		})
+		err := a.Go(context.Background())
+		if attempt == 0 {
+			if !errors.Is(err, errDeclined) {
+				t.Fatalf("Expected the first run to fail, got %v", err)
+			}
+			continue
+		}
+		if err != nil || receipt != "receipt-1" {
+			t.Errorf("Expected the recorded receipt, got %q, %v", receipt, err)
+		}
+	}
+	if n := charges.Load(); n != 1 {
+		t.Errorf("Expected a single charge, got %d", n)
+	}
+
+	// Failures are not recorded
+	for range 2 {
+		var v int
+		_ = TaskIdempotent(runner.RunInAsync(), "email-1", &v, func(ctx context.Context) (int, error) {
+			charges.Add(1)
+			return 0, errDeclined
+		}).Go(context.Background())
+	}
+	if n := charges.Load(); n != 3 {
+		t.Errorf("Expected failures to be retried, got %d calls", n)
+	}
+}
+
+func TestTaskIdempotentConcurrent(t *testing.T) {
+	runner := NewAsyncRunner(WithIdempotencyStore(NewMemoryIdempotencyStore(time.Hour)))
+	var sends atomic.Int32
+	release := make(chan struct{})
+	send := func(ctx context.Context) (int32, error) {
+		<-release
+		return sends.Add(1), nil
+	}
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Go(func() {
+			var id int32
+			if err := TaskIdempotent(runner.RunInAsync(), "welcome-email", &id, send).Go(context.Background()); err != nil || id != 1 {
+				t.Errorf("Expected the shared result, got %d, %v", id, err)
+			}
+		})
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := sends.Load(); n != 1 {
+		t.Errorf("Expected a single send, got %d", n)
+	}
+}
+
+type failingStore struct{ err error }
+
+func (s failingStore) Load(context.Context, string) (any, bool, error) { return nil, false, s.err }
+func (s failingStore) Save(context.Context, string, any) error         { return s.err }
+
+func TestTaskIdempotentStoreErrors(t *testing.T) {
+	errStore := errors.New("store unavailable")
+	called := false
+	var v int
+	err := TaskIdempotent(NewAsyncRunner(WithIdempotencyStore(failingStore{errStore})).RunInAsync(), "key", &v, func(ctx context.Context) (int, error) {
+		called = true
+		return 1, nil
+	}).Go(context.Background())
+	if !errors.Is(err, errStore) || called {
+		t.Errorf("Expected the task to fail without running, got %v (called %v)", err, called)
+	}
+
+	err = TaskIdempotent(NewAsyncRunner().RunInAsync(), "key", &v, func(ctx context.Context) (int, error) {
+		return 1, nil
+	}).Go(context.Background())
+	if !errors.Is(err, ErrNoIdempotencyStore) {
+		t.Errorf("Expected ErrNoIdempotencyStore, got %v", err)
+	}
+}
+
+func TestTaskIdempotentResultType(t *testing.T) {
+	store := NewMemoryIdempotencyStore(0)
+	_ = store.Save(context.Background(), "order-1", "receipt-1")
+	runner := NewAsyncRunner(WithIdempotencyStore(store))
+
+	v := -1
+	err := TaskIdempotent(runner.RunInAsync(), "order-1", &v, func(ctx context.Context) (int, error) {
+		return 1, nil
+	}).Go(context.Background())
+	if !errors.Is(err, ErrFieldType) || v != -1 {
+		t.Errorf("Expected ErrFieldType leaving the destination alone, got %d, %v", v, err)
+	}
+	if err != nil && !strings.Contains(err.Error(), `"order-1": recorded string to int`) {
+		t.Errorf("Expected the error to name the key and both types, got %v", err)
+	}
+}
+
+func TestMemoryIdempotencyStoreRetention(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryIdempotencyStore(5 * time.Millisecond)
+	_ = s.Save(ctx, "key", 1)
+	if v, ok, _ := s.Load(ctx, "key"); !ok || v != 1 {
+		t.Fatalf("Expected the recorded result, got %v, %v", v, ok)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if _, ok, _ := s.Load(ctx, "key"); ok {
+		t.Error("Expected the key to expire")
+	}
+}
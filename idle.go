@@ -0,0 +1,80 @@
+package async
+
+import (
+	"fmt"
+	"time"
+)
+
+// idleWatcher is a callback registered with OnIdle.
+type idleWatcher struct {
+	d  time.Duration
+	fn func()
+	// item is the pending call of fn, while the runner is idle
+	item *delayItem
+}
+
+// OnIdle registers fn to be called once the runner has had no running batch
+// for d, and again after every later idle period lasting d, for scaling
+// workers to zero or flushing batched telemetry when load subsides. A runner
+// that never ran a batch is idle from the moment fn is registered. Scheduled
+// and supervised tasks don't keep the runner busy, only the batches they run
+// do. fn runs on its own goroutine.
+func (a *asyncRunner) OnIdle(d time.Duration, fn func()) error {
+	if fn == nil {
+		return fmt.Errorf("%w for idle callback", ErrNilTask)
+	}
+	dr := a.drain
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	if dr.closing {
+		return ErrShuttingDown
+	}
+	w := &idleWatcher{d: d, fn: fn}
+	dr.idle = append(dr.idle, w)
+	if dr.active == 0 {
+		dr.armWatcher(w)
+	}
+	return nil
+}
+
+// armIdle starts timing the idle period of every watcher. It is called with
+// d.mu held once the last batch returned.
+func (d *drain) armIdle() {
+	if d.closing {
+		return
+	}
+	for _, w := range d.idle {
+		d.armWatcher(w)
+	}
+}
+
+// armWatcher schedules the call of w at the end of the idle period, unless a
+// batch starts in between.
+func (d *drain) armWatcher(w *idleWatcher) {
+	gen := d.idleGen
+	w.item = d.delays.schedule(d.delays.clock.Now().Add(w.d), func() {
+		d.mu.Lock()
+		// The item may have fired while a new batch was cancelling it
+		fire := d.idleGen == gen && d.active == 0 && !d.closing
+		if fire {
+			w.item = nil
+		}
+		d.mu.Unlock()
+		if fire {
+			go w.fn()
+		}
+	})
+}
+
+// disarmIdle cancels the pending idle calls. It is called with d.mu held once
+// a batch starts on an idle runner, or Shutdown begins.
+func (d *drain) disarmIdle() {
+	d.idleGen++
+	for _, w := range d.idle {
+		if w.item != nil {
+			d.delays.cancel(w.item)
+			w.item = nil
+		}
+	}
+}
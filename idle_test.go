@@ -0,0 +1,59 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunnerOnIdle(t *testing.T) {
+	runner := NewAsyncRunner()
+	idle := make(chan time.Time, 4)
+	registered := time.Now()
+	if err := runner.OnIdle(20*time.Millisecond, func() { idle <- time.Now() }); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// A runner that never ran a batch is idle from the start
+	if at := <-idle; at.Sub(registered) < 20*time.Millisecond {
+		t.Errorf("Expected the callback after the idle period, got it after %v", at.Sub(registered))
+	}
+
+	release := make(chan struct{})
+	result := make(chan error, 1)
+	go func() {
+		result <- runner.RunInAsync().Task(func(ctx context.Context) error {
+			<-release
+			return nil
+		}).Go(context.Background())
+	}()
+	time.Sleep(40 * time.Millisecond)
+	select {
+	case <-idle:
+		t.Fatal("Expected no callback while a batch is running")
+	default:
+	}
+
+	close(release)
+	if err := <-result; err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	finished := time.Now()
+	if at := <-idle; at.Sub(finished) < 20*time.Millisecond {
+		t.Errorf("Expected the callback an idle period after the batch, got it after %v", at.Sub(finished))
+	}
+
+	// Fires once per idle period
+	time.Sleep(40 * time.Millisecond)
+	select {
+	case <-idle:
+		t.Error("Expected a single callback per idle period")
+	default:
+	}
+
+	_ = runner.Shutdown(context.Background())
+	if err := runner.OnIdle(time.Millisecond, func() {}); !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("Expected ErrShuttingDown, got %v", err)
+	}
+}
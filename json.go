@@ -0,0 +1,24 @@
+package async
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// TaskJSON appends a function whose returned bytes are decoded into dest.
+func (a *async) TaskJSON(dest any, fn func(ctx context.Context) ([]byte, error)) Async {
+	if fn == nil {
+		return a.Task(nil)
+	}
+	return a.Task(func(ctx context.Context) error {
+		s := bindStateFrom(ctx)
+		if err := s.claim(dest); err != nil {
+			return err
+		}
+		data, err := fn(ctx)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(data, dest)
+	})
+}
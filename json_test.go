@@ -0,0 +1,54 @@
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestTaskJSON(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var user struct {
+		Name string `json:"name"`
+	}
+	var orders []int
+	err := runner.RunInAsync().
+		TaskJSON(&user, func(ctx context.Context) ([]byte, error) {
+			return []byte(`{"name":"alice"}`), nil
+		}).
+		TaskJSON(&orders, func(ctx context.Context) ([]byte, error) {
+			return []byte(`[1,2,3]`), nil
+		}).
+		Go(context.Background())
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if user.Name != "alice" || len(orders) != 3 {
+		t.Errorf("Expected decoded results, got %+v and %v", user, orders)
+	}
+}
+
+func TestTaskJSONErrors(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var n int
+	err := runner.RunInAsync().
+		TaskJSON(&n, func(ctx context.Context) ([]byte, error) {
+			return []byte(`"not a number"`), nil
+		}).
+		Go(context.Background())
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		t.Errorf("Expected a JSON type error, got %v", err)
+	}
+
+	err = runner.RunInAsync().
+		TaskJSON(&n, nil).
+		Go(context.Background())
+	if !errors.Is(err, ErrNilTask) {
+		t.Errorf("Expected ErrNilTask, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package async
+
+import "context"
+
+// laneYield is how many tasks of a key a drainer runs before letting other
+// waiting jobs go first.
+const laneYield = 8
+
+// SubmitKeyed queues fn for execution after every task previously submitted
+// with the same key, like per-user event processing. Each key with pending
+// tasks is served by a single drainer job on the shared queue, so tasks of a
+// key never overlap while distinct keys spread over all workers.
+func (p *workerPool) SubmitKeyed(ctx context.Context, key string, fn AsyncFunc) error {
+	j := p.newJob(ctx, fn, nil)
+
+	// Holding p.mu keeps Shutdown from closing the pool while the task joins
+	// a lane whose drainer may already be gone
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		p.rejected.Add(1)
+		return ErrPoolClosed
+	}
+	p.lanesMu.Lock()
+	if p.lanes == nil {
+		p.lanes = make(map[string][]job)
+	}
+	if pending, ok := p.lanes[key]; ok {
+		// A drainer is scheduled for the key and will pick the task up
+		p.lanes[key] = append(pending, j)
+		p.keyed.Add(1)
+		p.lanesMu.Unlock()
+		p.mu.RUnlock()
+		return nil
+	}
+	p.lanes[key] = nil
+	p.lanesMu.Unlock()
+	p.mu.RUnlock()
+
+	err := p.submit(ctx, p.laneJob(key, j))
+	if err != nil {
+		// Tasks queued behind this one meanwhile share its fate
+		p.rejected.Add(uint64(p.abandonLane(key, err)))
+	}
+	return err
+}
+
+// laneJob returns the drainer job of key, starting with j.
+func (p *workerPool) laneJob(key string, j job) job {
+	return job{
+		run: func(local any) {
+			p.drainLane(key, j, local)
+		},
+		abandon: func(err error) {
+			j.abandon(err)
+			p.abandoned.Add(int64(p.abandonLane(key, err)))
+		},
+	}
+}
+
+// drainLane runs j and then every task queued for key, until the lane is empty.
+// Every laneYield tasks the drainer moves to the back of the queue if other
+// jobs are waiting, so a hot key cannot hold a worker while unkeyed tasks and
+// colder keys starve behind it.
+func (p *workerPool) drainLane(key string, j job, local any) {
+	for n := 1; ; n++ {
+		j.run(local)
+
+		p.lanesMu.Lock()
+		pending := p.lanes[key]
+		if len(pending) == 0 {
+			delete(p.lanes, key)
+			p.lanesMu.Unlock()
+			return
+		}
+		j = pending[0]
+		p.lanes[key] = pending[1:]
+		p.keyed.Add(-1)
+		p.lanesMu.Unlock()
+
+		if n%laneYield == 0 && p.requeue(p.laneJob(key, j)) {
+			return
+		}
+		p.completed.Add(1)
+	}
+}
+
+// requeue puts j at the back of the queue if other jobs are waiting and there
+// is room for it, without ever blocking the worker.
+func (p *workerPool) requeue(j job) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed || len(p.queue) == 0 {
+		return false
+	}
+	select {
+	case p.queue <- j:
+		return true
+	default:
+		return false
+	}
+}
+
+// abandonLane removes the lane of key, abandons its pending tasks with err and
+// returns how many there were.
+func (p *workerPool) abandonLane(key string, err error) int {
+	p.lanesMu.Lock()
+	pending := p.lanes[key]
+	delete(p.lanes, key)
+	p.keyed.Add(-int32(len(pending)))
+	p.lanesMu.Unlock()
+
+	for _, j := range pending {
+		j.abandon(err)
+	}
+	return len(pending)
+}
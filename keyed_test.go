@@ -0,0 +1,224 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSubmitKeyedOrder(t *testing.T) {
+	pool := NewWorkerPool(4, WithQueueSize(64))
+
+	var (
+		mu     sync.Mutex
+		events = make(map[string][]int)
+	)
+
+	for i := 0; i < 20; i++ {
+		for _, key := range []string{"alice", "bob", "carol"} {
+			err := pool.SubmitKeyed(context.Background(), key, func(ctx context.Context) error {
+				time.Sleep(time.Duration(i%3) * time.Millisecond)
+				mu.Lock()
+				events[key] = append(events[key], i)
+				mu.Unlock()
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		}
+	}
+
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for key, got := range events {
+		if len(got) != 20 {
+			t.Fatalf("Expected 20 events for %s, got %d", key, len(got))
+		}
+		for i, v := range got {
+			if v != i {
+				t.Fatalf("Expected events of %s in submission order, got %v", key, got)
+			}
+		}
+	}
+	if completed := pool.Stats().Completed; completed != 60 {
+		t.Errorf("Expected 60 completed tasks, got %d", completed)
+	}
+}
+
+func TestSubmitKeyedParallelAcrossKeys(t *testing.T) {
+	pool := NewWorkerPool(2)
+	defer pool.Shutdown(context.Background())
+
+	var current, peak atomic.Int32
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		pool.SubmitKeyed(context.Background(), fmt.Sprint(i%2), func(ctx context.Context) error {
+			defer wg.Done()
+			n := current.Add(1)
+			defer current.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		})
+	}
+	wg.Wait()
+
+	if peak.Load() != 2 {
+		t.Errorf("Expected distinct keys to run in parallel, got peak %d", peak.Load())
+	}
+}
+
+func TestSubmitKeyedAbandoned(t *testing.T) {
+	var errs atomic.Int32
+	pool := NewWorkerPool(1, WithQueueSize(4), WithPoolErrorHandler(func(err error) {
+		if errors.Is(err, ErrPoolClosed) {
+			errs.Add(1)
+		}
+	}))
+
+	running := make(chan struct{})
+	pool.Submit(context.Background(), func(ctx context.Context) error {
+		close(running)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	<-running
+
+	for i := 0; i < 3; i++ {
+		pool.SubmitKeyed(context.Background(), "key", func(ctx context.Context) error {
+			return nil
+		})
+	}
+	if queued := pool.Stats().Queued; queued != 3 {
+		t.Errorf("Expected 3 queued tasks, got %d", queued)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	var shutdownErr *ShutdownError
+	if err := pool.Shutdown(ctx); !errors.As(err, &shutdownErr) || shutdownErr.Abandoned != 3 {
+		t.Fatalf("Expected 3 abandoned tasks, got %v", err)
+	}
+	if errs.Load() != 3 {
+		t.Errorf("Expected every abandoned task to report ErrPoolClosed, got %d", errs.Load())
+	}
+}
+
+func TestSubmitKeyedAfterShutdown(t *testing.T) {
+	pool := NewWorkerPool(1)
+
+	running := make(chan struct{})
+	release := make(chan struct{})
+	pool.SubmitKeyed(context.Background(), "key", func(ctx context.Context) error {
+		close(running)
+		<-release
+		return nil
+	})
+	<-running
+
+	done := make(chan error, 1)
+	go func() {
+		done <- pool.Shutdown(context.Background())
+	}()
+	wp := pool.(*workerPool)
+	for {
+		wp.mu.RLock()
+		closed := wp.closed
+		wp.mu.RUnlock()
+		if closed {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// The lane of key is still draining, but the pool must not take the task
+	var ran atomic.Bool
+	err := pool.SubmitKeyed(context.Background(), "key", func(ctx context.Context) error {
+		ran.Store(true)
+		return nil
+	})
+	if !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Expected ErrPoolClosed, got %v", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("Expected a clean shutdown, got %v", err)
+	}
+	if ran.Load() {
+		t.Error("Expected the task submitted after Shutdown not to run")
+	}
+	if rejected := pool.Stats().Rejected; rejected != 1 {
+		t.Errorf("Expected 1 rejected task, got %d", rejected)
+	}
+}
+
+func TestSubmitKeyedYieldsToWaitingTasks(t *testing.T) {
+	pool := NewWorkerPool(1, WithQueueSize(4))
+	defer pool.Shutdown(context.Background())
+
+	release := make(chan struct{})
+	running := make(chan struct{})
+	pool.Submit(context.Background(), func(ctx context.Context) error {
+		close(running)
+		<-release
+		return nil
+	})
+	<-running
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	record := func(name string) AsyncFunc {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	for i := 0; i < 3*laneYield; i++ {
+		pool.SubmitKeyed(context.Background(), "hot", record("hot"))
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- pool.SubmitWait(context.Background(), record("cold"))
+	}()
+	for pool.Stats().Queued < 3*laneYield+1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for i, name := range order {
+		if name == "cold" {
+			if i != laneYield {
+				t.Errorf("Expected the unkeyed task to run after %d hot tasks, ran after %d", laneYield, i)
+			}
+			return
+		}
+	}
+	t.Fatal("Expected the unkeyed task to run")
+}
@@ -0,0 +1,63 @@
+package async
+
+import (
+	"context"
+	"sync"
+)
+
+// Latch lets tasks wait until others have counted it down to zero, for
+// gather-then-proceed coordination inside a batch. Unlike sync.WaitGroup,
+// waiting honours the task's context.
+//
+// Tasks awaiting a latch hold their slot while they wait, so a batch limited
+// with WithConcurrency, or run by a synchronous runner, must leave room for
+// the tasks counting it down.
+type Latch struct {
+	mu    sync.Mutex
+	count int
+	done  chan struct{}
+}
+
+// NewLatch returns a latch released after n calls to CountDown. A latch
+// created with n <= 0 is already released.
+func NewLatch(n int) *Latch {
+	l := &Latch{count: n, done: make(chan struct{})}
+	if n <= 0 {
+		l.count = 0
+		close(l.done)
+	}
+	return l
+}
+
+// CountDown decrements the count, releasing the waiting tasks when it reaches
+// zero. Calls past zero have no effect.
+func (l *Latch) CountDown() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.count == 0 {
+		return
+	}
+	l.count--
+	if l.count == 0 {
+		close(l.done)
+	}
+}
+
+// Await blocks until the latch is released, returning nil, or until ctx is
+// done, returning its error.
+func (l *Latch) Await(ctx context.Context) error {
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Count returns the number of CountDown calls still needed to release the latch.
+func (l *Latch) Count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.count
+}
@@ -0,0 +1,57 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLatchInBatch(t *testing.T) {
+	var loaded atomic.Int32
+	latch := NewLatch(2)
+	load := func(ctx context.Context) error {
+		loaded.Add(1)
+		latch.CountDown()
+		return nil
+	}
+
+	var seen int32
+	err := NewAsyncRunner().RunInAsync().
+		Task(func(ctx context.Context) error {
+			if err := latch.Await(ctx); err != nil {
+				return err
+			}
+			seen = loaded.Load()
+			return nil
+		}).
+		Task(load).
+		Task(load).
+		Go(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if seen != 2 {
+		t.Errorf("Expected the waiting task to see both loads, saw %d", seen)
+	}
+
+	// Extra calls are ignored
+	latch.CountDown()
+	if n := latch.Count(); n != 0 {
+		t.Errorf("Expected a count of 0, got %d", n)
+	}
+}
+
+func TestLatchAwaitCanceled(t *testing.T) {
+	latch := NewLatch(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if err := latch.Await(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the context error, got %v", err)
+	}
+
+	if err := NewLatch(0).Await(context.Background()); err != nil {
+		t.Errorf("Expected a zero latch to be released, got %v", err)
+	}
+}
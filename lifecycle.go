@@ -0,0 +1,267 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrShuttingDown is returned when work is submitted after shutdown has begun.
+var ErrShuttingDown = errors.New("async: shutting down")
+
+// Lifecycle tracks the long-running work of a service, such as background
+// jobs, worker pools and scheduled tasks, and stops all of it in dependency
+// order on Shutdown.
+type Lifecycle interface {
+	// Go runs fn in the background under name until Shutdown cancels its
+	// context, which happens once every component depending on it has stopped.
+	Go(name string, fn AsyncFunc, dependsOn ...string) error
+	// Register adds a component stopped by calling stop on Shutdown, once
+	// every component depending on it has stopped. WorkerPool.Shutdown can be
+	// registered directly.
+	Register(name string, stop func(ctx context.Context) error, dependsOn ...string) error
+	// Shutdown stops accepting components and stops the registered ones,
+	// dependents first, waiting until they are all stopped or ctx is done.
+	// It returns a *LifecycleError if a component failed or did not stop in
+	// time. Later calls wait for the first one and return its result.
+	Shutdown(ctx context.Context) error
+}
+
+// LifecycleError is returned by Lifecycle.Shutdown when components did not
+// stop cleanly.
+type LifecycleError struct {
+	// Unfinished names the components still stopping when the context was
+	// done, in registration order.
+	Unfinished []string
+	// Failed holds the error of every component that failed, by name,
+	// including background jobs that failed before Shutdown.
+	Failed map[string]error
+	// Err is the error of the context, if it was done before every component stopped.
+	Err error
+}
+
+func (e *LifecycleError) Error() string {
+	var b strings.Builder
+	b.WriteString("async: shutdown incomplete")
+	if len(e.Unfinished) > 0 {
+		fmt.Fprintf(&b, "; unfinished %s: %v", strings.Join(e.Unfinished, ", "), e.Err)
+	}
+	for _, name := range slices.Sorted(maps.Keys(e.Failed)) {
+		fmt.Fprintf(&b, "; %s: %v", name, e.Failed[name])
+	}
+	return b.String()
+}
+
+// Unwrap returns the context error and every failure, so errors.Is and
+// errors.As match any of them.
+func (e *LifecycleError) Unwrap() []error {
+	var errs []error
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	for _, name := range slices.Sorted(maps.Keys(e.Failed)) {
+		errs = append(errs, e.Failed[name])
+	}
+	return errs
+}
+
+// lifecycle implements the Lifecycle interface.
+type lifecycle struct {
+	mu         sync.Mutex
+	components []*component
+	byName     map[string]*component
+	closing    bool
+
+	// done is closed once the first Shutdown returns, with err its result
+	done chan struct{}
+	err  error
+}
+
+// component is a single unit of work stopped by the lifecycle.
+type component struct {
+	name string
+	stop func(ctx context.Context) error
+	// dependents are the components stopped before this one
+	dependents []*component
+	// stopped is closed once stop has returned
+	stopped chan struct{}
+	// err is the failure of stop, or of a background job before Shutdown
+	err error
+}
+
+// NewLifecycle creates an empty Lifecycle.
+func NewLifecycle() Lifecycle {
+	return &lifecycle{
+		byName: make(map[string]*component),
+		done:   make(chan struct{}),
+	}
+}
+
+// Go starts fn and registers it as a component stopped by cancelling its context.
+func (l *lifecycle) Go(name string, fn AsyncFunc, dependsOn ...string) error {
+	if fn == nil {
+		return fmt.Errorf("%w for lifecycle component %q", ErrNilTask, name)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	exited := make(chan struct{})
+	var jobErr error
+
+	c, err := l.add(name, func(stopCtx context.Context) error {
+		cancel()
+		select {
+		case <-exited:
+			return jobErr
+		case <-stopCtx.Done():
+			return stopCtx.Err()
+		}
+	}, dependsOn)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	go func() {
+		defer close(exited)
+		err := safeCall(ctx, fn)
+		if err != nil && ctx.Err() == nil {
+			// Failed on its own rather than because of Shutdown
+			jobErr = err
+			l.mu.Lock()
+			c.err = err
+			l.mu.Unlock()
+		}
+	}()
+	return nil
+}
+
+// Register adds a component stopped by stop.
+func (l *lifecycle) Register(name string, stop func(ctx context.Context) error, dependsOn ...string) error {
+	if stop == nil {
+		return fmt.Errorf("%w for lifecycle component %q", ErrNilTask, name)
+	}
+	_, err := l.add(name, stop, dependsOn)
+	return err
+}
+
+// add records a component. Dependencies must be registered first, which also
+// keeps the dependencies from forming a cycle.
+func (l *lifecycle) add(name string, stop func(ctx context.Context) error, dependsOn []string) (*component, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closing {
+		return nil, ErrShuttingDown
+	}
+	if _, ok := l.byName[name]; ok {
+		return nil, fmt.Errorf("async: duplicate lifecycle component %q", name)
+	}
+	for _, dep := range dependsOn {
+		if _, ok := l.byName[dep]; !ok {
+			return nil, &MissingDependencyError{Node: name, Missing: dep}
+		}
+	}
+
+	c := &component{name: name, stop: stop, stopped: make(chan struct{})}
+	for _, dep := range dependsOn {
+		d := l.byName[dep]
+		d.dependents = append(d.dependents, c)
+	}
+	l.components = append(l.components, c)
+	l.byName[name] = c
+	return c, nil
+}
+
+// Shutdown stops every component, dependents first.
+func (l *lifecycle) Shutdown(ctx context.Context) error {
+	l.mu.Lock()
+	if l.closing {
+		l.mu.Unlock()
+		select {
+		case <-l.done:
+			return l.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	l.closing = true
+	components := l.components
+	l.mu.Unlock()
+
+	// Every component waits for its dependents, so independent components
+	// stop concurrently. Once ctx is done, the remaining ones are stopped
+	// right away with the expired context, giving them a chance to cancel
+	// their work
+	var wg sync.WaitGroup
+	for _, c := range components {
+		wg.Go(func() {
+			for _, d := range c.dependents {
+				select {
+				case <-d.stopped:
+				case <-ctx.Done():
+				}
+			}
+			err := stopComponent(ctx, c.stop)
+			l.mu.Lock()
+			if err != nil {
+				c.err = err
+			}
+			l.mu.Unlock()
+			close(c.stopped)
+		})
+	}
+
+	all := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(all)
+	}()
+	var ctxErr error
+	select {
+	case <-all:
+	case <-ctx.Done():
+		ctxErr = ctx.Err()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lcErr := &LifecycleError{Err: ctxErr}
+	for _, c := range components {
+		select {
+		case <-c.stopped:
+		default:
+			lcErr.Unfinished = append(lcErr.Unfinished, c.name)
+			continue
+		}
+		if c.err != nil {
+			if lcErr.Failed == nil {
+				lcErr.Failed = make(map[string]error)
+			}
+			lcErr.Failed[c.name] = c.err
+		}
+	}
+	if len(lcErr.Unfinished) == 0 {
+		lcErr.Err = nil
+	}
+	if len(lcErr.Unfinished) > 0 || len(lcErr.Failed) > 0 {
+		l.err = lcErr
+	}
+	close(l.done)
+	return l.err
+}
+
+// stopComponent calls stop with panic recovery. Unlike safeCall, it calls
+// stop even when ctx is done, so the component still gets to cancel its work.
+func stopComponent(ctx context.Context, stop func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return stop(ctx)
+}
@@ -0,0 +1,104 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLifecycleShutdownOrder(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		stopped []string
+	)
+	record := func(name string) {
+		mu.Lock()
+		stopped = append(stopped, name)
+		mu.Unlock()
+	}
+
+	lc := NewLifecycle()
+	pool := NewWorkerPool(2)
+	if err := lc.Register("pool", func(ctx context.Context) error {
+		record("pool")
+		return pool.Shutdown(ctx)
+	}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	running := make(chan struct{})
+	if err := lc.Go("consumer", func(ctx context.Context) error {
+		close(running)
+		<-ctx.Done()
+		record("consumer")
+		return ctx.Err()
+	}, "pool"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	<-running
+
+	if err := lc.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected a clean shutdown, got %v", err)
+	}
+	if got := fmt.Sprint(stopped); got != "[consumer pool]" {
+		t.Errorf("Expected the consumer to stop before the pool, got %v", got)
+	}
+
+	if err := lc.Register("late", func(ctx context.Context) error { return nil }); !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("Expected ErrShuttingDown, got %v", err)
+	}
+	if err := lc.Shutdown(context.Background()); err != nil {
+		t.Errorf("Expected a second Shutdown to return the first result, got %v", err)
+	}
+}
+
+func TestLifecycleShutdownIncomplete(t *testing.T) {
+	errBroken := errors.New("broken")
+	release := make(chan struct{})
+	defer close(release)
+
+	lc := NewLifecycle()
+	_ = lc.Register("stuck", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	failed := make(chan struct{})
+	_ = lc.Go("job", func(ctx context.Context) error {
+		defer close(failed)
+		return errBroken
+	})
+	<-failed
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := lc.Shutdown(ctx)
+	var lcErr *LifecycleError
+	if !errors.As(err, &lcErr) {
+		t.Fatalf("Expected a *LifecycleError, got %v", err)
+	}
+	if fmt.Sprint(lcErr.Unfinished) != "[stuck]" || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the stuck component to be unfinished, got %v", err)
+	}
+	if !errors.Is(lcErr.Failed["job"], errBroken) || !errors.Is(err, errBroken) {
+		t.Errorf("Expected the job failure, got %v", err)
+	}
+}
+
+func TestLifecycleRegisterErrors(t *testing.T) {
+	lc := NewLifecycle()
+	stop := func(ctx context.Context) error { return nil }
+
+	var missing *MissingDependencyError
+	if err := lc.Register("consumer", stop, "queue"); !errors.As(err, &missing) || missing.Missing != "queue" {
+		t.Errorf("Expected a *MissingDependencyError, got %v", err)
+	}
+	_ = lc.Register("queue", stop)
+	if err := lc.Register("queue", stop); err == nil {
+		t.Error("Expected an error for a duplicate name")
+	}
+	if err := lc.Go("job", nil); !errors.Is(err, ErrNilTask) {
+		t.Errorf("Expected ErrNilTask, got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package async
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// lru is a map holding up to capacity values, evicting the least recently
+// used one to make room, with an optional ttl per value. Callers pass the
+// current time, so expiry follows their clock.
+type lru[K comparable, V any] struct {
+	capacity int
+
+	mu    sync.Mutex
+	order *list.List
+	items map[K]*list.Element
+}
+
+// lruItem is a value of an lru, stored in its recency list.
+type lruItem[K comparable, V any] struct {
+	key   K
+	value V
+	// expires is when the value expires, or zero if it never does
+	expires time.Time
+}
+
+func newLRU[K comparable, V any](capacity int) *lru[K, V] {
+	return &lru[K, V]{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[K]*list.Element),
+	}
+}
+
+// get returns the value stored under key at now, marking it as recently used.
+func (c *lru[K, V]) get(key K, now time.Time) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	el, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+	item := el.Value.(*lruItem[K, V])
+	if !item.expires.IsZero() && !now.Before(item.expires) {
+		c.remove(el)
+		return zero, false
+	}
+	c.order.MoveToFront(el)
+	return item.value, true
+}
+
+// set stores value under key at now, evicting the least recently used value
+// if the map is full.
+func (c *lru[K, V]) set(key K, value V, ttl time.Duration, now time.Time) {
+	var expires time.Time
+	if ttl > 0 {
+		expires = now.Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		item := el.Value.(*lruItem[K, V])
+		item.value, item.expires = value, expires
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.order.PushFront(&lruItem[K, V]{key: key, value: value, expires: expires})
+	if c.capacity > 0 && c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+}
+
+// delete removes the value stored under key.
+func (c *lru[K, V]) delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+}
+
+// remove drops el from the map. It must be called with c.mu held.
+func (c *lru[K, V]) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*lruItem[K, V]).key)
+}
+
+// lruCache implements the Cache interface in memory.
+type lruCache struct {
+	entries *lru[string, CacheEntry]
+	clock   Clock
+}
+
+var _ Cache = lruCache{}
+
+// clockedCache is implemented by caches measuring expiry with a Clock, which
+// runners set to their own, so expiry and staleness follow the same time.
+type clockedCache interface {
+	withClock(c Clock) Cache
+}
+
+// NewLRUCache creates an in-memory Cache holding up to capacity entries,
+// evicting the least recently used one to make room. A capacity of zero or
+// less means no limit. Expired entries are dropped once looked up, or evicted
+// like the others. Entries expire on the clock of the runner using the cache,
+// set with WithClock.
+func NewLRUCache(capacity int) Cache {
+	return lruCache{entries: newLRU[string, CacheEntry](capacity), clock: realClock{}}
+}
+
+// withClock returns the cache measuring expiry with clock.
+func (c lruCache) withClock(clock Clock) Cache {
+	c.clock = clock
+	return c
+}
+
+// Get returns the entry stored under key, marking it as recently used.
+func (c lruCache) Get(_ context.Context, key string) (CacheEntry, bool, error) {
+	e, ok := c.entries.get(key, c.clock.Now())
+	return e, ok, nil
+}
+
+// Set stores entry under key, evicting the least recently used entry if the
+// cache is full.
+func (c lruCache) Set(_ context.Context, key string, entry CacheEntry, ttl time.Duration) error {
+	c.entries.set(key, entry, ttl, c.clock.Now())
+	return nil
+}
+
+// Delete removes the entry stored under key.
+func (c lruCache) Delete(_ context.Context, key string) error {
+	c.entries.delete(key)
+	return nil
+}
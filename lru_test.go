@@ -0,0 +1,94 @@
+package async
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLRUCacheEviction(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRUCache(2)
+	_ = c.Set(ctx, "a", CacheEntry{Value: 1}, 0)
+	_ = c.Set(ctx, "b", CacheEntry{Value: 2}, 0)
+	// Using a makes b the least recently used entry
+	if e, ok, _ := c.Get(ctx, "a"); !ok || e.Value != 1 {
+		t.Fatalf("Expected a hit for a, got %v, %v", e, ok)
+	}
+	_ = c.Set(ctx, "c", CacheEntry{Value: 3}, 0)
+
+	if _, ok, _ := c.Get(ctx, "b"); ok {
+		t.Error("Expected b to be evicted")
+	}
+	for key, want := range map[string]int{"a": 1, "c": 3} {
+		if e, ok, _ := c.Get(ctx, key); !ok || e.Value != want {
+			t.Errorf("Expected %d for %s, got %v, %v", want, key, e, ok)
+		}
+	}
+
+	_ = c.Delete(ctx, "a")
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Error("Expected a to be deleted")
+	}
+}
+
+func TestLRUCacheExpiry(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRUCache(0)
+	_ = c.Set(ctx, "short", CacheEntry{Value: 1}, 5*time.Millisecond)
+	_ = c.Set(ctx, "forever", CacheEntry{Value: 2}, 0)
+	time.Sleep(10 * time.Millisecond)
+
+	if _, ok, _ := c.Get(ctx, "short"); ok {
+		t.Error("Expected the entry to expire")
+	}
+	if _, ok, _ := c.Get(ctx, "forever"); !ok {
+		t.Error("Expected an entry without ttl to last")
+	}
+}
+
+// frozenClock is a Clock whose time only moves when advanced.
+type frozenClock struct {
+	realClock
+
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *frozenClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *frozenClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestLRUCacheRunnerClock(t *testing.T) {
+	clock := &frozenClock{now: time.Now()}
+	runner := NewAsyncRunner(WithClock(clock), WithCache(NewLRUCache(0)))
+	calls := 0
+	get := func() int {
+		var v int
+		_ = TaskCached(runner.RunInAsync(), "a", 20*time.Millisecond, &v, func(ctx context.Context) (int, error) {
+			calls++
+			return calls, nil
+		}).Go(context.Background())
+		return v
+	}
+
+	get()
+	// The entry is fresh on the runner's clock, however long it really took
+	time.Sleep(30 * time.Millisecond)
+	if v := get(); v != 1 {
+		t.Errorf("Expected a hit while the runner's clock is frozen, got call %d", v)
+	}
+	clock.advance(time.Second)
+	if v := get(); v != 2 {
+		t.Errorf("Expected the entry to expire on the runner's clock, got call %d", v)
+	}
+}
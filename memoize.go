@@ -0,0 +1,77 @@
+package async
+
+import (
+	"context"
+	"time"
+)
+
+// MemoizeOption configures Memoize.
+type MemoizeOption func(*memoizeConfig)
+
+type memoizeConfig struct {
+	size int
+	ttl  time.Duration
+}
+
+// MemoizeSize caps the number of results Memoize keeps, evicting the least
+// recently used one to make room. By default the results are not capped.
+func MemoizeSize(n int) MemoizeOption {
+	return func(c *memoizeConfig) {
+		c.size = n
+	}
+}
+
+// MemoizeTTL makes Memoize forget results once they are ttl old. By default
+// results are kept until evicted.
+func MemoizeTTL(ttl time.Duration) MemoizeOption {
+	return func(c *memoizeConfig) {
+		c.ttl = ttl
+	}
+}
+
+// Memoize returns a function calling fn at most once per key: results are
+// kept in memory, and concurrent calls with the same key share a single call
+// of fn. Errors are not kept, so the next call tries again, and a call
+// cancelled by the context of its caller is made again for the callers still
+// waiting. The returned function is safe for concurrent use, both on its own
+// and as the body of tasks, through Bind:
+//
+//	a.Task(async.Bind(&user, func(ctx context.Context) (User, error) {
+//		return loadUser(ctx, id)
+//	}))
+func Memoize[K comparable, T any](fn func(ctx context.Context, key K) (T, error), opts ...MemoizeOption) func(ctx context.Context, key K) (T, error) {
+	var cfg memoizeConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	results := newLRU[K, T](cfg.size)
+	flights := newFlightGroup[K](0, nil)
+
+	return func(ctx context.Context, key K) (T, error) {
+		for {
+			if res, ok := results.get(key, time.Now()); ok {
+				return res, nil
+			}
+			f, leader := flights.join(key)
+			if leader {
+				return shareCall(ctx, flights, f, key, func(ctx context.Context) (T, error) {
+					// The previous call may have finished between the lookup
+					// and the join
+					if res, ok := results.get(key, time.Now()); ok {
+						return res, nil
+					}
+					res, err := fn(ctx, key)
+					if err == nil {
+						results.set(key, res, cfg.ttl, time.Now())
+					}
+					return res, err
+				})
+			}
+			res, retry, err := wait[T](ctx, f, key)
+			if retry {
+				continue
+			}
+			return res, err
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoize(t *testing.T) {
+	var calls atomic.Int32
+	errNotFound := errors.New("not found")
+	load := Memoize(func(ctx context.Context, id int) (string, error) {
+		calls.Add(1)
+		if id < 0 {
+			return "", errNotFound
+		}
+		return fmt.Sprint("user-", id), nil
+	}, MemoizeSize(2))
+
+	ctx := context.Background()
+	for range 2 {
+		if v, err := load(ctx, 1); err != nil || v != "user-1" {
+			t.Errorf("Expected user-1, got %q, %v", v, err)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected the result to be kept, got %d calls", n)
+	}
+
+	// Errors are not kept
+	for range 2 {
+		if _, err := load(ctx, -1); !errors.Is(err, errNotFound) {
+			t.Errorf("Expected the failure, got %v", err)
+		}
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("Expected failures to be retried, got %d calls", n)
+	}
+
+	// Loading two more keys evicts the first one
+	_, _ = load(ctx, 2)
+	_, _ = load(ctx, 3)
+	_, _ = load(ctx, 1)
+	if n := calls.Load(); n != 6 {
+		t.Errorf("Expected the first key to be evicted, got %d calls", n)
+	}
+}
+
+func TestMemoizeConcurrent(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	load := Memoize(func(ctx context.Context, id string) (int32, error) {
+		<-release
+		return calls.Add(1), nil
+	})
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Go(func() {
+			if v, err := load(context.Background(), "a"); err != nil || v != 1 {
+				t.Errorf("Expected the shared result, got %d, %v", v, err)
+			}
+		})
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected a single call, got %d", n)
+	}
+}
+
+func TestMemoizeTTL(t *testing.T) {
+	var calls atomic.Int32
+	load := Memoize(func(ctx context.Context, id int) (int32, error) {
+		return calls.Add(1), nil
+	}, MemoizeTTL(5*time.Millisecond))
+
+	var user string
+	err := NewAsyncRunner().RunInAsync().
+		Task(Bind(&user, func(ctx context.Context) (string, error) {
+			v, err := load(ctx, 1)
+			return fmt.Sprint(v), err
+		})).
+		Go(context.Background())
+	if err != nil || user != "1" {
+		t.Fatalf("Expected the memoized result in the task, got %q, %v", user, err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if v, _ := load(context.Background(), 1); v != 2 {
+		t.Errorf("Expected the result to expire, got %d", v)
+	}
+}
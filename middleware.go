@@ -0,0 +1,111 @@
+package async
+
+import (
+	"context"
+	"maps"
+	"reflect"
+	"sync/atomic"
+)
+
+// TaskInfo describes the task a Middleware wraps.
+type TaskInfo struct {
+	// Index is the position of the task in the batch report.
+	Index int
+	// Name is the name given with TaskNamed, if any.
+	Name string
+	// Result reports whether the task stores a result, as built with Bind,
+	// BindPtr, BindFunc or Populate, so StubResult replaces its function.
+	Result bool
+}
+
+// Middleware wraps the function of every task run by a runner's batches,
+// spawned tasks included, for concerns such as tracing or test doubles. It is
+// called when the task starts, after its condition, if any, reported true.
+type Middleware func(info TaskInfo, next AsyncFunc) AsyncFunc
+
+// WithMiddleware adds middlewares to the runner. The first one added is the
+// outermost, so it sees the task start first and finish last.
+func WithMiddleware(mw ...Middleware) RunnerOption {
+	return func(c *runnerConfig) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// wrap applies mws to fn, the first one ending up outermost.
+func wrap(mws []Middleware, info TaskInfo, fn AsyncFunc) AsyncFunc {
+	for i := len(mws) - 1; i >= 0; i-- {
+		fn = mws[i](info, fn)
+	}
+	return fn
+}
+
+type stubKey struct{}
+
+// stubbed is the result a Middleware made a task store.
+type stubbed struct {
+	v any
+}
+
+// resultFuncs holds the code pointers of the functions storing a result, so
+// middlewares can tell them apart from plain tasks before running them.
+var resultFuncs atomic.Pointer[map[uintptr]struct{}]
+
+// storesResult marks fn as storing its result through the result function,
+// and returns it.
+func storesResult(fn AsyncFunc) AsyncFunc {
+	pc := reflect.ValueOf(fn).Pointer()
+	for {
+		old := resultFuncs.Load()
+		if old != nil {
+			if _, ok := (*old)[pc]; ok {
+				return fn
+			}
+		}
+		// Only a handful of functions are ever marked, so copying is cheap
+		next := map[uintptr]struct{}{pc: {}}
+		if old != nil {
+			maps.Copy(next, *old)
+		}
+		if resultFuncs.CompareAndSwap(old, &next) {
+			return fn
+		}
+	}
+}
+
+// isResultFunc reports whether fn was marked by storesResult.
+func isResultFunc(fn AsyncFunc) bool {
+	funcs := resultFuncs.Load()
+	if funcs == nil {
+		return false
+	}
+	_, ok := (*funcs)[reflect.ValueOf(fn).Pointer()]
+	return ok
+}
+
+// StubResult returns a copy of ctx making the Bind, BindPtr, BindFunc or
+// Populate task it is passed to store v instead of calling its function, so a
+// Middleware can script task results in tests. Other tasks, for which
+// TaskInfo.Result is false, ignore it and run their function, so middlewares
+// must not call them. A v that is not of the task's result type fails the
+// task with ErrFieldType.
+func StubResult(ctx context.Context, v any) context.Context {
+	return context.WithValue(ctx, stubKey{}, stubbed{v: v})
+}
+
+// result returns the stubbed result of the task owning ctx, or calls fn if
+// there is none.
+func result[T any](ctx context.Context, fn func(ctx context.Context) (T, error)) (T, error) {
+	st, ok := ctx.Value(stubKey{}).(stubbed)
+	if !ok {
+		return fn(ctx)
+	}
+	var zero T
+	if st.v == nil {
+		return zero, nil
+	}
+	res, ok := st.v.(T)
+	if !ok {
+		return zero, detailed(ErrFieldType, "stubbed %T to %s", st.v, reflect.TypeFor[T]())
+	}
+	return res, nil
+}
@@ -0,0 +1,128 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestMiddlewareOrder(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		trace []string
+	)
+	record := func(s string) {
+		mu.Lock()
+		trace = append(trace, s)
+		mu.Unlock()
+	}
+	layer := func(label string) Middleware {
+		return func(info TaskInfo, next AsyncFunc) AsyncFunc {
+			return func(ctx context.Context) error {
+				record(label + " " + info.Name)
+				err := next(ctx)
+				record(label + " done")
+				return err
+			}
+		}
+	}
+
+	runner := NewAsyncRunner(WithMiddleware(layer("outer")), WithMiddleware(layer("inner")))
+	err := runner.RunInAsync().
+		TaskNamed("orders", func(ctx context.Context) error {
+			record("orders")
+			return nil
+		}).
+		Go(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{"outer orders", "inner orders", "orders", "inner done", "outer done"}
+	if len(trace) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, trace)
+	}
+	for i := range expected {
+		if trace[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, trace)
+		}
+	}
+}
+
+func TestMiddlewareSkipsTasksNotStarted(t *testing.T) {
+	var wrapped []int
+	runner := NewAsyncRunner(WithMiddleware(func(info TaskInfo, next AsyncFunc) AsyncFunc {
+		wrapped = append(wrapped, info.Index)
+		return next
+	}))
+
+	err := runner.RunInAsync().
+		WithConcurrency(1).
+		TaskIf(func(ctx context.Context) bool { return false }, func(ctx context.Context) error {
+			return nil
+		}).
+		Task(func(ctx context.Context) error { return nil }).
+		Go(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(wrapped) != 1 || wrapped[0] != 1 {
+		t.Errorf("Expected only the second task to be wrapped, got %v", wrapped)
+	}
+}
+
+func TestStubResult(t *testing.T) {
+	stub := func(v any) Middleware {
+		return func(info TaskInfo, next AsyncFunc) AsyncFunc {
+			return func(ctx context.Context) error {
+				return next(StubResult(ctx, v))
+			}
+		}
+	}
+	called := false
+	fetch := func(ctx context.Context) (int, error) {
+		called = true
+		return 1, nil
+	}
+
+	var n int
+	var p *int
+	var sum int
+	err := NewAsyncRunner(WithMiddleware(stub(42))).RunInAsync().
+		WithConcurrency(1).
+		Task(Bind(&n, fetch)).
+		Task(BindPtr(&p, fetch)).
+		Task(BindFunc(func(v int) { sum += v }, fetch)).
+		Go(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if called {
+		t.Error("Expected the stubbed functions not to be called")
+	}
+	if n != 42 || p == nil || *p != 42 || sum != 42 {
+		t.Errorf("Expected every destination to get 42, got %d, %v and %d", n, p, sum)
+	}
+
+	var results []bool
+	record := func(info TaskInfo, next AsyncFunc) AsyncFunc {
+		results = append(results, info.Result)
+		return next
+	}
+	_ = NewAsyncRunner(WithMiddleware(record)).RunInAsync().
+		WithConcurrency(1).
+		Task(Bind(&n, fetch)).
+		Task(func(ctx context.Context) error { return nil }).
+		Go(context.Background())
+	if len(results) != 2 || !results[0] || results[1] {
+		t.Errorf("Expected only the bound task to store a result, got %v", results)
+	}
+
+	err = NewAsyncRunner(WithMiddleware(stub("42"))).RunInAsync().
+		Task(Bind(&n, fetch)).
+		Go(context.Background())
+	if !errors.Is(err, ErrFieldType) {
+		t.Errorf("Expected ErrFieldType for a stub of the wrong type, got %v", err)
+	}
+}
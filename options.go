@@ -0,0 +1,96 @@
+package async
+
+import (
+	"os"
+	"time"
+)
+
+// RunnerOption configures the defaults of an AsyncRunner, inherited by every
+// batch it creates.
+type RunnerOption func(*runnerConfig)
+
+type runnerConfig struct {
+	timeout     time.Duration
+	concurrency int
+	pools       map[string]WorkerPool
+	onPanic     func(*PanicError)
+	maxTasks    int
+	synchronous bool
+	// seeded makes synchronous batches shuffle their tasks with seed
+	seeded     bool
+	seed       uint64
+	clock      Clock
+	middleware []Middleware
+	signals    []os.Signal
+	// drainPolicy decides what happens to batches started during Shutdown
+	drainPolicy DrainPolicy
+	cache       Cache
+	// coalesce is how long keyed tasks share a finished call
+	coalesce time.Duration
+	// idempotencyStore records the completed TaskIdempotent keys
+	idempotencyStore IdempotencyStore
+	// hookTimeouts bounds the shutdown hooks of a phase, by phase
+	hookTimeouts map[int]time.Duration
+}
+
+// WithDefaultTimeout sets the timeout applied to every batch created by the runner.
+// A batch can still override it with WithTimeout.
+func WithDefaultTimeout(timeout time.Duration) RunnerOption {
+	return func(c *runnerConfig) {
+		c.timeout = timeout
+	}
+}
+
+// WithDefaultConcurrency limits how many tasks of every batch created by the
+// runner may run at the same time. A batch can still override it with WithConcurrency.
+func WithDefaultConcurrency(n int) RunnerOption {
+	return func(c *runnerConfig) {
+		c.concurrency = n
+	}
+}
+
+// WithDefaultMaxTasks caps the number of tasks, spawned ones included, of every
+// batch created by the runner. A batch can still override it with WithMaxTasks.
+func WithDefaultMaxTasks(n int) RunnerOption {
+	return func(c *runnerConfig) {
+		c.maxTasks = n
+	}
+}
+
+// WithPool registers pool under name, so batches of the runner can route tasks
+// to it with TaskOn. Giving each dependency its own pool acts as a bulkhead:
+// a slow dependency exhausts its own workers without starving the others.
+// TaskOn only supports pools created by NewWorkerPool.
+func WithPool(name string, pool WorkerPool) RunnerOption {
+	return func(c *runnerConfig) {
+		if c.pools == nil {
+			c.pools = make(map[string]WorkerPool)
+		}
+		c.pools[name] = pool
+	}
+}
+
+// WithSynchronous makes the runner's batches run their tasks one after another
+// in registration order on the goroutine calling Go, tasks routed to pools
+// included. Spawned tasks run after the tasks of their phase. Intended for
+// tests, where deterministic execution is easier to reason about and step
+// through; the first error still cancels the remaining tasks.
+func WithSynchronous() RunnerOption {
+	return func(c *runnerConfig) {
+		c.synchronous = true
+	}
+}
+
+// WithSeededOrder is like WithSynchronous, but runs the tasks of every phase
+// in a pseudo-random order drawn from seed instead of registration order. A
+// bug depending on the order tasks run in, such as one seen in CI, reproduces
+// with the seed it showed up with; trying several seeds explores orders the
+// real scheduler rarely produces. Each phase draws its order independently of
+// other batches, so runners can be shared by parallel tests.
+func WithSeededOrder(seed uint64) RunnerOption {
+	return func(c *runnerConfig) {
+		c.synchronous = true
+		c.seeded = true
+		c.seed = seed
+	}
+}
@@ -0,0 +1,31 @@
+package async
+
+import "fmt"
+
+// PanicError is returned in place of a task's result when the task panicked.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the panicking goroutine.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("async task panicked: %v", e.Value)
+}
+
+// Unwrap returns the panic value when it is an error, so errors.Is and
+// errors.As see through the panic.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// WithPanicHandler registers fn to be called with every panic recovered from a
+// task of the runner's batches, for instance to log the stack trace or report
+// it to an error tracker. The panic is still returned as a *PanicError.
+func WithPanicHandler(fn func(*PanicError)) RunnerOption {
+	return func(c *runnerConfig) {
+		c.onPanic = fn
+	}
+}
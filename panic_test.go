@@ -0,0 +1,43 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestPanicError(t *testing.T) {
+	var handled *PanicError
+
+	runner := NewAsyncRunner(WithPanicHandler(func(err *PanicError) {
+		handled = err
+	}))
+
+	err := runner.RunInAsync().
+		Task(func(ctx context.Context) error {
+			panic(io.ErrUnexpectedEOF)
+		}).
+		Go(context.Background())
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("Expected a PanicError, got %v", err)
+	}
+	if panicErr.Value != io.ErrUnexpectedEOF {
+		t.Errorf("Expected the panic value, got %v", panicErr.Value)
+	}
+	if !strings.Contains(string(panicErr.Stack), "TestPanicError") {
+		t.Errorf("Expected the stack trace of the panicking goroutine, got %s", panicErr.Stack)
+	}
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Error("Expected the error panic value to be unwrapped")
+	}
+	if err.Error() != "async task panicked: unexpected EOF" {
+		t.Errorf("Unexpected message: %v", err)
+	}
+	if handled != panicErr {
+		t.Errorf("Expected the panic handler to receive the error, got %v", handled)
+	}
+}
@@ -0,0 +1,540 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrPoolClosed is returned when submitting work to a pool that is shutting down.
+	ErrPoolClosed = errors.New("async: worker pool is closed")
+	// ErrPoolSaturated is returned by pools using RejectAbort when their queue is full.
+	ErrPoolSaturated = errors.New("async: worker pool is saturated")
+
+	// errCallerRuns tells a submitter to run the job itself under RejectCallerRuns.
+	errCallerRuns = errors.New("async: caller runs")
+)
+
+// ShutdownError is returned by Shutdown when its context is done before the
+// pool finished its queued work.
+type ShutdownError struct {
+	// Abandoned is the number of queued tasks that never started.
+	Abandoned int
+	// Err is the error of the shutdown context.
+	Err error
+}
+
+func (e *ShutdownError) Error() string {
+	return fmt.Sprintf("async: pool shutdown abandoned %d queued tasks: %v", e.Abandoned, e.Err)
+}
+
+func (e *ShutdownError) Unwrap() error {
+	return e.Err
+}
+
+// RejectPolicy decides what happens to a task submitted while the pool's queue is full.
+type RejectPolicy int
+
+const (
+	// RejectBlock waits for room in the queue until the submitter's context is done.
+	RejectBlock RejectPolicy = iota
+	// RejectAbort fails the submission immediately with ErrPoolSaturated.
+	RejectAbort
+	// RejectCallerRuns executes the task on the submitting goroutine, which
+	// naturally slows down producers that outpace the pool.
+	RejectCallerRuns
+)
+
+// WorkerPool runs tasks on a set of long-lived goroutines, amortizing the cost
+// of spawning goroutines for every batch in high-throughput services.
+type WorkerPool interface {
+	// RunInAsync initializes a new batch whose tasks execute on the pool's workers.
+	RunInAsync() Async
+	// RunInAsyncN initializes a batch on the pool with room for n tasks.
+	RunInAsyncN(n int) Async
+	// Submit queues fn for execution and returns without waiting for it.
+	Submit(ctx context.Context, fn AsyncFunc) error
+	// SubmitWait queues fn for execution and waits for its result.
+	SubmitWait(ctx context.Context, fn AsyncFunc) error
+	// SubmitKeyed queues fn behind every task previously submitted with the
+	// same key. Tasks of one key run in order; different keys run in parallel.
+	SubmitKeyed(ctx context.Context, key string, fn AsyncFunc) error
+	// Shutdown stops accepting tasks and waits for queued and running ones to finish.
+	Shutdown(ctx context.Context) error
+	// Stats returns a snapshot of the pool's backlog and utilization.
+	Stats() PoolStats
+}
+
+// PoolStats is a point-in-time snapshot of a worker pool.
+type PoolStats struct {
+	// Workers is the current number of workers.
+	Workers int
+	// Queued is the number of tasks waiting for a free worker, including keyed
+	// tasks waiting behind an earlier task of their key.
+	Queued int
+	// Running is the number of tasks being executed.
+	Running int
+	// Idle is the number of workers waiting for a task.
+	Idle int
+	// Completed is the number of tasks that have finished since the pool started.
+	Completed uint64
+	// Rejected is the number of tasks the pool refused to queue.
+	Rejected uint64
+}
+
+// PoolOption configures a worker pool.
+type PoolOption func(*poolConfig)
+
+type poolConfig struct {
+	queueSize   int
+	onError     func(error)
+	minWorkers  int
+	maxWorkers  int
+	idleTimeout time.Duration
+	reject      RejectPolicy
+
+	workerInit     func(ctx context.Context) (any, error)
+	healthCheck    func(ctx context.Context, local any) error
+	healthInterval time.Duration
+}
+
+// defaultIdleTimeout is how long a worker above the minimum waits for work
+// before exiting, unless WithIdleTimeout is given.
+const defaultIdleTimeout = time.Minute
+
+// WithQueueSize sets how many submitted tasks may wait for a free worker.
+// Submitting blocks while the queue is full. It defaults to the pool size.
+func WithQueueSize(n int) PoolOption {
+	return func(c *poolConfig) {
+		c.queueSize = n
+	}
+}
+
+// WithPoolErrorHandler registers a callback receiving the errors of tasks
+// queued with Submit, which have no caller to return them to.
+func WithPoolErrorHandler(fn func(error)) PoolOption {
+	return func(c *poolConfig) {
+		c.onError = fn
+	}
+}
+
+// WithMinWorkers sets how many workers the pool keeps while idle. It defaults
+// to the pool size and is at least one.
+func WithMinWorkers(n int) PoolOption {
+	return func(c *poolConfig) {
+		c.minWorkers = n
+	}
+}
+
+// WithMaxWorkers lets the pool add workers up to n while tasks are waiting in
+// the queue and every worker is busy. It defaults to the pool size.
+func WithMaxWorkers(n int) PoolOption {
+	return func(c *poolConfig) {
+		c.maxWorkers = n
+	}
+}
+
+// WithIdleTimeout sets how long a worker above the minimum stays without work
+// before it exits. It defaults to one minute.
+func WithIdleTimeout(d time.Duration) PoolOption {
+	return func(c *poolConfig) {
+		c.idleTimeout = d
+	}
+}
+
+// WithRejectPolicy sets how the pool handles tasks submitted while its queue
+// is full. It defaults to RejectBlock.
+func WithRejectPolicy(policy RejectPolicy) PoolOption {
+	return func(c *poolConfig) {
+		c.reject = policy
+	}
+}
+
+// job is a unit of work queued on the pool.
+type job struct {
+	// run receives the value of the worker executing the job, see WorkerValue
+	run func(local any)
+	// abandon, if set, is called instead of run when the job is dropped
+	abandon func(error)
+}
+
+// workerPool implements the WorkerPool interface.
+type workerPool struct {
+	cfg    poolConfig
+	runner *asyncRunner
+	queue  chan job
+
+	// ctx is cancelled when a shutdown deadline expires, aborting running tasks
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// mu guards closed and the queue against being closed while sending
+	mu      sync.RWMutex
+	closed  bool
+	workers sync.WaitGroup
+	// closing is closed as soon as Shutdown starts, waking up submitters
+	// blocked on a full queue so they release mu
+	closing     chan struct{}
+	closingOnce sync.Once
+
+	// size is the current number of workers, guarded by scaleMu
+	scaleMu sync.Mutex
+	size    int
+	// idle counts workers waiting for a job
+	idle atomic.Int32
+
+	running   atomic.Int32
+	completed atomic.Uint64
+	rejected  atomic.Uint64
+	abandoned atomic.Int64
+
+	// lanes holds the pending tasks of every key with a scheduled drainer
+	lanesMu sync.Mutex
+	lanes   map[string][]job
+	keyed   atomic.Int32
+}
+
+// NewWorkerPool starts a pool of size workers. With WithMinWorkers and
+// WithMaxWorkers the pool grows under load and shrinks back when idle.
+func NewWorkerPool(size int, opts ...PoolOption) WorkerPool {
+	if size < 1 {
+		size = 1
+	}
+
+	cfg := poolConfig{queueSize: size, minWorkers: size, maxWorkers: size}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	// A pool always keeps a worker, so queued jobs cannot be stranded
+	cfg.minWorkers = max(cfg.minWorkers, 1)
+	cfg.maxWorkers = max(cfg.maxWorkers, cfg.minWorkers)
+	if cfg.idleTimeout <= 0 {
+		cfg.idleTimeout = defaultIdleTimeout
+	}
+	if cfg.healthInterval <= 0 {
+		cfg.healthInterval = defaultHealthInterval
+	}
+	size = min(max(size, cfg.minWorkers), cfg.maxWorkers)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &workerPool{
+		cfg:     cfg,
+		runner:  newAsyncRunner(nil),
+		queue:   make(chan job, cfg.queueSize),
+		closing: make(chan struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+		size:    size,
+	}
+
+	p.workers.Add(size)
+	for i := 0; i < size; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// work processes queued jobs until the queue is closed, the worker retires
+// after staying idle above the minimum number of workers, or it is replaced
+// after failing a health check.
+func (p *workerPool) work() {
+	defer p.workers.Done()
+
+	local, ok := p.initWorker()
+	if !ok {
+		return
+	}
+	defer closeWorker(local)
+
+	var idle, health <-chan time.Time
+
+	// A fixed-size pool never retires workers
+	var timer *time.Timer
+	if p.cfg.maxWorkers > p.cfg.minWorkers {
+		timer = time.NewTimer(p.cfg.idleTimeout)
+		defer timer.Stop()
+		idle = timer.C
+	}
+	if p.cfg.healthCheck != nil {
+		ticker := time.NewTicker(p.cfg.healthInterval)
+		defer ticker.Stop()
+		health = ticker.C
+	}
+
+	for p.next(local, idle, health) {
+		if timer != nil {
+			timer.Reset(p.cfg.idleTimeout)
+		}
+	}
+}
+
+// next runs the next queued job. It reports false when the worker must exit,
+// because the queue is closed, it retired after idle fired or it was replaced
+// after health fired and the check failed.
+func (p *workerPool) next(local any, idle, health <-chan time.Time) bool {
+	p.idle.Add(1)
+	select {
+	case <-p.ctx.Done():
+		// A forced shutdown leaves the remaining jobs to Shutdown
+		p.idle.Add(-1)
+		return false
+	case j, ok := <-p.queue:
+		p.idle.Add(-1)
+		if !ok {
+			return false
+		}
+		p.runJob(j, local)
+		return true
+	case <-idle:
+		p.idle.Add(-1)
+		return !p.retire()
+	case <-health:
+		p.idle.Add(-1)
+		return p.checkWorker(local)
+	}
+}
+
+// runJob runs j, keeping the utilization counters up to date.
+func (p *workerPool) runJob(j job, local any) {
+	p.running.Add(1)
+	defer p.completed.Add(1)
+	defer p.running.Add(-1)
+	j.run(local)
+}
+
+// retire removes a worker unless the pool is at its minimum size.
+func (p *workerPool) retire() bool {
+	p.scaleMu.Lock()
+	defer p.scaleMu.Unlock()
+
+	if p.size <= p.cfg.minWorkers {
+		return false
+	}
+	p.size--
+	return true
+}
+
+// grow adds a worker when the pool is below its maximum size and jobs are
+// waiting with no idle worker, or the queue is full. Callers must hold p.mu so
+// the pool cannot be closed.
+func (p *workerPool) grow(full bool) {
+	if !full && (p.idle.Load() > 0 || len(p.queue) == 0) {
+		return
+	}
+
+	p.scaleMu.Lock()
+	defer p.scaleMu.Unlock()
+
+	if p.size >= p.cfg.maxWorkers {
+		return
+	}
+	p.size++
+	p.workers.Add(1)
+	go p.work()
+}
+
+// send queues j, applying the reject policy when the queue is full. It returns
+// errCallerRuns when the caller must run j itself, after releasing p.mu.
+func (p *workerPool) send(ctx context.Context, j job) error {
+	select {
+	case p.queue <- j:
+		p.grow(false)
+		return nil
+	default:
+	}
+
+	// The queue is full: add a worker, if allowed, before rejecting or waiting for room
+	p.grow(true)
+	if p.cfg.reject != RejectBlock {
+		select {
+		case p.queue <- j:
+			return nil
+		default:
+		}
+		if p.cfg.reject == RejectCallerRuns {
+			return errCallerRuns
+		}
+		p.rejected.Add(1)
+		return ErrPoolSaturated
+	}
+
+	select {
+	case p.queue <- j:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.closing:
+		p.rejected.Add(1)
+		return ErrPoolClosed
+	}
+}
+
+// submit queues j unless the pool is closed, running it on the calling
+// goroutine when the reject policy says so.
+func (p *workerPool) submit(ctx context.Context, j job) error {
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		p.rejected.Add(1)
+		return ErrPoolClosed
+	}
+	err := p.send(ctx, j)
+	p.mu.RUnlock()
+
+	if err == errCallerRuns {
+		p.runJob(j, nil)
+		return nil
+	}
+	return err
+}
+
+// RunInAsync initializes a new batch of async operations executed by the pool.
+func (p *workerPool) RunInAsync() Async {
+	return p.runner.newBatch(p)
+}
+
+// RunInAsyncN initializes a new batch executed by the pool, preallocated for n tasks.
+func (p *workerPool) RunInAsyncN(n int) Async {
+	b := p.runner.newBatch(p)
+	b.tasks = slices.Grow(b.tasks, n)
+	return b
+}
+
+// execute queues fn, blocking while the queue is full under RejectBlock
+// until ctx is done.
+func (p *workerPool) execute(ctx context.Context, fn func(), abandon func(error)) error {
+	return p.submit(ctx, job{
+		run: func(any) {
+			fn()
+		},
+		abandon: abandon,
+	})
+}
+
+// enqueue queues fn to run with a context derived from ctx that is also
+// cancelled when the pool is forcibly stopped. done, if set, receives the result.
+func (p *workerPool) enqueue(ctx context.Context, fn AsyncFunc, done chan<- error) error {
+	return p.submit(ctx, p.newJob(ctx, fn, done))
+}
+
+// newJob wraps fn into a job delivering its result to done, or to the error
+// handler when done is nil.
+func (p *workerPool) newJob(ctx context.Context, fn AsyncFunc, done chan<- error) job {
+	result := func(err error) {
+		if done != nil {
+			done <- err
+			return
+		}
+		if err != nil && p.cfg.onError != nil {
+			p.cfg.onError(err)
+		}
+	}
+
+	return job{
+		run: func(local any) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			ctx = onPool(ctx, p)
+			if local != nil {
+				ctx = context.WithValue(ctx, workerValueKey{}, local)
+			}
+			stop := context.AfterFunc(p.ctx, cancel)
+			defer stop()
+
+			result(safeCall(ctx, fn))
+		},
+		abandon: result,
+	}
+}
+
+// Submit queues fn without waiting for it to run. When the queue is full the
+// pool's reject policy applies; by default Submit blocks until ctx is done.
+func (p *workerPool) Submit(ctx context.Context, fn AsyncFunc) error {
+	return p.enqueue(ctx, fn, nil)
+}
+
+// SubmitWait queues fn and waits for it to complete or for ctx to be done.
+// Called from a task of the same pool while no worker is free, it fails with
+// ErrPoolDeadlock instead of waiting forever.
+func (p *workerPool) SubmitWait(ctx context.Context, fn AsyncFunc) error {
+	if err := p.checkSelfWait(ctx); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	if err := p.enqueue(ctx, fn, done); err != nil {
+		return err
+	}
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown stops accepting new tasks and waits for the queued and running ones.
+// If ctx is done first, running tasks are cancelled, queued tasks are dropped
+// with ErrPoolClosed and a *ShutdownError wrapping ctx's error is returned.
+func (p *workerPool) Shutdown(ctx context.Context) error {
+	p.closingOnce.Do(func() {
+		close(p.closing)
+	})
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		p.workers.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		p.drain()
+		return &ShutdownError{Abandoned: int(p.abandoned.Load()), Err: ctx.Err()}
+	}
+}
+
+// drain abandons the jobs left in the closed queue. Workers stop taking jobs
+// once the pool is cancelled, though one may still start a job it received at
+// that moment.
+func (p *workerPool) drain() {
+	for j := range p.queue {
+		p.abandoned.Add(1)
+		if j.abandon != nil {
+			j.abandon(ErrPoolClosed)
+		}
+	}
+}
+
+// Stats returns a snapshot of the pool. The counters are read independently,
+// so they may be slightly inconsistent with each other under load.
+func (p *workerPool) Stats() PoolStats {
+	p.scaleMu.Lock()
+	workers := p.size
+	p.scaleMu.Unlock()
+
+	return PoolStats{
+		Workers:   workers,
+		Queued:    len(p.queue) + int(p.keyed.Load()),
+		Running:   int(p.running.Load()),
+		Idle:      int(p.idle.Load()),
+		Completed: p.completed.Load(),
+		Rejected:  p.rejected.Load(),
+	}
+}
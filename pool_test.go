@@ -0,0 +1,495 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorkerPoolBatch(t *testing.T) {
+	pool := NewWorkerPool(2)
+	defer pool.Shutdown(context.Background())
+
+	var result1 int
+	var result2 string
+
+	err := pool.RunInAsync().
+		Task(Bind(&result1, func(ctx context.Context) (int, error) {
+			return 42, nil
+		})).
+		Task(Bind(&result2, func(ctx context.Context) (string, error) {
+			return "pooled", nil
+		})).
+		Go(context.Background())
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result1 != 42 || result2 != "pooled" {
+		t.Errorf("Expected 42 and 'pooled', got %d and %s", result1, result2)
+	}
+}
+
+func TestWorkerPoolLimitsConcurrency(t *testing.T) {
+	pool := NewWorkerPool(2)
+	defer pool.Shutdown(context.Background())
+
+	var current, peak atomic.Int32
+
+	a := pool.RunInAsync()
+	for i := 0; i < 8; i++ {
+		a.Task(func(ctx context.Context) error {
+			n := current.Add(1)
+			defer current.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		})
+	}
+
+	if err := a.Go(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if peak.Load() > 2 {
+		t.Errorf("Expected at most 2 concurrent tasks, got %d", peak.Load())
+	}
+}
+
+func TestWorkerPoolSubmit(t *testing.T) {
+	errs := make(chan error, 1)
+	pool := NewWorkerPool(1, WithPoolErrorHandler(func(err error) {
+		errs <- err
+	}))
+	defer pool.Shutdown(context.Background())
+
+	if err := pool.Submit(context.Background(), func(ctx context.Context) error {
+		return errors.New("background failure")
+	}); err != nil {
+		t.Fatalf("Expected submit to succeed, got %v", err)
+	}
+
+	select {
+	case err := <-errs:
+		if err.Error() != "background failure" {
+			t.Errorf("Expected 'background failure', got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected error handler to be called")
+	}
+}
+
+func TestWorkerPoolSubmitWait(t *testing.T) {
+	pool := NewWorkerPool(1)
+	defer pool.Shutdown(context.Background())
+
+	err := pool.SubmitWait(context.Background(), func(ctx context.Context) error {
+		return errors.New("waited failure")
+	})
+	if err == nil || err.Error() != "waited failure" {
+		t.Errorf("Expected 'waited failure', got %v", err)
+	}
+
+	err = pool.SubmitWait(context.Background(), func(ctx context.Context) error {
+		panic("boom")
+	})
+	if err == nil || err.Error() != "async task panicked: boom" {
+		t.Errorf("Expected panic error, got %v", err)
+	}
+}
+
+func TestWorkerPoolShutdown(t *testing.T) {
+	pool := NewWorkerPool(1)
+
+	var ran atomic.Int32
+	for i := 0; i < 3; i++ {
+		pool.Submit(context.Background(), func(ctx context.Context) error {
+			time.Sleep(5 * time.Millisecond)
+			ran.Add(1)
+			return nil
+		})
+	}
+
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ran.Load() != 3 {
+		t.Errorf("Expected queued tasks to finish before shutdown returned, got %d", ran.Load())
+	}
+
+	err := pool.Submit(context.Background(), func(ctx context.Context) error { return nil })
+	if !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Expected ErrPoolClosed, got %v", err)
+	}
+
+	err = pool.RunInAsync().Task(func(ctx context.Context) error { return nil }).Go(context.Background())
+	if !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Expected ErrPoolClosed from a batch, got %v", err)
+	}
+}
+
+func TestWorkerPoolShutdownDeadline(t *testing.T) {
+	pool := NewWorkerPool(1)
+
+	cancelled := make(chan struct{})
+	pool.Submit(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		close(cancelled)
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := pool.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("Expected running task to be cancelled")
+	}
+}
+
+func TestWorkerPoolAutoscaling(t *testing.T) {
+	pool := NewWorkerPool(1,
+		WithMaxWorkers(4),
+		WithQueueSize(1),
+		WithIdleTimeout(20*time.Millisecond),
+	).(*workerPool)
+	defer pool.Shutdown(context.Background())
+
+	workers := func() int {
+		return pool.Stats().Workers
+	}
+
+	release := make(chan struct{})
+	var started atomic.Int32
+	for i := 0; i < 8; i++ {
+		go pool.Submit(context.Background(), func(ctx context.Context) error {
+			started.Add(1)
+			<-release
+			return nil
+		})
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for started.Load() < 4 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := workers(); n != 4 {
+		t.Errorf("Expected the pool to grow to 4 workers, got %d", n)
+	}
+	close(release)
+
+	deadline = time.Now().Add(time.Second)
+	for workers() > 1 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := workers(); n != 1 {
+		t.Errorf("Expected the pool to shrink back to 1 worker, got %d", n)
+	}
+}
+
+func TestWorkerPoolStats(t *testing.T) {
+	pool := NewWorkerPool(1, WithQueueSize(4))
+
+	release := make(chan struct{})
+	running := make(chan struct{})
+	pool.Submit(context.Background(), func(ctx context.Context) error {
+		close(running)
+		<-release
+		return nil
+	})
+	<-running
+
+	for i := 0; i < 2; i++ {
+		pool.Submit(context.Background(), func(ctx context.Context) error {
+			return nil
+		})
+	}
+
+	stats := pool.Stats()
+	if stats.Workers != 1 || stats.Running != 1 || stats.Queued != 2 || stats.Idle != 0 {
+		t.Errorf("Unexpected stats while busy: %+v", stats)
+	}
+
+	close(release)
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := pool.Submit(context.Background(), func(ctx context.Context) error { return nil }); !errors.Is(err, ErrPoolClosed) {
+		t.Fatalf("Expected ErrPoolClosed, got %v", err)
+	}
+
+	stats = pool.Stats()
+	if stats.Completed != 3 || stats.Rejected != 1 || stats.Queued != 0 || stats.Running != 0 {
+		t.Errorf("Unexpected stats after shutdown: %+v", stats)
+	}
+}
+
+func TestWorkerPoolRejectPolicy(t *testing.T) {
+	block := func(pool WorkerPool) chan struct{} {
+		release := make(chan struct{})
+		running := make(chan struct{})
+		pool.Submit(context.Background(), func(ctx context.Context) error {
+			close(running)
+			<-release
+			return nil
+		})
+		<-running
+		// Fill the queue
+		pool.Submit(context.Background(), func(ctx context.Context) error {
+			return nil
+		})
+		return release
+	}
+
+	t.Run("abort", func(t *testing.T) {
+		pool := NewWorkerPool(1, WithQueueSize(1), WithRejectPolicy(RejectAbort))
+		release := block(pool)
+		defer pool.Shutdown(context.Background())
+		defer close(release)
+
+		err := pool.Submit(context.Background(), func(ctx context.Context) error {
+			return nil
+		})
+		if !errors.Is(err, ErrPoolSaturated) {
+			t.Errorf("Expected ErrPoolSaturated, got %v", err)
+		}
+		if rejected := pool.Stats().Rejected; rejected != 1 {
+			t.Errorf("Expected 1 rejected task, got %d", rejected)
+		}
+	})
+
+	t.Run("caller runs", func(t *testing.T) {
+		pool := NewWorkerPool(1, WithQueueSize(1), WithRejectPolicy(RejectCallerRuns))
+		release := block(pool)
+		defer pool.Shutdown(context.Background())
+		defer close(release)
+
+		ran := false
+		err := pool.SubmitWait(context.Background(), func(ctx context.Context) error {
+			ran = true
+			return errors.New("inline")
+		})
+		if !ran || err == nil || err.Error() != "inline" {
+			t.Errorf("Expected the task to run on the caller, got ran=%v err=%v", ran, err)
+		}
+	})
+
+	t.Run("block", func(t *testing.T) {
+		pool := NewWorkerPool(1, WithQueueSize(1))
+		release := block(pool)
+		defer pool.Shutdown(context.Background())
+		defer close(release)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		err := pool.Submit(ctx, func(ctx context.Context) error {
+			return nil
+		})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+	})
+}
+
+func TestWorkerPoolShutdownAbandoned(t *testing.T) {
+	pool := NewWorkerPool(1, WithQueueSize(4))
+
+	running := make(chan struct{})
+	pool.Submit(context.Background(), func(ctx context.Context) error {
+		close(running)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	<-running
+
+	var ran atomic.Int32
+	results := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			results <- pool.SubmitWait(context.Background(), func(ctx context.Context) error {
+				ran.Add(1)
+				return nil
+			})
+		}()
+	}
+	for pool.Stats().Queued < 3 {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := pool.Shutdown(ctx)
+	var shutdownErr *ShutdownError
+	if !errors.As(err, &shutdownErr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected a ShutdownError wrapping context.DeadlineExceeded, got %v", err)
+	}
+	if shutdownErr.Abandoned != 3 {
+		t.Errorf("Expected 3 abandoned tasks, got %d", shutdownErr.Abandoned)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := <-results; !errors.Is(err, ErrPoolClosed) {
+			t.Errorf("Expected abandoned tasks to fail with ErrPoolClosed, got %v", err)
+		}
+	}
+	if ran.Load() != 0 {
+		t.Errorf("Expected abandoned tasks not to run, got %d", ran.Load())
+	}
+}
+
+func TestRunnerNamedPools(t *testing.T) {
+	db := NewWorkerPool(1)
+	defer db.Shutdown(context.Background())
+	http := NewWorkerPool(2)
+	defer http.Shutdown(context.Background())
+
+	runner := NewAsyncRunner(WithPool("db", db), WithPool("http", http))
+	if runner.Pool("db") != db || runner.Pool("http") != http || runner.Pool("cache") != nil {
+		t.Fatal("Expected pools to be looked up by name")
+	}
+
+	err := runner.RunInAsync().
+		TaskOn("db", func(ctx context.Context) error { return nil }).
+		TaskOn("db", func(ctx context.Context) error { return nil }).
+		TaskOn("http", func(ctx context.Context) error { return nil }).
+		Task(func(ctx context.Context) error { return nil }).
+		Go(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if completed := db.Stats().Completed; completed != 2 {
+		t.Errorf("Expected 2 tasks on the db pool, got %d", completed)
+	}
+	if completed := http.Stats().Completed; completed != 1 {
+		t.Errorf("Expected 1 task on the http pool, got %d", completed)
+	}
+
+	err = runner.RunInAsync().
+		TaskOn("cache", func(ctx context.Context) error { return nil }).
+		Go(context.Background())
+	if !errors.Is(err, ErrUnknownPool) {
+		t.Errorf("Expected ErrUnknownPool, got %v", err)
+	}
+}
+
+// wrappedPool is a WorkerPool implementation not created by NewWorkerPool.
+type wrappedPool struct {
+	WorkerPool
+}
+
+func TestRunnerRejectsForeignPool(t *testing.T) {
+	pool := NewWorkerPool(1)
+	defer pool.Shutdown(context.Background())
+
+	var ran atomic.Bool
+	runner := NewAsyncRunner(WithPool("db", wrappedPool{pool}))
+	err := runner.RunInAsync().
+		TaskOn("db", func(ctx context.Context) error {
+			ran.Store(true)
+			return nil
+		}).
+		Go(context.Background())
+	if !errors.Is(err, ErrUnsupportedPool) {
+		t.Errorf("Expected ErrUnsupportedPool, got %v", err)
+	}
+	if ran.Load() {
+		t.Error("Expected the task not to run off the pool")
+	}
+}
+
+func TestWorkerPoolBatchTimeoutWhileQueueFull(t *testing.T) {
+	pool := NewWorkerPool(1, WithQueueSize(1))
+	release := make(chan struct{})
+	defer pool.Shutdown(context.Background())
+	defer close(release)
+
+	started := make(chan struct{})
+	block := func(ctx context.Context) error {
+		<-release
+		return nil
+	}
+	pool.Submit(context.Background(), func(ctx context.Context) error {
+		close(started)
+		return block(ctx)
+	})
+	<-started
+	pool.Submit(context.Background(), block)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- pool.RunInAsync().
+			WithTimeout(50 * time.Millisecond).
+			Task(block).
+			Task(block).
+			Go(context.Background())
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected deadline exceeded, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the batch to give up queueing once its timeout expired")
+	}
+}
+
+func TestWorkerPoolShutdownWithBlockedSubmit(t *testing.T) {
+	pool := NewWorkerPool(1, WithQueueSize(1))
+	release := make(chan struct{})
+	defer close(release)
+
+	started := make(chan struct{})
+	block := func(ctx context.Context) error {
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+		return nil
+	}
+	pool.Submit(context.Background(), func(ctx context.Context) error {
+		close(started)
+		return block(ctx)
+	})
+	<-started
+	pool.Submit(context.Background(), block)
+
+	submitted := make(chan error, 1)
+	go func() {
+		submitted <- pool.Submit(context.Background(), block)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- pool.Shutdown(ctx)
+	}()
+
+	select {
+	case err := <-shutdown:
+		var shutdownErr *ShutdownError
+		if !errors.As(err, &shutdownErr) {
+			t.Errorf("Expected a ShutdownError, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected Shutdown to honor its deadline despite a blocked Submit")
+	}
+	if err := <-submitted; !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Expected the blocked Submit to fail with ErrPoolClosed, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+var (
+	// ErrUnknownField is returned by Populate tasks whose name matches no
+	// settable field of the destination struct.
+	ErrUnknownField = errors.New("async: unknown destination field")
+	// ErrFieldType is returned by Populate tasks whose result cannot be
+	// assigned to the matching field, and by tasks given a stubbed result of
+	// the wrong type.
+	ErrFieldType = errors.New("async: result not assignable to field")
+)
+
+// ValueFunc is a function producing an untyped result, for destinations that
+// are resolved at run time such as the fields filled by Populate.
+type ValueFunc func(ctx context.Context) (any, error)
+
+// Value adapts a typed function to a ValueFunc.
+func Value[T any](fn func(ctx context.Context) (T, error)) ValueFunc {
+	if fn == nil {
+		return nil
+	}
+	return func(ctx context.Context) (any, error) {
+		return fn(ctx)
+	}
+}
+
+// Populate adds a task named after every entry of fns to a, assigning the
+// result to the field of the struct pointed to by dst with the entry's name. A
+// field is matched by its `async:"name"` tag first, then by its exact name.
+// Results go through the same checks as Bind, so two entries resolving to the
+// same field fail with ErrSharedDest, and strict batches reject nil results.
+func Populate(a Async, dst any, fns map[string]ValueFunc) Async {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return a.Task(func(ctx context.Context) error {
+			return detailed(ErrUnknownField, "destination %T is not a pointer to a struct", dst)
+		})
+	}
+
+	// Sorted, so task reports come in a stable order
+	for _, name := range slices.Sorted(maps.Keys(fns)) {
+		fn := fns[name]
+		if fn == nil {
+			a.TaskNamed(name, nil)
+			continue
+		}
+		field, ok := fieldByTag(v.Elem(), name)
+		if !ok {
+			a.TaskNamed(name, func(ctx context.Context) error {
+				return detailed(ErrUnknownField, "%q", name)
+			})
+			continue
+		}
+		a.TaskNamed(name, storesResult(func(ctx context.Context) error {
+			s := bindStateFrom(ctx)
+			if err := s.claim(field.Addr().Interface()); err != nil {
+				return err
+			}
+			res, err := result(ctx, fn)
+			if err != nil {
+				return err
+			}
+			return storeValue(s, field, res)
+		}))
+	}
+	return a
+}
+
+// fieldByTag returns the settable field of the struct v tagged or named name.
+func fieldByTag(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("async") == name && v.Field(i).CanSet() {
+			return v.Field(i), true
+		}
+	}
+	if f, ok := t.FieldByName(name); ok && len(f.Index) == 1 && v.Field(f.Index[0]).CanSet() {
+		return v.Field(f.Index[0]), true
+	}
+	return reflect.Value{}, false
+}
+
+// storeValue assigns res to field, applying the batch's result policies like store.
+func storeValue(s *bindState, field reflect.Value, res any) error {
+	rv := reflect.ValueOf(res)
+	if s != nil && s.strict && (!rv.IsValid() || isNilValue(rv)) {
+		return detailed(ErrNilResult, "%s", field.Type())
+	}
+	if s != nil && s.assign != nil {
+		return s.assign.Assign(field.Addr().Interface(), res)
+	}
+	if !rv.IsValid() {
+		// A nil interface result
+		field.SetZero()
+		return nil
+	}
+	if !rv.Type().AssignableTo(field.Type()) {
+		return detailed(ErrFieldType, "%s to %s", rv.Type(), field.Type())
+	}
+	if s != nil && s.copy {
+		rv = deepCopy(rv, make(map[uintptr]reflect.Value))
+	}
+	field.Set(rv)
+	return nil
+}
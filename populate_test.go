@@ -0,0 +1,77 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type dashboard struct {
+	User    string
+	Orders  []int `async:"orders"`
+	Balance float64
+	private int
+}
+
+func TestPopulate(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var view dashboard
+	err := Populate(runner.RunInAsync(), &view, map[string]ValueFunc{
+		"User": Value(func(ctx context.Context) (string, error) {
+			return "alice", nil
+		}),
+		"orders": Value(func(ctx context.Context) ([]int, error) {
+			return []int{1, 2}, nil
+		}),
+		"Balance": func(ctx context.Context) (any, error) {
+			return 9.5, nil
+		},
+	}).Go(context.Background())
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if view.User != "alice" || len(view.Orders) != 2 || view.Balance != 9.5 {
+		t.Errorf("Expected populated fields, got %+v", view)
+	}
+}
+
+func TestPopulateErrors(t *testing.T) {
+	runner := NewAsyncRunner()
+	value := func(v any) ValueFunc {
+		return func(ctx context.Context) (any, error) {
+			return v, nil
+		}
+	}
+
+	var view dashboard
+	tests := []struct {
+		name string
+		dst  any
+		fns  map[string]ValueFunc
+		want error
+	}{
+		{"unknown field", &view, map[string]ValueFunc{"Missing": value(1)}, ErrUnknownField},
+		{"unexported field", &view, map[string]ValueFunc{"private": value(1)}, ErrUnknownField},
+		{"not a struct pointer", view, map[string]ValueFunc{"User": value("a")}, ErrUnknownField},
+		{"wrong type", &view, map[string]ValueFunc{"User": value(1)}, ErrFieldType},
+		{"same field twice", &view, map[string]ValueFunc{"Orders": value([]int{}), "orders": value([]int{})}, ErrSharedDest},
+		{"nil function", &view, map[string]ValueFunc{"User": nil}, ErrNilTask},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Populate(runner.RunInAsync(), tt.dst, tt.fns).Go(context.Background())
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	err := Populate(runner.RunInAsync().Strict(), &view, map[string]ValueFunc{
+		"Orders": value([]int(nil)),
+	}).Go(context.Background())
+	if !errors.Is(err, ErrNilResult) {
+		t.Errorf("Expected ErrNilResult in strict mode, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package async
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNoQuorum is returned by Go when a batch run with WithQuorum cannot get
+// the required number of successful tasks.
+var ErrNoQuorum = errors.New("async: quorum not reached")
+
+// errQuorum is used internally to cancel the remaining tasks once the quorum is reached.
+var errQuorum = errors.New("async: quorum reached")
+
+// quorum counts the outcomes of a batch run with WithQuorum.
+type quorum struct {
+	need int
+
+	mu sync.Mutex
+	// total is the number of tasks of the run, spawned ones included
+	total int
+	ok    int
+	errs  []error
+}
+
+// add accounts for a spawned task.
+func (q *quorum) add() {
+	q.mu.Lock()
+	q.total++
+	q.mu.Unlock()
+}
+
+// done records the outcome of a task and returns the error its group should
+// see: errQuorum once the quorum is reached, the quorum failure once it can
+// no longer be, and nil otherwise, so failures alone do not stop the batch.
+func (q *quorum) done(err error) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.ok >= q.need {
+		// Tasks cancelled after the quorum was reached
+		return nil
+	}
+	if err == nil {
+		q.ok++
+		if q.ok == q.need {
+			return errQuorum
+		}
+		return nil
+	}
+	q.errs = append(q.errs, err)
+	if len(q.errs) > q.total-q.need {
+		return q.errLocked()
+	}
+	return nil
+}
+
+// skip accounts for a task whose condition kept it from running, returning
+// the quorum failure if the quorum can no longer be reached.
+func (q *quorum) skip() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.total--
+	if q.ok < q.need && len(q.errs) > q.total-q.need {
+		return q.errLocked()
+	}
+	return nil
+}
+
+// err returns the quorum failure, or nil if the quorum was reached.
+func (q *quorum) err() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.ok >= q.need {
+		return nil
+	}
+	return q.errLocked()
+}
+
+func (q *quorum) errLocked() error {
+	if len(q.errs) == 0 {
+		return fmt.Errorf("%w: %d of %d tasks succeeded", ErrNoQuorum, q.ok, q.need)
+	}
+	return fmt.Errorf("%w: %d of %d tasks succeeded: %w", ErrNoQuorum, q.ok, q.need, errors.Join(q.errs...))
+}
@@ -0,0 +1,95 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQuorumReached(t *testing.T) {
+	replica := func(d time.Duration, err error) AsyncFunc {
+		return func(ctx context.Context) error {
+			select {
+			case <-time.After(d):
+				return err
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	start := time.Now()
+	a := NewAsyncRunner().RunInAsync().
+		WithQuorum(2).
+		Task(replica(time.Millisecond, errors.New("replica down"))).
+		Task(replica(2*time.Millisecond, nil)).
+		Task(replica(3*time.Millisecond, nil)).
+		Task(replica(time.Hour, nil))
+	if err := a.Go(context.Background()); err != nil {
+		t.Fatalf("Expected the quorum to be reached, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the slow replica to be cancelled, took %v", elapsed)
+	}
+
+	statuses := []TaskStatus{StatusFailed, StatusSucceeded, StatusSucceeded}
+	tasks := a.Report().Tasks
+	for i, want := range statuses {
+		if tasks[i].Status != want {
+			t.Errorf("Expected task %d to be %v, got %v", i, want, tasks[i].Status)
+		}
+	}
+	// Depending on whether it started before the quorum was reached
+	if s := tasks[3].Status; s != StatusFailed && s != StatusCanceled {
+		t.Errorf("Expected the slow replica to be cancelled, got %v", s)
+	}
+}
+
+func TestQuorumUnreachable(t *testing.T) {
+	errDown := errors.New("down")
+	// The third task can only finish once the failures cancel it
+	err := NewAsyncRunner().RunInAsync().
+		WithQuorum(2).
+		Task(func(ctx context.Context) error { return errDown }).
+		Task(func(ctx context.Context) error { return errDown }).
+		Task(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}).
+		Go(context.Background())
+	if !errors.Is(err, ErrNoQuorum) || !errors.Is(err, errDown) {
+		t.Fatalf("Expected ErrNoQuorum with the failures, got %v", err)
+	}
+
+	// More votes needed than there are tasks
+	err = NewAsyncRunner().RunInAsync().
+		WithQuorum(3).
+		Task(func(ctx context.Context) error { return nil }).
+		TaskIf(func(ctx context.Context) bool { return false }, func(ctx context.Context) error { return nil }).
+		Go(context.Background())
+	if !errors.Is(err, ErrNoQuorum) {
+		t.Errorf("Expected ErrNoQuorum, got %v", err)
+	}
+}
+
+func TestQuorumSkipsLaterPhases(t *testing.T) {
+	ran := false
+	a := NewAsyncRunner().RunInAsync().
+		WithQuorum(1).
+		Task(func(ctx context.Context) error { return nil }).
+		Phase().
+		Task(func(ctx context.Context) error {
+			ran = true
+			return nil
+		})
+	if err := a.Go(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ran {
+		t.Error("Expected the batch to stop once the quorum was reached")
+	}
+	if s := a.Report().Tasks[1].Status; s != StatusCanceled {
+		t.Errorf("Expected the next phase to be canceled, got %v", s)
+	}
+}
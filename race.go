@@ -0,0 +1,250 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrNoFuncs is returned by combinators called without any function.
+var ErrNoFuncs = errors.New("async: no functions")
+
+// AllFailedError is returned by Race, Speculate and Chain when every function
+// failed. It keeps each failure, so a multi-source lookup still tells why
+// every source failed.
+type AllFailedError struct {
+	// Errs holds the error of every function, in argument order.
+	Errs []error
+}
+
+func (e *AllFailedError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "async: all %d calls failed", len(e.Errs))
+	for i, err := range e.Errs {
+		fmt.Fprintf(&b, "; [%d] %v", i, err)
+	}
+	return b.String()
+}
+
+// Unwrap returns every failure, so errors.Is and errors.As match any of them.
+func (e *AllFailedError) Unwrap() []error {
+	return e.Errs
+}
+
+// outcome is the result of a function called by launch, at index i of its
+// arguments.
+type outcome[T any] struct {
+	i   int
+	val T
+	err error
+}
+
+// launch calls each of fns in its own goroutine, turning panics into errors,
+// and sends their outcomes on the returned channel. The channel is buffered,
+// so the calls still running once the caller stopped receiving can finish.
+func launch[T any](ctx context.Context, fns ...func(ctx context.Context) (T, error)) <-chan outcome[T] {
+	results := make(chan outcome[T], len(fns))
+	for i, fn := range fns {
+		go func() {
+			var res T
+			err := safeCall(ctx, func(ctx context.Context) error {
+				var err error
+				res, err = fn(ctx)
+				return err
+			})
+			results <- outcome[T]{i: i, val: res, err: err}
+		}()
+	}
+	return results
+}
+
+// Race runs fns concurrently and returns the first successful result,
+// cancelling the context of the others, as when querying several mirrors or
+// replicas of the same data. It returns without waiting for the cancelled
+// calls. If every call fails, it returns an *AllFailedError holding all the
+// failures.
+func Race[T any](ctx context.Context, fns ...func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	if len(fns) == 0 {
+		return zero, ErrNoFuncs
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := launch(ctx, fns...)
+
+	errs := make([]error, len(fns))
+	for range fns {
+		r := <-results
+		if r.err == nil {
+			return r.val, nil
+		}
+		errs[r.i] = r.err
+	}
+	return zero, &AllFailedError{Errs: errs}
+}
+
+// First runs fns concurrently and returns the outcome of the first one to
+// finish, whether it succeeded or failed, cancelling the context of the
+// others. Unlike Race, a fast failure is returned right away, so the caller
+// decides on the fallback, as when a local cache misses quickly. It returns
+// without waiting for the cancelled calls.
+func First[T any](ctx context.Context, fns ...func(ctx context.Context) (T, error)) (T, error) {
+	if len(fns) == 0 {
+		var zero T
+		return zero, ErrNoFuncs
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := launch(ctx, fns...)
+
+	r := <-results
+	return r.val, r.err
+}
+
+// Speculate runs primary and speculative concurrently and prefers the result
+// of primary, for gradual migrations between data sources. The speculative
+// result is used instead when primary fails, or when primary is still running
+// threshold after speculative succeeded; the call left behind is cancelled.
+// When both succeed in time, reconcile combines their results, for instance
+// to log mismatches; a nil reconcile keeps the primary result. If both fail,
+// an *AllFailedError holds the primary failure first.
+func Speculate[T any](ctx context.Context, primary, speculative func(ctx context.Context) (T, error), threshold time.Duration, reconcile func(primary, speculative T) T) (T, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pc, sc := launch(ctx, primary), launch(ctx, speculative)
+
+	var p, s outcome[T]
+	select {
+	case p = <-pc:
+		if p.err == nil {
+			// The speculative result is only reconciled if it is already there
+			select {
+			case s = <-sc:
+				if s.err == nil && reconcile != nil {
+					return reconcile(p.val, s.val), nil
+				}
+			default:
+			}
+			return p.val, nil
+		}
+		s = <-sc
+	case s = <-sc:
+		if s.err == nil {
+			timer := time.NewTimer(threshold)
+			defer timer.Stop()
+			select {
+			case p = <-pc:
+				if p.err != nil {
+					return s.val, nil
+				}
+				if reconcile != nil {
+					return reconcile(p.val, s.val), nil
+				}
+				return p.val, nil
+			case <-timer.C:
+				return s.val, nil
+			}
+		}
+		p = <-pc
+	}
+
+	if p.err == nil {
+		return p.val, nil
+	}
+	if s.err == nil {
+		return s.val, nil
+	}
+	var zero T
+	return zero, &AllFailedError{Errs: []error{p.err, s.err}}
+}
+
+// Chain returns a function trying fns one after another until one succeeds,
+// for fallback chains such as primary store, then replica, then a static
+// default. Each link is a plain AsyncFunc, so it can carry its own retries or
+// timeout. A panicking link counts as a failure. When every link fails, or
+// ctx is done before the chain succeeds, Chain returns an *AllFailedError
+// holding the failures of the links tried. A nil link yields a nil AsyncFunc,
+// which the batch rejects.
+func Chain(fns ...AsyncFunc) AsyncFunc {
+	for _, fn := range fns {
+		if fn == nil {
+			return nil
+		}
+	}
+	return func(ctx context.Context) error {
+		if len(fns) == 0 {
+			return ErrNoFuncs
+		}
+		errs := make([]error, 0, len(fns))
+		for _, fn := range fns {
+			err := safeCall(ctx, fn)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, err)
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		return &AllFailedError{Errs: errs}
+	}
+}
+
+// TaskRace adds a task to a storing into dest the first successful result of
+// fns, raced with Race.
+func TaskRace[T any](a Async, dest *T, fns ...func(ctx context.Context) (T, error)) Async {
+	return a.Task(Bind(dest, func(ctx context.Context) (T, error) {
+		return Race(ctx, fns...)
+	}))
+}
+
+// ZipResult is the outcome of one function passed to ZipTimeout.
+type ZipResult[T any] struct {
+	Value T
+	Err   error
+	// Done reports whether the function finished before ZipTimeout returned.
+	Done bool
+}
+
+// ZipTimeout runs fns concurrently and waits for them for at most d, for
+// pages that must respond within their SLO with whatever data is available.
+// It returns the outcome of every function in argument order, failures
+// included, along with the indexes of those still running at the deadline,
+// whose context is cancelled. It also returns early when ctx is done.
+func ZipTimeout[T any](ctx context.Context, d time.Duration, fns ...func(ctx context.Context) (T, error)) ([]ZipResult[T], []int) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := launch(ctx, fns...)
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	out := make([]ZipResult[T], len(fns))
+wait:
+	for range fns {
+		select {
+		case r := <-results:
+			out[r.i] = ZipResult[T]{Value: r.val, Err: r.err, Done: true}
+		case <-timer.C:
+			break wait
+		case <-ctx.Done():
+			break wait
+		}
+	}
+
+	var pending []int
+	for i, r := range out {
+		if !r.Done {
+			pending = append(pending, i)
+		}
+	}
+	return out, pending
+}
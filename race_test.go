@@ -0,0 +1,270 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRaceFirstSuccessWins(t *testing.T) {
+	started := make(chan struct{})
+	canceled := make(chan struct{})
+	res, err := Race(context.Background(),
+		func(ctx context.Context) (string, error) {
+			return "", errors.New("mirror down")
+		},
+		func(ctx context.Context) (string, error) {
+			<-started
+			return "fast", nil
+		},
+		func(ctx context.Context) (string, error) {
+			close(started)
+			<-ctx.Done()
+			close(canceled)
+			return "slow", nil
+		},
+	)
+	if err != nil || res != "fast" {
+		t.Fatalf("Expected the fast result, got %q and %v", res, err)
+	}
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Error("Expected the slow call to be cancelled")
+	}
+}
+
+func TestRaceAllFail(t *testing.T) {
+	errTimeout := errors.New("timeout")
+	_, err := Race(context.Background(),
+		func(ctx context.Context) (int, error) {
+			time.Sleep(5 * time.Millisecond)
+			return 0, errTimeout
+		},
+		func(ctx context.Context) (int, error) {
+			panic("bad response")
+		},
+	)
+	var allFailed *AllFailedError
+	if !errors.As(err, &allFailed) || len(allFailed.Errs) != 2 {
+		t.Fatalf("Expected an *AllFailedError with both failures, got %v", err)
+	}
+	if allFailed.Errs[0] != errTimeout {
+		t.Errorf("Expected the failures in argument order, got %v", allFailed.Errs)
+	}
+	var panicErr *PanicError
+	if !errors.Is(err, errTimeout) || !errors.As(err, &panicErr) {
+		t.Errorf("Expected errors.Is and errors.As to see every failure, got %v", err)
+	}
+	if want := "async: all 2 calls failed; [0] timeout; [1] async task panicked: bad response"; err.Error() != want {
+		t.Errorf("Expected %q, got %q", want, err.Error())
+	}
+
+	if _, err := Race[int](context.Background()); !errors.Is(err, ErrNoFuncs) {
+		t.Errorf("Expected ErrNoFuncs, got %v", err)
+	}
+}
+
+func TestTaskRace(t *testing.T) {
+	var region string
+	a := NewAsyncRunner().RunInAsync()
+	err := TaskRace(a, &region,
+		func(ctx context.Context) (string, error) {
+			panic("replica crashed")
+		},
+		func(ctx context.Context) (string, error) {
+			return "eu-west", nil
+		},
+	).Go(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if region != "eu-west" {
+		t.Errorf("Expected the successful replica, got %q", region)
+	}
+}
+
+func TestFirstReturnsFastFailure(t *testing.T) {
+	errMiss := errors.New("cache miss")
+	started := make(chan struct{})
+	canceled := make(chan struct{})
+	_, err := First(context.Background(),
+		func(ctx context.Context) (string, error) {
+			<-started
+			return "", errMiss
+		},
+		func(ctx context.Context) (string, error) {
+			close(started)
+			<-ctx.Done()
+			close(canceled)
+			return "from database", nil
+		},
+	)
+	if !errors.Is(err, errMiss) {
+		t.Fatalf("Expected the fast failure, got %v", err)
+	}
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Error("Expected the slow call to be cancelled")
+	}
+
+	res, err := First(context.Background(),
+		func(ctx context.Context) (int, error) {
+			time.Sleep(20 * time.Millisecond)
+			return 0, errMiss
+		},
+		func(ctx context.Context) (int, error) {
+			return 7, nil
+		},
+	)
+	if err != nil || res != 7 {
+		t.Errorf("Expected the fast success, got %d and %v", res, err)
+	}
+
+	if _, err := First[int](context.Background()); !errors.Is(err, ErrNoFuncs) {
+		t.Errorf("Expected ErrNoFuncs, got %v", err)
+	}
+}
+
+func TestSpeculate(t *testing.T) {
+	value := func(d time.Duration, v string, err error) func(ctx context.Context) (string, error) {
+		return func(ctx context.Context) (string, error) {
+			select {
+			case <-time.After(d):
+				return v, err
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}
+	}
+	errDown := errors.New("down")
+	reconcile := func(p, s string) string {
+		return p + "+" + s
+	}
+
+	tests := []struct {
+		name        string
+		primary     func(ctx context.Context) (string, error)
+		speculative func(ctx context.Context) (string, error)
+		want        string
+	}{
+		{"primary first", value(0, "old", nil), value(time.Hour, "new", nil), "old"},
+		{"primary fails", value(0, "", errDown), value(time.Millisecond, "new", nil), "new"},
+		{"primary too slow", value(time.Hour, "old", nil), value(0, "new", nil), "new"},
+		{"both in time", value(5*time.Millisecond, "old", nil), value(0, "new", nil), "old+new"},
+		{"speculative fails", value(5*time.Millisecond, "old", nil), value(0, "", errDown), "old"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Speculate(context.Background(), tt.primary, tt.speculative, 100*time.Millisecond, reconcile)
+			if err != nil || got != tt.want {
+				t.Errorf("Expected %q, got %q and %v", tt.want, got, err)
+			}
+		})
+	}
+
+	_, err := Speculate(context.Background(), value(0, "", errDown), value(0, "", errors.New("also down")), time.Second, nil)
+	var allFailed *AllFailedError
+	if !errors.As(err, &allFailed) || allFailed.Errs[0] != errDown {
+		t.Errorf("Expected both failures, primary first, got %v", err)
+	}
+}
+
+func TestZipTimeout(t *testing.T) {
+	errDown := errors.New("down")
+	canceled := make(chan struct{})
+	results, pending := ZipTimeout(context.Background(), 50*time.Millisecond,
+		func(ctx context.Context) (string, error) {
+			return "profile", nil
+		},
+		func(ctx context.Context) (string, error) {
+			<-ctx.Done()
+			close(canceled)
+			return "", ctx.Err()
+		},
+		func(ctx context.Context) (string, error) {
+			return "", errDown
+		},
+	)
+	if len(pending) != 1 || pending[0] != 1 {
+		t.Fatalf("Expected the second call to be pending, got %v", pending)
+	}
+	if r := results[0]; !r.Done || r.Value != "profile" || r.Err != nil {
+		t.Errorf("Expected the first result, got %+v", r)
+	}
+	if r := results[1]; r.Done || r.Value != "" {
+		t.Errorf("Expected no result for the pending call, got %+v", r)
+	}
+	if r := results[2]; !r.Done || !errors.Is(r.Err, errDown) {
+		t.Errorf("Expected the failure of the third call, got %+v", r)
+	}
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Error("Expected the pending call to be cancelled")
+	}
+
+	results, pending = ZipTimeout(context.Background(), time.Hour,
+		func(ctx context.Context) (string, error) { return "a", nil },
+		func(ctx context.Context) (string, error) { return "b", nil },
+	)
+	if pending != nil || results[0].Value != "a" || results[1].Value != "b" {
+		t.Errorf("Expected both results without waiting, got %+v and %v", results, pending)
+	}
+}
+
+func TestChain(t *testing.T) {
+	var tried []string
+	link := func(name string, err error) AsyncFunc {
+		return func(ctx context.Context) error {
+			tried = append(tried, name)
+			return err
+		}
+	}
+	errMiss := errors.New("miss")
+
+	err := NewAsyncRunner().RunInAsync().
+		Task(Chain(
+			link("cache", errMiss),
+			func(ctx context.Context) error {
+				tried = append(tried, "replica")
+				panic("replica crashed")
+			},
+			link("primary", nil),
+			link("default", nil),
+		)).
+		Go(context.Background())
+	if err != nil {
+		t.Fatalf("Expected the chain to succeed, got %v", err)
+	}
+	if got := fmt.Sprint(tried); got != "[cache replica primary]" {
+		t.Errorf("Expected the chain to stop at the first success, tried %v", got)
+	}
+
+	err = Chain(link("cache", errMiss), link("primary", errMiss))(context.Background())
+	var allFailed *AllFailedError
+	if !errors.As(err, &allFailed) || len(allFailed.Errs) != 2 {
+		t.Errorf("Expected an *AllFailedError with both failures, got %v", err)
+	}
+
+	// A done context stops the chain
+	ctx, cancel := context.WithCancel(context.Background())
+	tried = nil
+	err = Chain(func(ctx context.Context) error {
+		cancel()
+		return ctx.Err()
+	}, link("primary", nil))(ctx)
+	if !errors.Is(err, context.Canceled) || tried != nil {
+		t.Errorf("Expected the chain to stop on cancellation, got %v after %v", err, tried)
+	}
+
+	if Chain(link("cache", nil), nil) != nil {
+		t.Error("Expected a nil link to yield a nil function")
+	}
+	if err := Chain()(context.Background()); !errors.Is(err, ErrNoFuncs) {
+		t.Errorf("Expected ErrNoFuncs, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package async
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// batchPool recycles released batches along with their task and report slices.
+var batchPool = sync.Pool{
+	New: func() any {
+		return new(async)
+	},
+}
+
+// Release clears the batch and puts it back in the pool. Releasing a batch
+// twice has no effect.
+func (a *async) Release() {
+	a.build.Lock()
+	if a.released {
+		a.build.Unlock()
+		return
+	}
+	a.build.Unlock()
+
+	// Drop references to task functions and errors, keeping the capacity
+	tasks := a.tasks[:0]
+	clear(a.tasks)
+	reports := a.reports[:0]
+	clear(a.reports)
+
+	*a = async{
+		tasks:    tasks,
+		reports:  reports,
+		released: true,
+	}
+	batchPool.Put(a)
+}
+
+// Reset clears the outcome of the last run so the batch can run again with the
+// same configuration and tasks.
+func (a *async) Reset() Async {
+	a.build.Lock()
+	a.started = false
+	if errors.Is(a.err, ErrBatchSealed) {
+		// The batch accepts tasks again
+		a.err = nil
+	}
+	a.build.Unlock()
+
+	a.mu.Lock()
+	clear(a.reports)
+	a.reports = a.reports[:0]
+	a.spawned = 0
+	a.tally = nil
+	a.settled = false
+	a.progress = time.Time{}
+	a.mu.Unlock()
+	return a
+}
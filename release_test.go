@@ -0,0 +1,80 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAsyncRelease(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var result int
+	a := runner.RunInAsync().
+		WithMaxTasks(5).
+		Task(Bind(&result, func(ctx context.Context) (int, error) {
+			return 1, nil
+		}))
+	if err := a.Go(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	a.Release()
+	a.Release()
+
+	// Whether or not the released batch is handed out again, a new batch starts clean
+	for i := 0; i < 10; i++ {
+		b := runner.RunInAsync()
+		if r := b.Report(); len(r.Tasks) != 0 || b.Err() != nil {
+			t.Fatalf("Expected a clean batch, got %+v and %v", r, b.Err())
+		}
+		err := b.Task(Bind(&result, func(ctx context.Context) (int, error) {
+			return 2, nil
+		})).Go(context.Background())
+		if err != nil || result != 2 {
+			t.Fatalf("Expected the reused batch to run, got %d and %v", result, err)
+		}
+		if len(b.Report().Tasks) != 1 {
+			t.Fatalf("Expected a single task report, got %d", len(b.Report().Tasks))
+		}
+		b.Release()
+	}
+}
+
+func TestAsyncReset(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var runs atomic.Int32
+	a := runner.RunInAsync().
+		Task(func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		}).
+		Task(func(ctx context.Context) error {
+			return Spawn(ctx, func(ctx context.Context) error {
+				runs.Add(1)
+				return nil
+			})
+		})
+
+	for i := 0; i < 3; i++ {
+		if err := a.Reset().Go(context.Background()); err != nil {
+			t.Fatalf("Expected run %d to succeed, got %v", i, err)
+		}
+		if n := len(a.Report().Tasks); n != 3 {
+			t.Fatalf("Expected 3 task reports after run %d, got %d", i, n)
+		}
+	}
+	if runs.Load() != 6 {
+		t.Errorf("Expected the tasks to run on every run, got %d runs", runs.Load())
+	}
+	if err := a.Go(context.Background()); !errors.Is(err, ErrBatchAlreadyRun) {
+		t.Errorf("Expected ErrBatchAlreadyRun without Reset, got %v", err)
+	}
+
+	// Build errors survive a reset
+	b := runner.RunInAsync().Task(nil)
+	if err := b.Reset().Go(context.Background()); !errors.Is(err, ErrNilTask) {
+		t.Errorf("Expected ErrNilTask after Reset, got %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package async
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// TaskStatus describes where a task is in its lifecycle.
+type TaskStatus int
+
+const (
+	// StatusPending means the task has not started yet.
+	StatusPending TaskStatus = iota
+	// StatusRunning means the task is currently executing.
+	StatusRunning
+	// StatusSucceeded means the task returned without error.
+	StatusSucceeded
+	// StatusFailed means the task returned an error or panicked.
+	StatusFailed
+	// StatusSkipped means the task's condition prevented it from running.
+	StatusSkipped
+	// StatusCanceled means the context was done before the task could start.
+	StatusCanceled
+)
+
+// String returns the lower-case name of the status.
+func (s TaskStatus) String() string {
+	switch s {
+	case StatusPending:
+		return "pending"
+	case StatusRunning:
+		return "running"
+	case StatusSucceeded:
+		return "succeeded"
+	case StatusFailed:
+		return "failed"
+	case StatusSkipped:
+		return "skipped"
+	case StatusCanceled:
+		return "canceled"
+	default:
+		return "unknown"
+	}
+}
+
+// TaskReport is the outcome of a single task.
+type TaskReport struct {
+	// Index is the position of the task in registration order.
+	Index int
+	// Name is the name given with TaskNamed, if any.
+	Name string
+	// Status is the task's lifecycle status.
+	Status TaskStatus
+	// Err is the error returned by the task, if any.
+	Err error
+	// Duration is how long the task ran, excluding time spent waiting to start.
+	Duration time.Duration
+	// Spawned reports whether the task was started by another task with Spawn
+	// rather than registered on the batch.
+	Spawned bool
+}
+
+// Report describes the outcome of a batch execution.
+type Report struct {
+	// Tasks holds one entry per task, in registration order.
+	Tasks []TaskReport
+	// Stacks is a dump of every goroutine's stack. It is only set in the
+	// reports handed to a watchdog.
+	Stacks []byte
+}
+
+// snapshotTask is the normalized form of a TaskReport in a snapshot.
+type snapshotTask struct {
+	Index    *int   `json:"index,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Spawned  bool   `json:"spawned,omitempty"`
+	Status   string `json:"status"`
+	Err      string `json:"error,omitempty"`
+	Duration string `json:"duration,omitempty"`
+}
+
+// Snapshot returns the report as indented JSON that stays the same from run to
+// run of a deterministic batch, for golden-file tests. Durations are rounded
+// down to a multiple of bucket, or left out if bucket is not positive.
+// Registered tasks come in registration order; spawned tasks, whose indexes
+// depend on scheduling, follow without an index, sorted by their content.
+// Stacks are left out.
+func (r Report) Snapshot(bucket time.Duration) []byte {
+	var registered, spawned []snapshotTask
+	for _, t := range r.Tasks {
+		s := snapshotTask{Name: t.Name, Spawned: t.Spawned, Status: t.Status.String()}
+		if t.Err != nil {
+			s.Err = t.Err.Error()
+		}
+		if bucket > 0 {
+			s.Duration = t.Duration.Truncate(bucket).String()
+		}
+		if t.Spawned {
+			spawned = append(spawned, s)
+			continue
+		}
+		s.Index = &t.Index
+		registered = append(registered, s)
+	}
+	slices.SortFunc(spawned, func(a, b snapshotTask) int {
+		return cmp.Or(
+			cmp.Compare(a.Name, b.Name),
+			cmp.Compare(a.Status, b.Status),
+			cmp.Compare(a.Err, b.Err),
+			cmp.Compare(a.Duration, b.Duration),
+		)
+	})
+
+	data, err := json.MarshalIndent(struct {
+		Tasks []snapshotTask `json:"tasks"`
+	}{append(registered, spawned...)}, "", "  ")
+	if err != nil {
+		// Only strings, booleans and ints are marshalled
+		panic(err)
+	}
+	return append(data, '\n')
+}
@@ -0,0 +1,85 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReportSnapshot(t *testing.T) {
+	report := Report{
+		Tasks: []TaskReport{
+			{Index: 0, Name: "user", Status: StatusSucceeded, Duration: 12*time.Millisecond + 345*time.Microsecond},
+			{Index: 1, Status: StatusFailed, Err: errors.New("boom"), Duration: 7 * time.Millisecond},
+			{Index: 3, Spawned: true, Status: StatusSucceeded, Duration: 3 * time.Millisecond},
+			{Index: 2, Spawned: true, Status: StatusCanceled},
+		},
+		Stacks: []byte("goroutine 1 [running]:"),
+	}
+
+	want := `{
+  "tasks": [
+    {
+      "index": 0,
+      "name": "user",
+      "status": "succeeded",
+      "duration": "10ms"
+    },
+    {
+      "index": 1,
+      "status": "failed",
+      "error": "boom",
+      "duration": "5ms"
+    },
+    {
+      "spawned": true,
+      "status": "canceled",
+      "duration": "0s"
+    },
+    {
+      "spawned": true,
+      "status": "succeeded",
+      "duration": "0s"
+    }
+  ]
+}
+`
+	if got := string(report.Snapshot(5 * time.Millisecond)); got != want {
+		t.Errorf("Unexpected snapshot:\n%s", got)
+	}
+
+	// Spawned tasks are ordered by content, whatever their index
+	report.Tasks[2], report.Tasks[3] = report.Tasks[3], report.Tasks[2]
+	if got := string(report.Snapshot(5 * time.Millisecond)); got != want {
+		t.Errorf("Expected the snapshot not to depend on spawn order, got:\n%s", got)
+	}
+}
+
+func TestReportSnapshotWithoutDurations(t *testing.T) {
+	a := NewAsyncRunner().RunInAsync().
+		TaskNamed("parent", func(ctx context.Context) error {
+			return Spawn(ctx, func(ctx context.Context) error { return nil })
+		})
+	if err := a.Go(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := `{
+  "tasks": [
+    {
+      "index": 0,
+      "name": "parent",
+      "status": "succeeded"
+    },
+    {
+      "spawned": true,
+      "status": "succeeded"
+    }
+  ]
+}
+`
+	if got := string(a.Report().Snapshot(0)); got != want {
+		t.Errorf("Unexpected snapshot:\n%s", got)
+	}
+}
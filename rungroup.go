@@ -0,0 +1,86 @@
+package async
+
+import (
+	"context"
+	"sync"
+)
+
+// RunGroup runs blocking loops together, such as an HTTP server, a consumer
+// and a signal listener, and stops all of them as soon as one returns.
+type RunGroup interface {
+	// Add registers an actor. run blocks until its context is cancelled or
+	// it fails; interrupt, if not nil, is called with the error that stops
+	// the group, for actors that cannot be stopped through their context,
+	// such as an http.Server.
+	Add(run AsyncFunc, interrupt func(error))
+	// Run starts every actor and waits for all of them to return once the
+	// first one returned or ctx is done. It returns the error of the first
+	// actor to return, which is nil if that actor returned nil, or the error
+	// of ctx if it was done first. A group without actors returns nil right
+	// away.
+	Run(ctx context.Context) error
+}
+
+// runGroup implements the RunGroup interface.
+type runGroup struct {
+	actors []actor
+}
+
+// actor is a loop of a RunGroup and the hook interrupting it.
+type actor struct {
+	run       AsyncFunc
+	interrupt func(error)
+}
+
+// NewRunGroup creates an empty RunGroup.
+func NewRunGroup() RunGroup {
+	return &runGroup{}
+}
+
+// Add registers an actor.
+func (g *runGroup) Add(run AsyncFunc, interrupt func(error)) {
+	g.actors = append(g.actors, actor{run: run, interrupt: interrupt})
+}
+
+// Run runs the actors until the first one returns.
+func (g *runGroup) Run(ctx context.Context) error {
+	if len(g.actors) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Buffered, so actors never block returning once the first one did
+	errs := make(chan error, len(g.actors))
+	for _, a := range g.actors {
+		go func() {
+			errs <- safeCall(ctx, a.run)
+		}()
+	}
+
+	var err error
+	remaining := len(g.actors)
+	select {
+	case err = <-errs:
+		remaining--
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	cancel()
+	var wg sync.WaitGroup
+	for _, a := range g.actors {
+		if a.interrupt != nil {
+			wg.Go(func() {
+				a.interrupt(err)
+			})
+		}
+	}
+	wg.Wait()
+
+	for range remaining {
+		<-errs
+	}
+	return err
+}
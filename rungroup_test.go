@@ -0,0 +1,54 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRunGroupStopsOnFirstExit(t *testing.T) {
+	errClosed := errors.New("listener closed")
+	var interrupted []error
+	stop := make(chan struct{})
+
+	g := NewRunGroup()
+	g.Add(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, nil)
+	g.Add(func(ctx context.Context) error {
+		// Like an http.Server, only stopped by its interrupt hook
+		<-stop
+		return nil
+	}, func(err error) {
+		interrupted = append(interrupted, err)
+		close(stop)
+	})
+	g.Add(func(ctx context.Context) error {
+		return errClosed
+	}, nil)
+
+	if err := g.Run(context.Background()); !errors.Is(err, errClosed) {
+		t.Fatalf("Expected the first actor's error, got %v", err)
+	}
+	if len(interrupted) != 1 || !errors.Is(interrupted[0], errClosed) {
+		t.Errorf("Expected the interrupt hook to receive the error, got %v", interrupted)
+	}
+}
+
+func TestRunGroupContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := NewRunGroup()
+	g.Add(func(ctx context.Context) error {
+		cancel()
+		<-ctx.Done()
+		return ctx.Err()
+	}, nil)
+	if err := g.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the group to stop with its context, got %v", err)
+	}
+
+	if err := NewRunGroup().Run(context.Background()); err != nil {
+		t.Errorf("Expected an empty group to return nil, got %v", err)
+	}
+}
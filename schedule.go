@@ -0,0 +1,447 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OverlapPolicy decides what happens when a periodic run is still in progress
+// at the time the next one is due.
+type OverlapPolicy int
+
+const (
+	// OverlapSkip drops runs that become due while a previous run is in progress.
+	OverlapSkip OverlapPolicy = iota
+	// OverlapQueue starts the next run immediately after the current one finishes.
+	OverlapQueue
+	// OverlapConcurrent starts every run on time, even if previous runs are still in progress.
+	OverlapConcurrent
+)
+
+// MissedRunPolicy decides how runs that became due while the task could not
+// run (e.g. during an overrun under OverlapQueue) are caught up.
+type MissedRunPolicy int
+
+const (
+	// MissedCoalesce collapses all missed runs into a single catch-up run.
+	MissedCoalesce MissedRunPolicy = iota
+	// MissedRunAll replays every missed run back to back.
+	MissedRunAll
+)
+
+// ScheduleOption configures a scheduled task.
+type ScheduleOption func(*scheduleConfig)
+
+type scheduleConfig struct {
+	overlap  OverlapPolicy
+	missed   MissedRunPolicy
+	onError  func(error)
+	jitter   time.Duration
+	trailing bool
+	gate     Gate
+	name     string
+}
+
+// Gate guards the runs of scheduled and supervised tasks, so that leader
+// election or a distributed lock can ensure only one instance of a service
+// runs a recurring job.
+type Gate interface {
+	// Acquire blocks until the caller may run the task, returning an error
+	// if it may not, such as when another instance holds the lock.
+	Acquire(ctx context.Context) error
+	// Release is called once the run guarded by a successful Acquire returned.
+	Release()
+}
+
+// WithOverlap sets the policy applied when a run overruns its interval.
+func WithOverlap(policy OverlapPolicy) ScheduleOption {
+	return func(c *scheduleConfig) {
+		c.overlap = policy
+	}
+}
+
+// WithMissedRuns sets how runs missed during an overrun are caught up.
+// It only has an effect together with OverlapQueue.
+func WithMissedRuns(policy MissedRunPolicy) ScheduleOption {
+	return func(c *scheduleConfig) {
+		c.missed = policy
+	}
+}
+
+// WithErrorHandler registers a callback receiving the errors returned by scheduled runs.
+// Errors caused by the schedule being stopped are not reported.
+func WithErrorHandler(fn func(error)) ScheduleOption {
+	return func(c *scheduleConfig) {
+		c.onError = fn
+	}
+}
+
+// WithGate makes every run of a scheduled or supervised task Acquire gate
+// first, and Release it once the run returned. Scheduled runs for which
+// Acquire fails are skipped; supervised tasks retry after their backoff.
+func WithGate(gate Gate) ScheduleOption {
+	return func(c *scheduleConfig) {
+		c.gate = gate
+	}
+}
+
+// WithName names a scheduled or supervised task in the runner's Health.
+func WithName(name string) ScheduleOption {
+	return func(c *scheduleConfig) {
+		c.name = name
+	}
+}
+
+// WithJitter delays every run by a random duration in [0, maxJitter), so that many
+// instances sharing a schedule don't hit a shared backend at exactly the same moment.
+func WithJitter(maxJitter time.Duration) ScheduleOption {
+	return func(c *scheduleConfig) {
+		c.jitter = maxJitter
+	}
+}
+
+// jitterDelay returns a random delay bounded by the configured jitter.
+func (c *scheduleConfig) jitterDelay() time.Duration {
+	if c.jitter <= 0 {
+		return 0
+	}
+	return rand.N(c.jitter)
+}
+
+// WithTrailing makes a Throttled trigger run once more at the end of the interval
+// when it was triggered while throttled, so the latest trigger is never lost.
+func WithTrailing() ScheduleOption {
+	return func(c *scheduleConfig) {
+		c.trailing = true
+	}
+}
+
+func newScheduleConfig(opts []ScheduleOption) *scheduleConfig {
+	cfg := &scheduleConfig{overlap: OverlapSkip}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// ScheduledTask is a handle to work scheduled in the background.
+type ScheduledTask interface {
+	// Stop cancels future runs and waits for the in-progress ones to return.
+	Stop()
+	// Pause suspends future runs without tearing down the schedule.
+	// Runs already in progress are not interrupted.
+	Pause()
+	// Resume re-enables runs suspended by Pause.
+	Resume()
+}
+
+// scheduledTask implements the ScheduledTask interface.
+type scheduledTask struct {
+	cfg *scheduleConfig
+	fn  AsyncFunc
+	// delays times the runs of runner schedules; triggers use the real time
+	delays  *delayQueue
+	cancel  context.CancelFunc
+	done    chan struct{}
+	running atomic.Bool
+	// untrack, if set, removes the task from its runner once done
+	untrack func()
+
+	mu      sync.Mutex
+	pending int
+	// resumed is nil while the task is active and closed by Resume
+	resumed chan struct{}
+	// health records the runs for the runner's Health
+	health BackgroundHealth
+}
+
+func newScheduledTask(ctx context.Context, fn AsyncFunc, opts []ScheduleOption) (*scheduledTask, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &scheduledTask{
+		cfg:    newScheduleConfig(opts),
+		fn:     fn,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}, ctx
+}
+
+// Every starts a background loop running fn at the given interval.
+func (a *asyncRunner) Every(ctx context.Context, interval time.Duration, fn AsyncFunc, opts ...ScheduleOption) ScheduledTask {
+	s, ctx := newScheduledTask(ctx, fn, opts)
+	s.delays = a.delays
+	s.untrack = a.drain.addBackground(s)
+	go s.loop(ctx, interval)
+	return s
+}
+
+// At registers fn on the runner's delay queue to run once when t is reached.
+// No goroutine is held while the task is pending.
+func (a *asyncRunner) At(ctx context.Context, t time.Time, fn AsyncFunc, opts ...ScheduleOption) ScheduledTask {
+	s, ctx := newScheduledTask(ctx, fn, opts)
+	s.delays = a.delays
+	s.untrack = a.drain.addBackground(s)
+
+	item := a.delays.schedule(t, func() {
+		go func() {
+			defer s.finish()
+			// Releases the context once the single run is over
+			defer s.cancel()
+			if err := s.waitResumed(ctx); err != nil {
+				return
+			}
+			s.run(ctx)
+		}()
+	})
+	context.AfterFunc(ctx, func() {
+		if a.delays.cancel(item) {
+			s.finish()
+		}
+	})
+
+	return s
+}
+
+// Pause suspends the schedule. Periodic runs that become due while paused are
+// skipped; a one-shot run due while paused waits for Resume.
+func (s *scheduledTask) Pause() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.resumed == nil {
+		s.resumed = make(chan struct{})
+	}
+}
+
+// Resume re-enables a paused schedule.
+func (s *scheduledTask) Resume() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.resumed != nil {
+		close(s.resumed)
+		s.resumed = nil
+	}
+}
+
+// pausedCh returns a channel that is closed on Resume, or nil if not paused.
+func (s *scheduledTask) pausedCh() chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.resumed
+}
+
+// waitResumed blocks while the schedule is paused.
+func (s *scheduledTask) waitResumed(ctx context.Context) error {
+	for {
+		ch := s.pausedCh()
+		if ch == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ch:
+		}
+	}
+}
+
+// halt cancels the schedule, returning a channel closed once it is done.
+func (s *scheduledTask) halt() <-chan struct{} {
+	s.cancel()
+	return s.done
+}
+
+// Stop cancels the schedule and waits for the background loop to exit.
+func (s *scheduledTask) Stop() {
+	s.cancel()
+	<-s.done
+}
+
+// finish marks the task as done.
+func (s *scheduledTask) finish() {
+	if s.untrack != nil {
+		s.untrack()
+	}
+	close(s.done)
+}
+
+// loop triggers a run on every tick, applying the configured overlap policy.
+func (s *scheduledTask) loop(ctx context.Context, interval time.Duration) {
+	defer s.finish()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	// A single worker serves the skip and queue policies, draining the
+	// pending count whenever it is woken up.
+	wake := make(chan struct{}, 1)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-wake:
+				for s.takePending() {
+					s.run(ctx)
+				}
+			}
+		}
+	}()
+
+	ticker := s.delays.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			if s.pausedCh() != nil {
+				continue
+			}
+			switch s.cfg.overlap {
+			case OverlapConcurrent:
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					s.run(ctx)
+				}()
+				continue
+			case OverlapSkip:
+				if s.running.Load() {
+					continue
+				}
+			}
+			s.addPending()
+			select {
+			case wake <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// addPending records a due run. Missed runs are counted individually only
+// under MissedRunAll; otherwise they collapse into a single pending run.
+func (s *scheduledTask) addPending() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cfg.missed == MissedRunAll {
+		s.pending++
+		return
+	}
+	s.pending = 1
+}
+
+// takePending consumes one pending run, reporting whether there was one.
+func (s *scheduledTask) takePending() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pending == 0 {
+		return false
+	}
+	s.pending--
+	return true
+}
+
+// run executes fn once, reporting its error to the configured handler.
+func (s *scheduledTask) run(ctx context.Context) {
+	s.running.Store(true)
+	defer s.running.Store(false)
+
+	if err := s.sleep(ctx, s.cfg.jitterDelay()); err != nil {
+		return
+	}
+
+	if gate := s.cfg.gate; gate != nil {
+		if gate.Acquire(ctx) != nil {
+			return
+		}
+		defer gate.Release()
+	}
+
+	s.mu.Lock()
+	s.health.Runs++
+	s.health.LastRun = s.now()
+	s.mu.Unlock()
+
+	err := safeCall(ctx, s.fn)
+	if err == nil {
+		return
+	}
+	if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		return
+	}
+	s.mu.Lock()
+	s.health.LastErr = err
+	s.health.LastFailure = s.now()
+	s.mu.Unlock()
+	if s.cfg.onError != nil {
+		s.cfg.onError(err)
+	}
+}
+
+// now returns the current time on the runner's clock, or the real time for triggers.
+func (s *scheduledTask) now() time.Time {
+	if s.delays == nil {
+		return time.Now()
+	}
+	return s.delays.clock.Now()
+}
+
+// snapshot returns the health of the schedule.
+func (s *scheduledTask) snapshot() BackgroundHealth {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h := s.health
+	h.Name = s.cfg.name
+	h.Kind = KindScheduled
+	h.Running = s.running.Load()
+	h.Paused = s.resumed != nil
+	return h
+}
+
+// sleep waits for d on the runner's clock, or on the real time for triggers.
+func (s *scheduledTask) sleep(ctx context.Context, d time.Duration) error {
+	if s.delays == nil {
+		return sleep(ctx, d)
+	}
+	return s.delays.sleep(ctx, d)
+}
+
+// delayed wraps fn so that it only starts once delay, plus any configured jitter, has elapsed.
+func delayed(q *delayQueue, delay time.Duration, fn AsyncFunc, opts []ScheduleOption) AsyncFunc {
+	cfg := newScheduleConfig(opts)
+	return func(ctx context.Context) error {
+		if err := q.sleep(ctx, delay+cfg.jitterDelay()); err != nil {
+			return err
+		}
+		return fn(ctx)
+	}
+}
+
+// sleep blocks for d or until ctx is done, whichever happens first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,335 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAsyncTaskDelayed(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var immediate, deferred time.Time
+	start := time.Now()
+
+	err := runner.RunInAsync().
+		Task(func(ctx context.Context) error {
+			immediate = time.Now()
+			return nil
+		}).
+		TaskDelayed(50*time.Millisecond, func(ctx context.Context) error {
+			deferred = time.Now()
+			return nil
+		}).
+		Go(context.Background())
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if immediate.Sub(start) >= 50*time.Millisecond {
+		t.Errorf("Expected immediate task to run without delay, took %v", immediate.Sub(start))
+	}
+	if deferred.Sub(start) < 50*time.Millisecond {
+		t.Errorf("Expected delayed task to wait at least 50ms, waited %v", deferred.Sub(start))
+	}
+}
+
+func TestAsyncTaskDelayedCancelled(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	called := false
+
+	err := runner.RunInAsync().
+		WithTimeout(20*time.Millisecond).
+		TaskDelayed(time.Second, func(ctx context.Context) error {
+			called = true
+			return nil
+		}).
+		Go(context.Background())
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if called {
+		t.Error("Expected delayed task not to run after cancellation")
+	}
+}
+
+func TestRunnerEvery(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var runs atomic.Int32
+
+	task := runner.Every(context.Background(), 10*time.Millisecond, func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+
+	time.Sleep(55 * time.Millisecond)
+	task.Stop()
+
+	stopped := runs.Load()
+	if stopped < 3 {
+		t.Errorf("Expected at least 3 runs, got %d", stopped)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if runs.Load() != stopped {
+		t.Errorf("Expected no runs after Stop, got %d more", runs.Load()-stopped)
+	}
+}
+
+func TestRunnerEveryHonorsContext(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var runs atomic.Int32
+
+	task := runner.Every(ctx, 10*time.Millisecond, func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+	cancel()
+	task.Stop()
+
+	if runs.Load() > 1 {
+		t.Errorf("Expected no runs after cancellation, got %d", runs.Load())
+	}
+}
+
+func TestRunnerEveryOverlapSkip(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var current, peak atomic.Int32
+
+	task := runner.Every(context.Background(), 5*time.Millisecond, func(ctx context.Context) error {
+		n := current.Add(1)
+		defer current.Add(-1)
+		if n > peak.Load() {
+			peak.Store(n)
+		}
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}, WithOverlap(OverlapSkip))
+
+	time.Sleep(60 * time.Millisecond)
+	task.Stop()
+
+	if peak.Load() != 1 {
+		t.Errorf("Expected runs never to overlap, peak concurrency was %d", peak.Load())
+	}
+}
+
+func TestRunnerEveryOverlapConcurrent(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var current, peak atomic.Int32
+	var mu sync.Mutex
+
+	task := runner.Every(context.Background(), 5*time.Millisecond, func(ctx context.Context) error {
+		n := current.Add(1)
+		defer current.Add(-1)
+		mu.Lock()
+		if n > peak.Load() {
+			peak.Store(n)
+		}
+		mu.Unlock()
+		time.Sleep(30 * time.Millisecond)
+		return nil
+	}, WithOverlap(OverlapConcurrent))
+
+	time.Sleep(60 * time.Millisecond)
+	task.Stop()
+
+	if peak.Load() < 2 {
+		t.Errorf("Expected overlapping runs, peak concurrency was %d", peak.Load())
+	}
+}
+
+func TestRunnerEveryErrorHandler(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	errs := make(chan error, 10)
+
+	task := runner.Every(context.Background(), 5*time.Millisecond, func(ctx context.Context) error {
+		return errors.New("tick failed")
+	}, WithErrorHandler(func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}))
+	defer task.Stop()
+
+	select {
+	case err := <-errs:
+		if err.Error() != "tick failed" {
+			t.Errorf("Expected 'tick failed', got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected error handler to be called")
+	}
+}
+
+func TestRunnerAt(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	ran := make(chan time.Time, 1)
+	at := time.Now().Add(30 * time.Millisecond)
+
+	task := runner.At(context.Background(), at, func(ctx context.Context) error {
+		ran <- time.Now()
+		return nil
+	})
+	defer task.Stop()
+
+	select {
+	case got := <-ran:
+		if got.Before(at) {
+			t.Errorf("Expected task to run at or after %v, ran at %v", at, got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected scheduled task to run")
+	}
+}
+
+func TestRunnerAtStop(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var runs atomic.Int32
+
+	task := runner.At(context.Background(), time.Now().Add(30*time.Millisecond), func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+	task.Stop()
+
+	time.Sleep(50 * time.Millisecond)
+	if runs.Load() != 0 {
+		t.Errorf("Expected stopped task not to run, ran %d times", runs.Load())
+	}
+}
+
+func TestAsyncTaskDelayedWithJitter(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	start := time.Now()
+	var ran time.Time
+
+	err := runner.RunInAsync().
+		TaskDelayed(10*time.Millisecond, func(ctx context.Context) error {
+			ran = time.Now()
+			return nil
+		}, WithJitter(20*time.Millisecond)).
+		Go(context.Background())
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if elapsed := ran.Sub(start); elapsed < 10*time.Millisecond {
+		t.Errorf("Expected at least the base delay, waited %v", elapsed)
+	}
+}
+
+func TestScheduleJitterBounds(t *testing.T) {
+	cfg := newScheduleConfig([]ScheduleOption{WithJitter(5 * time.Millisecond)})
+
+	for i := 0; i < 100; i++ {
+		if d := cfg.jitterDelay(); d < 0 || d >= 5*time.Millisecond {
+			t.Fatalf("Expected jitter in [0, 5ms), got %v", d)
+		}
+	}
+
+	if d := newScheduleConfig(nil).jitterDelay(); d != 0 {
+		t.Errorf("Expected no jitter by default, got %v", d)
+	}
+}
+
+func TestRunnerEveryMissedRuns(t *testing.T) {
+	count := func(policy MissedRunPolicy) int32 {
+		runner := NewAsyncRunner()
+
+		var runs atomic.Int32
+		block := make(chan struct{})
+
+		task := runner.Every(context.Background(), 5*time.Millisecond, func(ctx context.Context) error {
+			// The first run overruns several intervals
+			if runs.Add(1) == 1 {
+				<-block
+			}
+			return nil
+		}, WithOverlap(OverlapQueue), WithMissedRuns(policy))
+
+		time.Sleep(60 * time.Millisecond)
+		before := runs.Load()
+		close(block)
+		time.Sleep(3 * time.Millisecond)
+		task.Stop()
+
+		return runs.Load() - before
+	}
+
+	if caughtUp := count(MissedCoalesce); caughtUp > 2 {
+		t.Errorf("Expected missed runs to be coalesced, got %d catch-up runs", caughtUp)
+	}
+	if caughtUp := count(MissedRunAll); caughtUp < 5 {
+		t.Errorf("Expected missed runs to be replayed, got %d catch-up runs", caughtUp)
+	}
+}
+
+func TestRunnerEveryPauseResume(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var runs atomic.Int32
+
+	task := runner.Every(context.Background(), 5*time.Millisecond, func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+	defer task.Stop()
+
+	time.Sleep(20 * time.Millisecond)
+	task.Pause()
+	time.Sleep(10 * time.Millisecond)
+
+	paused := runs.Load()
+	time.Sleep(30 * time.Millisecond)
+	if runs.Load() != paused {
+		t.Errorf("Expected no runs while paused, got %d more", runs.Load()-paused)
+	}
+
+	task.Resume()
+	time.Sleep(30 * time.Millisecond)
+	if runs.Load() <= paused {
+		t.Error("Expected runs to continue after Resume")
+	}
+}
+
+func TestRunnerAtPausedUntilResume(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var runs atomic.Int32
+
+	task := runner.At(context.Background(), time.Now().Add(5*time.Millisecond), func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+	defer task.Stop()
+
+	task.Pause()
+	time.Sleep(30 * time.Millisecond)
+	if runs.Load() != 0 {
+		t.Fatalf("Expected no run while paused, got %d", runs.Load())
+	}
+
+	task.Resume()
+	time.Sleep(20 * time.Millisecond)
+	if runs.Load() != 1 {
+		t.Errorf("Expected the run to happen after Resume, got %d", runs.Load())
+	}
+}
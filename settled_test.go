@@ -0,0 +1,72 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGoSettled(t *testing.T) {
+	errRefund := errors.New("refund failed")
+	failed := make(chan struct{})
+	a := NewAsyncRunner().RunInAsync().
+		TaskNamed("refund", func(ctx context.Context) error {
+			defer close(failed)
+			return errRefund
+		}).
+		TaskNamed("notify", func(ctx context.Context) error {
+			<-failed
+			// Leaves the failure time to cancel the batch, as Go would
+			time.Sleep(5 * time.Millisecond)
+			return ctx.Err()
+		}).
+		TaskNamed("audit", func(ctx context.Context) error {
+			panic("audit log unavailable")
+		}).
+		Phase().
+		TaskNamed("cleanup", func(ctx context.Context) error { return nil })
+
+	tasks, err := a.GoSettled(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	statuses := []TaskStatus{StatusFailed, StatusSucceeded, StatusFailed, StatusSucceeded}
+	if len(tasks) != len(statuses) {
+		t.Fatalf("Expected %d reports, got %d", len(statuses), len(tasks))
+	}
+	for i, want := range statuses {
+		if tasks[i].Status != want {
+			t.Errorf("Expected task %q to be %v, got %v (%v)", tasks[i].Name, want, tasks[i].Status, tasks[i].Err)
+		}
+	}
+	if !errors.Is(tasks[0].Err, errRefund) {
+		t.Errorf("Expected the refund failure, got %v", tasks[0].Err)
+	}
+	var panicErr *PanicError
+	if !errors.As(tasks[2].Err, &panicErr) {
+		t.Errorf("Expected the audit panic, got %v", tasks[2].Err)
+	}
+
+	if _, err := a.GoSettled(context.Background()); !errors.Is(err, ErrBatchAlreadyRun) {
+		t.Errorf("Expected ErrBatchAlreadyRun, got %v", err)
+	}
+}
+
+func TestGoSettledCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tasks, err := NewAsyncRunner().RunInAsync().
+		Task(func(ctx context.Context) error { return nil }).
+		Task(func(ctx context.Context) error { return nil }).
+		GoSettled(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, r := range tasks {
+		if r.Status != StatusCanceled || !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("Expected task %d to be canceled, got %v (%v)", r.Index, r.Status, r.Err)
+		}
+	}
+}
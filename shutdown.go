@@ -0,0 +1,362 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"time"
+)
+
+// WithSignalHandling makes the runner Shutdown when the process receives one
+// of signals, such as syscall.SIGTERM and os.Interrupt, for CLI tools and
+// workers built directly on the runner. The drain is not bounded; wait on
+// ShuttingDown and call Shutdown with a deadline to bound it.
+func WithSignalHandling(signals ...os.Signal) RunnerOption {
+	return func(c *runnerConfig) {
+		c.signals = append(c.signals, signals...)
+	}
+}
+
+// DrainPolicy decides what happens to batches started once the runner is
+// shutting down.
+type DrainPolicy int
+
+const (
+	// DrainReject fails Go with ErrShuttingDown without running any task, so
+	// no work is started only to be killed halfway through.
+	DrainReject DrainPolicy = iota
+	// DrainAdmit runs the batch, which is only cancelled once the context of
+	// Shutdown is done. Shutdown waits for it unless it already moved on to
+	// shutting down the pools.
+	DrainAdmit
+)
+
+// WithDrainPolicy sets what happens to batches started once Shutdown has
+// begun. The default is DrainReject.
+func WithDrainPolicy(policy DrainPolicy) RunnerOption {
+	return func(c *runnerConfig) {
+		c.drainPolicy = policy
+	}
+}
+
+// WithShutdownTimeout bounds the OnShutdown hooks of phase to d, so a hook
+// stuck on a dead dependency doesn't eat the time left for the later phases.
+// Hooks still running once d elapsed are abandoned with a
+// context.DeadlineExceeded error.
+func WithShutdownTimeout(phase int, d time.Duration) RunnerOption {
+	return func(c *runnerConfig) {
+		if c.hookTimeouts == nil {
+			c.hookTimeouts = make(map[int]time.Duration)
+		}
+		c.hookTimeouts[phase] = d
+	}
+}
+
+// drain tracks the work of a runner that Shutdown cancels and waits for.
+type drain struct {
+	mu      sync.Mutex
+	closing bool
+	// expired is set once the context of Shutdown is done
+	expired bool
+	batches map[*async]runningBatch
+	// background maps background tasks to their registration sequence
+	background map[background]uint64
+	seq        uint64
+	// hooks holds the OnShutdown hooks, by phase
+	hooks map[int][]func(ctx context.Context) error
+	// idle holds the OnIdle watchers, timed by delays; idleGen changes
+	// whenever a batch starts on an idle runner, voiding the pending ones
+	idle    []*idleWatcher
+	idleGen uint64
+	delays  *delayQueue
+	// active counts the running batches; drained is closed once it drops
+	// to zero during Shutdown
+	active        int
+	drained       chan struct{}
+	drainedClosed bool
+
+	// closed is closed as soon as Shutdown starts
+	closed chan struct{}
+	// done is closed once the first Shutdown returns, with err its result
+	done chan struct{}
+	err  error
+}
+
+// runningBatch is a batch tracked by a drain.
+type runningBatch struct {
+	// cancel cancels the current phase of the batch, once it started
+	cancel context.CancelFunc
+	// admitted is set for batches started during Shutdown
+	admitted bool
+	// critical is set for batches marked Critical
+	critical bool
+}
+
+// spared reports whether Shutdown leaves the batch running until its context is done.
+func (b runningBatch) spared() bool {
+	return b.admitted || b.critical
+}
+
+func newDrain(delays *delayQueue) *drain {
+	return &drain{
+		delays:     delays,
+		batches:    make(map[*async]runningBatch),
+		background: make(map[background]uint64),
+		drained:    make(chan struct{}),
+		closed:     make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// addBatch tracks a batch about to run, failing with ErrShuttingDown if
+// Shutdown has begun and policy rejects new batches.
+func (d *drain) addBatch(a *async, policy DrainPolicy, critical bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closing && policy == DrainReject {
+		return ErrShuttingDown
+	}
+	d.batches[a] = runningBatch{admitted: d.closing, critical: critical}
+	d.active++
+	if d.active == 1 {
+		d.disarmIdle()
+	}
+	return nil
+}
+
+// setCancel records cancel as the way to cancel the current phase of a
+// batch, calling it right away if Shutdown cancelled the batch. Reusing the
+// phase's cancellation saves deriving one more context for every run.
+func (d *drain) setCancel(a *async, cancel context.CancelFunc) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	b := d.batches[a]
+	if d.closing && (d.expired || !b.spared()) {
+		cancel()
+		return
+	}
+	b.cancel = cancel
+	d.batches[a] = b
+}
+
+// removeBatch stops tracking a batch that finished running.
+func (d *drain) removeBatch(a *async) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.batches, a)
+	d.active--
+	if d.active == 0 {
+		d.armIdle()
+	}
+	d.checkDrained()
+}
+
+// checkDrained closes drained once Shutdown has begun and no batch is running.
+func (d *drain) checkDrained() {
+	if d.closing && d.active == 0 && !d.drainedClosed {
+		close(d.drained)
+		d.drainedClosed = true
+	}
+}
+
+// background is work a runner keeps running in the background, such as a
+// scheduled or supervised task.
+type background interface {
+	// halt cancels the work and returns a channel closed once it returned.
+	halt() <-chan struct{}
+	// snapshot returns the health of the work.
+	snapshot() BackgroundHealth
+}
+
+// addBackground tracks t until the returned function is called, halting it
+// right away if Shutdown has begun.
+func (d *drain) addBackground(t background) (untrack func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closing {
+		t.halt()
+		return nil
+	}
+	d.seq++
+	d.background[t] = d.seq
+	return func() {
+		d.mu.Lock()
+		delete(d.background, t)
+		d.mu.Unlock()
+	}
+}
+
+// OnShutdown registers fn to run in phase during Shutdown.
+func (a *asyncRunner) OnShutdown(phase int, fn func(ctx context.Context) error) error {
+	if fn == nil {
+		return fmt.Errorf("%w for shutdown phase %d", ErrNilTask, phase)
+	}
+	d := a.drain
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closing {
+		return ErrShuttingDown
+	}
+	if d.hooks == nil {
+		d.hooks = make(map[int][]func(ctx context.Context) error)
+	}
+	d.hooks[phase] = append(d.hooks[phase], fn)
+	return nil
+}
+
+// ShuttingDown returns a channel closed as soon as Shutdown starts.
+func (a *asyncRunner) ShuttingDown() <-chan struct{} {
+	return a.drain.closed
+}
+
+// Shutdown cancels the running batches and background tasks, waits for them,
+// shuts down the registered pools, then runs the shutdown hooks.
+func (a *asyncRunner) Shutdown(ctx context.Context) error {
+	d := a.drain
+	d.mu.Lock()
+	if d.closing {
+		d.mu.Unlock()
+		select {
+		case <-d.done:
+			return d.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	d.closing = true
+	close(d.closed)
+	d.disarmIdle()
+	// Best-effort batches are cancelled right away, leaving the remaining
+	// time to the critical ones
+	for _, b := range d.batches {
+		if b.cancel != nil && !b.spared() {
+			b.cancel()
+		}
+	}
+	d.checkDrained()
+	hooks := d.hooks
+	halted := make([]<-chan struct{}, 0, len(d.background))
+	for t := range d.background {
+		halted = append(halted, t.halt())
+	}
+	d.mu.Unlock()
+
+	stopped := make(chan struct{})
+	go func() {
+		<-d.drained
+		for _, done := range halted {
+			<-done
+		}
+		close(stopped)
+	}()
+
+	var errs []error
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		errs = append(errs, ctx.Err())
+		d.mu.Lock()
+		d.expired = true
+		for _, b := range d.batches {
+			if b.cancel != nil {
+				b.cancel()
+			}
+		}
+		d.mu.Unlock()
+	}
+
+	// Pools come last, since batches and scheduled tasks may feed them
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, pool := range a.cfg.pools {
+		wg.Go(func() {
+			if err := pool.Shutdown(ctx); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+
+	// Hooks come after the pools, since flushing buffers and closing
+	// clients must wait for the work using them
+	for _, phase := range slices.Sorted(maps.Keys(hooks)) {
+		errs = append(errs, a.runHooks(ctx, phase, hooks[phase])...)
+	}
+
+	d.err = errors.Join(errs...)
+	close(d.done)
+	return d.err
+}
+
+// runHooks runs the hooks of phase concurrently, waiting for them until ctx or
+// the phase timeout is done. Like the stop functions of a Lifecycle, hooks are
+// called even when ctx is done, so they still get to cancel their work.
+func (a *asyncRunner) runHooks(ctx context.Context, phase int, hooks []func(ctx context.Context) error) []error {
+	if d, ok := a.cfg.hookTimeouts[phase]; ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+
+	var (
+		mu   sync.Mutex
+		errs []error
+		wg   sync.WaitGroup
+	)
+	for _, hook := range hooks {
+		wg.Go(func() {
+			if err := stopComponent(ctx, hook); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("async: shutdown phase %d: %w", phase, err))
+				mu.Unlock()
+			}
+		})
+	}
+	finished := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+	case <-ctx.Done():
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	// Hooks still running are abandoned
+	select {
+	case <-finished:
+	default:
+		errs = append(errs, fmt.Errorf("async: shutdown phase %d: %w", phase, ctx.Err()))
+	}
+	return slices.Clone(errs)
+}
+
+// handleSignals calls Shutdown once the process receives one of signals.
+func (a *asyncRunner) handleSignals(signals []os.Signal) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+	go func() {
+		defer signal.Stop(ch)
+		select {
+		case <-ch:
+			_ = a.Shutdown(context.Background())
+		case <-a.drain.closed:
+		}
+	}()
+}
@@ -0,0 +1,248 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunnerShutdown(t *testing.T) {
+	pool := NewWorkerPool(1)
+	runner := NewAsyncRunner(WithPool("db", pool))
+
+	started := make(chan struct{})
+	result := make(chan error, 1)
+	go func() {
+		result <- runner.RunInAsync().
+			Task(func(ctx context.Context) error {
+				close(started)
+				<-ctx.Done()
+				return ctx.Err()
+			}).
+			Phase().
+			Task(func(ctx context.Context) error { return nil }).
+			Go(context.Background())
+	}()
+	<-started
+
+	ticks := make(chan struct{}, 1)
+	runner.Every(context.Background(), time.Millisecond, func(ctx context.Context) error {
+		select {
+		case ticks <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+	<-ticks
+
+	if err := runner.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected a clean shutdown, got %v", err)
+	}
+	if err := <-result; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the running batch to be cancelled, got %v", err)
+	}
+	select {
+	case <-runner.ShuttingDown():
+	default:
+		t.Error("Expected ShuttingDown to be closed")
+	}
+	if err := pool.Submit(context.Background(), func(ctx context.Context) error { return nil }); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Expected the pool to be shut down, got %v", err)
+	}
+
+	// Batches started afterwards are rejected
+	ran := false
+	a := runner.RunInAsync().Task(func(ctx context.Context) error {
+		ran = true
+		return nil
+	})
+	if err := a.Go(context.Background()); !errors.Is(err, ErrShuttingDown) || ran {
+		t.Errorf("Expected the late batch to be rejected, got %v", err)
+	}
+	if err := runner.Shutdown(context.Background()); err != nil {
+		t.Errorf("Expected a second Shutdown to return the first result, got %v", err)
+	}
+}
+
+func TestRunnerShutdownTimeout(t *testing.T) {
+	runner := NewAsyncRunner()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go runner.RunInAsync().
+		Task(func(ctx context.Context) error {
+			// Ignores its context
+			close(started)
+			<-release
+			return nil
+		}).
+		Go(context.Background())
+	<-started
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := runner.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline to be exceeded, got %v", err)
+	}
+}
+
+func TestRunnerShutdownAdmit(t *testing.T) {
+	runner := NewAsyncRunner(WithDrainPolicy(DrainAdmit))
+
+	// Keeps the drain waiting while the late batch starts
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go runner.RunInAsync().
+		Task(func(ctx context.Context) error {
+			close(started)
+			<-release
+			return nil
+		}).
+		Go(context.Background())
+	<-started
+
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- runner.Shutdown(context.Background())
+	}()
+	<-runner.ShuttingDown()
+
+	err := runner.RunInAsync().
+		Task(func(ctx context.Context) error {
+			close(release)
+			return ctx.Err()
+		}).
+		Go(context.Background())
+	if err != nil {
+		t.Errorf("Expected the late batch to run, got %v", err)
+	}
+	if err := <-shutdown; err != nil {
+		t.Errorf("Expected a clean shutdown, got %v", err)
+	}
+}
+
+func TestRunnerShutdownCritical(t *testing.T) {
+	runner := NewAsyncRunner()
+	blocked := func(started chan struct{}, release <-chan struct{}) AsyncFunc {
+		return func(ctx context.Context) error {
+			close(started)
+			select {
+			case <-release:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	release := make(chan struct{})
+	checkoutStarted, logsStarted := make(chan struct{}), make(chan struct{})
+	checkout, logs := make(chan error, 1), make(chan error, 1)
+	go func() {
+		checkout <- runner.RunInAsync().Critical().Task(blocked(checkoutStarted, release)).Go(context.Background())
+	}()
+	go func() {
+		logs <- runner.RunInAsync().Task(blocked(logsStarted, nil)).Go(context.Background())
+	}()
+	<-checkoutStarted
+	<-logsStarted
+
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- runner.Shutdown(context.Background())
+	}()
+	if err := <-logs; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the best-effort batch to be cancelled, got %v", err)
+	}
+	select {
+	case err := <-checkout:
+		t.Fatalf("Expected the critical batch to keep running, got %v", err)
+	default:
+	}
+
+	close(release)
+	if err := <-checkout; err != nil {
+		t.Errorf("Expected the critical batch to finish, got %v", err)
+	}
+	if err := <-shutdown; err != nil {
+		t.Errorf("Expected a clean shutdown, got %v", err)
+	}
+}
+
+func TestRunnerShutdownCriticalDeadline(t *testing.T) {
+	runner := NewAsyncRunner()
+	started := make(chan struct{})
+	result := make(chan error, 1)
+	go func() {
+		result <- runner.RunInAsync().Critical().
+			Task(func(ctx context.Context) error {
+				close(started)
+				<-ctx.Done()
+				return ctx.Err()
+			}).
+			Go(context.Background())
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := runner.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline to be exceeded, got %v", err)
+	}
+	if err := <-result; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the critical batch to be cancelled at the deadline, got %v", err)
+	}
+}
+
+func TestRunnerShutdownHooks(t *testing.T) {
+	errFlush := errors.New("flush failed")
+	pool := NewWorkerPool(1)
+	runner := NewAsyncRunner(WithPool("db", pool), WithShutdownTimeout(1, 10*time.Millisecond))
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	record := func(name string) {
+		mu.Lock()
+		order = append(order, name)
+		mu.Unlock()
+	}
+	release := make(chan struct{})
+	defer close(release)
+
+	_ = runner.OnShutdown(2, func(ctx context.Context) error {
+		record("close clients")
+		return nil
+	})
+	_ = runner.OnShutdown(0, func(ctx context.Context) error {
+		if err := pool.Submit(context.Background(), func(ctx context.Context) error { return nil }); !errors.Is(err, ErrPoolClosed) {
+			t.Errorf("Expected the pools to be shut down first, got %v", err)
+		}
+		record("stop consumers")
+		return nil
+	})
+	_ = runner.OnShutdown(1, func(ctx context.Context) error {
+		record("flush buffers")
+		return errFlush
+	})
+	_ = runner.OnShutdown(1, func(ctx context.Context) error {
+		// Ignores its context, stalling past the phase timeout
+		<-release
+		return nil
+	})
+
+	err := runner.Shutdown(context.Background())
+	if !errors.Is(err, errFlush) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the flush failure and the phase timeout, got %v", err)
+	}
+	if got := fmt.Sprint(order); got != "[stop consumers flush buffers close clients]" {
+		t.Errorf("Expected the hooks to run phase by phase, got %v", got)
+	}
+	if err := runner.OnShutdown(0, func(ctx context.Context) error { return nil }); !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("Expected ErrShuttingDown, got %v", err)
+	}
+}
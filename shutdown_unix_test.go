@@ -0,0 +1,22 @@
+//go:build unix
+
+package async
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestWithSignalHandling(t *testing.T) {
+	runner := NewAsyncRunner(WithSignalHandling(syscall.SIGUSR1))
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-runner.ShuttingDown():
+	case <-time.After(time.Second):
+		t.Fatal("Expected the signal to start the shutdown")
+	}
+}
@@ -0,0 +1,83 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrNoBatch is returned by Spawn when the context does not belong to a task of
+// a batch or graph that is still running.
+var ErrNoBatch = errors.New("async: no running batch to spawn into")
+
+type spawnerKey struct{}
+
+// spawner is implemented by executions that accept tasks while running.
+type spawner interface {
+	spawn(fn AsyncFunc) error
+}
+
+// Spawn adds fn to the batch or graph executing the task that owns ctx. The
+// batch waits for spawned tasks before returning, and their errors are handled
+// like those of any other task. Use Bind to capture a spawned task's result.
+func Spawn(ctx context.Context, fn AsyncFunc) error {
+	s, ok := ctx.Value(spawnerKey{}).(spawner)
+	if !ok {
+		return ErrNoBatch
+	}
+	return s.spawn(fn)
+}
+
+// taskGroup is the subset of errgroup.Group used to run spawned tasks.
+type taskGroup interface {
+	Go(fn func() error)
+	Wait() error
+}
+
+// groupSpawner adds spawned tasks to a running group.
+type groupSpawner struct {
+	g   taskGroup
+	ctx context.Context
+	run func(ctx context.Context, fn AsyncFunc) error
+	// admit, if set, can refuse a spawned task before it is scheduled
+	admit func() error
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// newGroupSpawner returns a spawner feeding g, along with ctx carrying it.
+func newGroupSpawner(ctx context.Context, g taskGroup, run func(ctx context.Context, fn AsyncFunc) error) (*groupSpawner, context.Context) {
+	s := &groupSpawner{g: g, run: run}
+	s.ctx = context.WithValue(ctx, spawnerKey{}, spawner(s))
+	return s, s.ctx
+}
+
+func (s *groupSpawner) spawn(fn AsyncFunc) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrNoBatch
+	}
+	if s.admit != nil {
+		if err := s.admit(); err != nil {
+			return err
+		}
+	}
+	s.g.Go(func() error {
+		return s.run(s.ctx, fn)
+	})
+	return nil
+}
+
+// wait waits for the group and rejects any later spawn attempt.
+func (s *groupSpawner) wait() error {
+	err := s.g.Wait()
+
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	return err
+}
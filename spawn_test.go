@@ -0,0 +1,89 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSpawnFromBatchTask(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	ids := []int{1, 2, 3}
+	results := make([]int, len(ids))
+
+	a := runner.RunInAsync().
+		Task(func(ctx context.Context) error {
+			// The sub-fetches are only known after the initial fetch
+			for i, id := range ids {
+				err := Spawn(ctx, Bind(&results[i], func(ctx context.Context) (int, error) {
+					return id * 10, nil
+				}))
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+	if err := a.Go(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i, id := range ids {
+		if results[i] != id*10 {
+			t.Errorf("Expected %d at index %d, got %d", id*10, i, results[i])
+		}
+	}
+
+	if n := len(a.Report().Tasks); n != 4 {
+		t.Errorf("Expected spawned tasks to appear in the report, got %d entries", n)
+	}
+}
+
+func TestSpawnErrorFailsBatch(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	err := runner.RunInAsync().
+		Task(func(ctx context.Context) error {
+			return Spawn(ctx, func(ctx context.Context) error {
+				return errors.New("spawned failed")
+			})
+		}).
+		Go(context.Background())
+
+	if err == nil || err.Error() != "spawned failed" {
+		t.Errorf("Expected 'spawned failed', got %v", err)
+	}
+}
+
+func TestSpawnFromGraphNode(t *testing.T) {
+	var spawned atomic.Bool
+
+	err := NewGraph().
+		Node("root", func(ctx context.Context) error {
+			return Spawn(ctx, func(ctx context.Context) error {
+				spawned.Store(true)
+				return nil
+			})
+		}).
+		Run(context.Background())
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !spawned.Load() {
+		t.Error("Expected spawned task to run before Run returned")
+	}
+}
+
+func TestSpawnOutsideBatch(t *testing.T) {
+	err := Spawn(context.Background(), func(ctx context.Context) error {
+		return nil
+	})
+
+	if !errors.Is(err, ErrNoBatch) {
+		t.Errorf("Expected ErrNoBatch, got %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+package async
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RestartMode decides when a supervised task is restarted.
+type RestartMode int
+
+const (
+	// RestartOnFailure restarts the task when it returns an error or panics,
+	// and lets it end when it returns nil.
+	RestartOnFailure RestartMode = iota
+	// RestartAlways restarts the task whenever it returns.
+	RestartAlways
+)
+
+// RestartPolicy configures how Supervise restarts a task.
+type RestartPolicy struct {
+	// Mode decides which returns lead to a restart.
+	Mode RestartMode
+	// Backoff is the delay before a restart. It doubles with every
+	// consecutive failure, up to MaxBackoff, and is reset by a successful run.
+	// Zero restarts the task right away.
+	Backoff time.Duration
+	// MaxBackoff caps the delay between restarts. Zero keeps it at Backoff.
+	MaxBackoff time.Duration
+	// MaxRestarts is the number of restarts after which the supervisor gives
+	// up. Zero means no limit.
+	MaxRestarts int
+}
+
+// SupervisedTask is a handle to a task kept running by Supervise.
+type SupervisedTask interface {
+	// Stop cancels the task, ending the supervision, and waits for it to return.
+	Stop()
+	// Stats returns a snapshot of the task's restarts and last outcome.
+	Stats() SupervisorStats
+	// Done returns a channel closed once the supervision ended, because the
+	// task was stopped, its context is done, it returned under
+	// RestartOnFailure, or it exhausted MaxRestarts.
+	Done() <-chan struct{}
+}
+
+// SupervisorStats is a point-in-time snapshot of a supervised task.
+type SupervisorStats struct {
+	// Running reports whether the task is running, rather than waiting to restart.
+	Running bool
+	// Restarts is the number of times the task was restarted.
+	Restarts int
+	// LastStart is when the task last started.
+	LastStart time.Time
+	// LastErr is the error of the last failed run, if any.
+	LastErr error
+	// LastFailure is when the task last failed.
+	LastFailure time.Time
+}
+
+// minGateRetry bounds how often a supervisor without backoff retries a Gate it
+// could not acquire.
+const minGateRetry = 100 * time.Millisecond
+
+// supervisor implements the SupervisedTask interface.
+type supervisor struct {
+	fn     AsyncFunc
+	policy RestartPolicy
+	cfg    *scheduleConfig
+	delays *delayQueue
+	clock  Clock
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu    sync.Mutex
+	stats SupervisorStats
+}
+
+// Supervise runs fn in the background and restarts it according to policy,
+// for long-lived work such as consumer loops and watchers. Panics count as
+// failures. Of the schedule options, only WithErrorHandler, which receives
+// the error of every failed run, WithGate, which is acquired before every
+// start, and WithName apply. The task is stopped when ctx is done, by Stop,
+// or by the runner's Shutdown.
+func (a *asyncRunner) Supervise(ctx context.Context, fn AsyncFunc, policy RestartPolicy, opts ...ScheduleOption) SupervisedTask {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &supervisor{
+		fn:     fn,
+		policy: policy,
+		cfg:    newScheduleConfig(opts),
+		delays: a.delays,
+		clock:  a.cfg.clock,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	untrack := a.drain.addBackground(s)
+	go func() {
+		defer close(s.done)
+		if untrack != nil {
+			defer untrack()
+		}
+		// Releases the context once the supervision ended on its own
+		defer cancel()
+		s.loop(ctx)
+	}()
+	return s
+}
+
+// loop runs the task until the policy stops restarting it.
+func (s *supervisor) loop(ctx context.Context) {
+	backoff := s.policy.Backoff
+	for {
+		if gate := s.cfg.gate; gate != nil {
+			if gate.Acquire(ctx) != nil {
+				if s.delays.sleep(ctx, max(s.policy.Backoff, minGateRetry)) != nil {
+					return
+				}
+				continue
+			}
+		}
+
+		s.mu.Lock()
+		s.stats.Running = true
+		s.stats.LastStart = s.clock.Now()
+		s.mu.Unlock()
+
+		err := safeCall(ctx, s.fn)
+		if s.cfg.gate != nil {
+			s.cfg.gate.Release()
+		}
+
+		s.mu.Lock()
+		s.stats.Running = false
+		if err != nil && ctx.Err() == nil {
+			s.stats.LastErr = err
+			s.stats.LastFailure = s.clock.Now()
+		}
+		restarts := s.stats.Restarts
+		s.mu.Unlock()
+
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil && s.cfg.onError != nil {
+			s.cfg.onError(err)
+		}
+		if err == nil {
+			if s.policy.Mode == RestartOnFailure {
+				return
+			}
+			backoff = s.policy.Backoff
+		}
+		if s.policy.MaxRestarts > 0 && restarts >= s.policy.MaxRestarts {
+			return
+		}
+
+		if s.delays.sleep(ctx, backoff) != nil {
+			return
+		}
+		if err != nil {
+			backoff = min(2*backoff, max(s.policy.MaxBackoff, s.policy.Backoff))
+		}
+
+		s.mu.Lock()
+		s.stats.Restarts++
+		s.mu.Unlock()
+	}
+}
+
+// halt cancels the task, returning a channel closed once the supervision ended.
+func (s *supervisor) halt() <-chan struct{} {
+	s.cancel()
+	return s.done
+}
+
+// Stop cancels the task and waits for the supervision to end.
+func (s *supervisor) Stop() {
+	<-s.halt()
+}
+
+// snapshot returns the health of the supervised task.
+func (s *supervisor) snapshot() BackgroundHealth {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h := BackgroundHealth{
+		Name:        s.cfg.name,
+		Kind:        KindSupervised,
+		Running:     s.stats.Running,
+		LastRun:     s.stats.LastStart,
+		LastErr:     s.stats.LastErr,
+		LastFailure: s.stats.LastFailure,
+		Restarts:    s.stats.Restarts,
+	}
+	if !s.stats.LastStart.IsZero() {
+		// Every restart is one more run after the first
+		h.Runs = s.stats.Restarts + 1
+	}
+	return h
+}
+
+// Stats returns a snapshot of the supervision.
+func (s *supervisor) Stats() SupervisorStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+// Done returns a channel closed once the supervision ended.
+func (s *supervisor) Done() <-chan struct{} {
+	return s.done
+}
@@ -0,0 +1,84 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSuperviseRestartsOnFailure(t *testing.T) {
+	errLost := errors.New("connection lost")
+	var runs, reported atomic.Int32
+	task := NewAsyncRunner().Supervise(context.Background(), func(ctx context.Context) error {
+		switch runs.Add(1) {
+		case 1:
+			return errLost
+		case 2:
+			panic("consumer crashed")
+		}
+		return nil
+	}, RestartPolicy{Backoff: time.Millisecond, MaxBackoff: 4 * time.Millisecond}, WithErrorHandler(func(err error) {
+		reported.Add(1)
+	}))
+
+	select {
+	case <-task.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected the supervision to end once the task succeeded")
+	}
+	stats := task.Stats()
+	if stats.Restarts != 2 || runs.Load() != 3 {
+		t.Errorf("Expected 2 restarts and 3 runs, got %d and %d", stats.Restarts, runs.Load())
+	}
+	var panicErr *PanicError
+	if !errors.As(stats.LastErr, &panicErr) {
+		t.Errorf("Expected the panic as the last error, got %v", stats.LastErr)
+	}
+	if reported.Load() != 2 {
+		t.Errorf("Expected both failures to be reported, got %d", reported.Load())
+	}
+}
+
+func TestSuperviseMaxRestarts(t *testing.T) {
+	var runs atomic.Int32
+	task := NewAsyncRunner().Supervise(context.Background(), func(ctx context.Context) error {
+		runs.Add(1)
+		return errors.New("down")
+	}, RestartPolicy{MaxRestarts: 3})
+
+	select {
+	case <-task.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected the supervisor to give up")
+	}
+	if n := task.Stats().Restarts; n != 3 || runs.Load() != 4 {
+		t.Errorf("Expected 3 restarts and 4 runs, got %d and %d", n, runs.Load())
+	}
+}
+
+func TestSuperviseAlwaysUntilShutdown(t *testing.T) {
+	runner := NewAsyncRunner()
+	restarted := make(chan struct{})
+	var runs atomic.Int32
+	task := runner.Supervise(context.Background(), func(ctx context.Context) error {
+		if runs.Add(1) == 3 {
+			close(restarted)
+		}
+		return nil
+	}, RestartPolicy{Mode: RestartAlways, Backoff: time.Millisecond})
+
+	<-restarted
+	if err := runner.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected a clean shutdown, got %v", err)
+	}
+	select {
+	case <-task.Done():
+	default:
+		t.Error("Expected Shutdown to stop the supervised task")
+	}
+	if task.Stats().LastErr != nil {
+		t.Errorf("Expected no failure, got %v", task.Stats().LastErr)
+	}
+}
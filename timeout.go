@@ -0,0 +1,126 @@
+package async
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TaskWithTimeout appends a function whose context expires after timeout.
+func (a *async) TaskWithTimeout(timeout time.Duration, fn AsyncFunc) Async {
+	if fn == nil {
+		return a.Task(nil)
+	}
+	return a.Task(withTaskTimeout(a.runner.delays, timeout, fn))
+}
+
+// withTaskTimeout bounds fn to timeout. The expiry is served by the delay
+// queue's single timer rather than a runtime timer per task, which keeps
+// batches of hundreds of tasks with their own timeout from churning timers.
+func withTaskTimeout(q *delayQueue, timeout time.Duration, fn AsyncFunc) AsyncFunc {
+	return func(ctx context.Context) error {
+		ctx, cancel := withDeadline(ctx, q, timeout)
+		defer cancel()
+		return fn(ctx)
+	}
+}
+
+// withDeadline is like context.WithTimeout, with the deadline enforced by the
+// delay queue and measured by its clock.
+func withDeadline(parent context.Context, q *delayQueue, timeout time.Duration) (context.Context, context.CancelFunc) {
+	c := &deadlineCtx{
+		Context:  parent,
+		deadline: q.clock.Now().Add(timeout),
+		done:     make(chan struct{}),
+	}
+	stop := context.AfterFunc(parent, func() {
+		c.cancel(parent.Err())
+	})
+	item := q.schedule(c.deadline, func() {
+		c.cancel(context.DeadlineExceeded)
+	})
+	return c, func() {
+		stop()
+		q.cancel(item)
+		c.cancel(context.Canceled)
+	}
+}
+
+// deadlineCtx is a context whose deadline is enforced by the delay queue. It
+// keeps its own done channel and error rather than wrapping a cancelable
+// context, so that contexts derived from it report context.DeadlineExceeded
+// too once the deadline has passed.
+type deadlineCtx struct {
+	context.Context
+	deadline time.Time
+	done     chan struct{}
+
+	mu     sync.Mutex
+	err    error
+	afters map[*afterFunc]struct{}
+}
+
+// afterFunc is a callback registered with deadlineCtx.AfterFunc.
+type afterFunc struct {
+	f func()
+}
+
+// cancel closes the context with err, unless it is already closed.
+func (c *deadlineCtx) cancel(err error) {
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return
+	}
+	c.err = err
+	close(c.done)
+	afters := c.afters
+	c.afters = nil
+	c.mu.Unlock()
+
+	for a := range afters {
+		go a.f()
+	}
+}
+
+func (c *deadlineCtx) Deadline() (time.Time, bool) {
+	if d, ok := c.Context.Deadline(); ok && d.Before(c.deadline) {
+		return d, true
+	}
+	return c.deadline, true
+}
+
+func (c *deadlineCtx) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *deadlineCtx) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// AfterFunc lets derived contexts be canceled with the deadline without a
+// goroutine each watching Done; the context package looks this method up.
+func (c *deadlineCtx) AfterFunc(f func()) (stop func() bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		go f()
+		return func() bool { return false }
+	}
+	a := &afterFunc{f: f}
+	if c.afters == nil {
+		c.afters = make(map[*afterFunc]struct{})
+	}
+	c.afters[a] = struct{}{}
+	return func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		_, ok := c.afters[a]
+		delete(c.afters, a)
+		return ok
+	}
+}
@@ -0,0 +1,82 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTaskWithTimeout(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	var fast int
+	var deadline time.Time
+	a := runner.RunInAsync()
+	for i := 0; i < 100; i++ {
+		a.TaskWithTimeout(time.Second, func(ctx context.Context) error {
+			return nil
+		})
+	}
+	a.TaskWithTimeout(time.Second, Bind(&fast, func(ctx context.Context) (int, error) {
+		deadline, _ = ctx.Deadline()
+		return 1, nil
+	}))
+	if err := a.Go(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if fast != 1 {
+		t.Errorf("Expected result 1, got %d", fast)
+	}
+	if until := time.Until(deadline); until <= 0 || until > time.Second {
+		t.Errorf("Expected the task deadline within a second, got %v", until)
+	}
+
+	start := time.Now()
+	err := runner.RunInAsync().
+		TaskWithTimeout(10*time.Millisecond, func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}).
+		Go(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the task to time out early, took %v", elapsed)
+	}
+}
+
+func TestDeadlineCtxParentDeadline(t *testing.T) {
+	parent, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	ctx, stop := withDeadline(parent, newDelayQueue(realClock{}), time.Hour)
+	defer stop()
+	want, _ := parent.Deadline()
+	if got, _ := ctx.Deadline(); !got.Equal(want) {
+		t.Errorf("Expected the earlier parent deadline %v, got %v", want, got)
+	}
+
+	<-ctx.Done()
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", ctx.Err())
+	}
+}
+
+func TestTaskWithTimeoutDerivedContext(t *testing.T) {
+	runner := NewAsyncRunner()
+
+	err := runner.RunInAsync().
+		TaskWithTimeout(10*time.Millisecond, func(ctx context.Context) error {
+			// Such as the context of an outgoing HTTP request
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			<-ctx.Done()
+			return ctx.Err()
+		}).
+		Go(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected derived contexts to report deadline exceeded, got %v", err)
+	}
+}
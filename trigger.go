@@ -0,0 +1,170 @@
+package async
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Trigger is a handle to a function whose executions are driven by explicit
+// Trigger calls instead of a clock.
+type Trigger interface {
+	// Trigger requests an execution of the underlying function.
+	Trigger()
+	// Stop discards pending executions and waits for running ones to return.
+	Stop()
+}
+
+// debouncer implements Trigger by coalescing bursts of calls.
+type debouncer struct {
+	task  *scheduledTask
+	ctx   context.Context
+	quiet time.Duration
+
+	mu      sync.Mutex
+	timer   *time.Timer
+	stopped bool
+
+	// runMu serializes executions so a slow run is never overlapped
+	runMu sync.Mutex
+	wg    sync.WaitGroup
+}
+
+// Debounced returns a Trigger that coalesces bursts of Trigger calls into a single
+// execution of fn, run once no further calls arrived for the quiet period.
+// Pending executions are dropped once ctx is done.
+func Debounced(ctx context.Context, fn AsyncFunc, quiet time.Duration, opts ...ScheduleOption) Trigger {
+	task, ctx := newScheduledTask(ctx, fn, opts)
+	return &debouncer{
+		task:  task,
+		ctx:   ctx,
+		quiet: quiet,
+	}
+}
+
+// Trigger (re)starts the quiet period.
+func (d *debouncer) Trigger() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stopped || d.ctx.Err() != nil {
+		return
+	}
+
+	if d.timer != nil && d.timer.Stop() {
+		// The previous trigger had not fired yet; its execution is superseded
+		d.wg.Done()
+	}
+	d.wg.Add(1)
+	d.timer = time.AfterFunc(d.quiet, d.fire)
+}
+
+// fire runs the debounced function once the quiet period has elapsed.
+func (d *debouncer) fire() {
+	defer d.wg.Done()
+
+	d.runMu.Lock()
+	defer d.runMu.Unlock()
+
+	if d.ctx.Err() != nil {
+		return
+	}
+	d.task.run(d.ctx)
+}
+
+// Stop cancels any pending execution and waits for a running one to return.
+func (d *debouncer) Stop() {
+	d.mu.Lock()
+	d.stopped = true
+	if d.timer != nil && d.timer.Stop() {
+		d.wg.Done()
+	}
+	d.mu.Unlock()
+
+	d.task.cancel()
+	d.wg.Wait()
+}
+
+// throttler implements Trigger by limiting executions to one per interval.
+type throttler struct {
+	task     *scheduledTask
+	ctx      context.Context
+	interval time.Duration
+
+	mu      sync.Mutex
+	last    time.Time
+	timer   *time.Timer
+	stopped bool
+
+	runMu sync.Mutex
+	wg    sync.WaitGroup
+}
+
+// Throttled returns a Trigger that runs fn at most once per minInterval no matter
+// how often it is triggered. Triggers arriving while throttled are dropped, unless
+// WithTrailing is set, in which case a single trailing execution is scheduled.
+func Throttled(ctx context.Context, fn AsyncFunc, minInterval time.Duration, opts ...ScheduleOption) Trigger {
+	task, ctx := newScheduledTask(ctx, fn, opts)
+	return &throttler{
+		task:     task,
+		ctx:      ctx,
+		interval: minInterval,
+	}
+}
+
+// Trigger runs fn immediately when allowed, or schedules a trailing run.
+func (t *throttler) Trigger() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.stopped || t.ctx.Err() != nil || t.timer != nil {
+		return
+	}
+
+	now := time.Now()
+	next := t.last.Add(t.interval)
+	if t.last.IsZero() || !now.Before(next) {
+		t.last = now
+		t.wg.Add(1)
+		go t.exec()
+		return
+	}
+
+	if t.task.cfg.trailing {
+		t.wg.Add(1)
+		t.timer = time.AfterFunc(next.Sub(now), func() {
+			t.mu.Lock()
+			t.timer = nil
+			t.last = time.Now()
+			t.mu.Unlock()
+			t.exec()
+		})
+	}
+}
+
+// exec runs the throttled function, never overlapping a previous execution.
+func (t *throttler) exec() {
+	defer t.wg.Done()
+
+	t.runMu.Lock()
+	defer t.runMu.Unlock()
+
+	if t.ctx.Err() != nil {
+		return
+	}
+	t.task.run(t.ctx)
+}
+
+// Stop cancels a pending trailing execution and waits for a running one to return.
+func (t *throttler) Stop() {
+	t.mu.Lock()
+	t.stopped = true
+	if t.timer != nil && t.timer.Stop() {
+		t.timer = nil
+		t.wg.Done()
+	}
+	t.mu.Unlock()
+
+	t.task.cancel()
+	t.wg.Wait()
+}
@@ -0,0 +1,102 @@
+package async
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDebounced(t *testing.T) {
+	var runs atomic.Int32
+
+	trigger := Debounced(context.Background(), func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}, 20*time.Millisecond)
+	defer trigger.Stop()
+
+	// A burst of triggers within the quiet period collapses into a single run
+	for i := 0; i < 5; i++ {
+		trigger.Trigger()
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if runs.Load() != 1 {
+		t.Errorf("Expected 1 run, got %d", runs.Load())
+	}
+
+	trigger.Trigger()
+	time.Sleep(50 * time.Millisecond)
+	if runs.Load() != 2 {
+		t.Errorf("Expected 2 runs, got %d", runs.Load())
+	}
+}
+
+func TestDebouncedStop(t *testing.T) {
+	var runs atomic.Int32
+
+	trigger := Debounced(context.Background(), func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}, 20*time.Millisecond)
+
+	trigger.Trigger()
+	trigger.Stop()
+	trigger.Trigger()
+
+	time.Sleep(40 * time.Millisecond)
+	if runs.Load() != 0 {
+		t.Errorf("Expected no runs after Stop, got %d", runs.Load())
+	}
+}
+
+func TestThrottled(t *testing.T) {
+	var runs atomic.Int32
+
+	trigger := Throttled(context.Background(), func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}, 50*time.Millisecond)
+	defer trigger.Stop()
+
+	for i := 0; i < 10; i++ {
+		trigger.Trigger()
+	}
+
+	time.Sleep(80 * time.Millisecond)
+	if runs.Load() != 1 {
+		t.Errorf("Expected 1 run, got %d", runs.Load())
+	}
+
+	trigger.Trigger()
+	time.Sleep(10 * time.Millisecond)
+	if runs.Load() != 2 {
+		t.Errorf("Expected 2 runs once the interval elapsed, got %d", runs.Load())
+	}
+}
+
+func TestThrottledTrailing(t *testing.T) {
+	var runs atomic.Int32
+
+	trigger := Throttled(context.Background(), func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}, 30*time.Millisecond, WithTrailing())
+	defer trigger.Stop()
+
+	for i := 0; i < 10; i++ {
+		trigger.Trigger()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if runs.Load() != 1 {
+		t.Errorf("Expected the leading run only, got %d", runs.Load())
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if runs.Load() != 2 {
+		t.Errorf("Expected a single trailing run, got %d", runs.Load())
+	}
+}
@@ -0,0 +1,125 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// WarmOption configures WarmCache.
+type WarmOption func(*warmConfig)
+
+type warmConfig struct {
+	ttl         time.Duration
+	concurrency int
+	retries     int
+	backoff     time.Duration
+	key         func(any) string
+}
+
+// WarmTTL stores the warmed results for ttl, which should match the ttl of
+// the TaskCached tasks reading them. By default they never expire.
+func WarmTTL(ttl time.Duration) WarmOption {
+	return func(c *warmConfig) {
+		c.ttl = ttl
+	}
+}
+
+// WarmConcurrency limits how many keys are loaded at the same time. By
+// default the runner's WithDefaultConcurrency applies.
+func WarmConcurrency(n int) WarmOption {
+	return func(c *warmConfig) {
+		c.concurrency = n
+	}
+}
+
+// WarmRetries retries the loading of a key up to n times after it failed,
+// waiting backoff before the first retry and twice as long before every
+// following one.
+func WarmRetries(n int, backoff time.Duration) WarmOption {
+	return func(c *warmConfig) {
+		c.retries = n
+		c.backoff = backoff
+	}
+}
+
+// WarmKey sets how keys map to cache keys, which must match the keys of the
+// TaskCached tasks reading them. By default keys are formatted with fmt.Sprint.
+func WarmKey[K any](fn func(key K) string) WarmOption {
+	return func(c *warmConfig) {
+		c.key = func(key any) string {
+			return fn(key.(K))
+		}
+	}
+}
+
+// WarmResult is the outcome of warming a single key.
+type WarmResult[K any] struct {
+	// Key is the warmed key.
+	Key K
+	// Attempts is the number of times the key was loaded.
+	Attempts int
+	// Err is the error of the last attempt, or the context error if the key
+	// was never loaded. It is nil if the key was stored.
+	Err error
+}
+
+// WarmCache loads every key with loader and stores the results in the cache
+// of runner, such as at startup, so the first requests hit a warm cache.
+// Keys are loaded by a batch of the runner, following its concurrency limit,
+// so runners wrapping another, such as test doubles, warm the cache of the
+// runner actually running the batch. Failed loads are retried according to
+// WarmRetries, and a key failing for good doesn't stop the others.
+// WarmCache returns one result per key, in key order; its error is only set
+// if the batch could not run, such as ErrNoCache for a runner without a cache.
+func WarmCache[K any, T any](ctx context.Context, runner AsyncRunner, keys []K, loader func(ctx context.Context, key K) (T, error), opts ...WarmOption) ([]WarmResult[K], error) {
+	cfg := warmConfig{key: func(key any) string { return fmt.Sprint(key) }}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	results := make([]WarmResult[K], len(keys))
+	a := runner.RunInAsyncN(len(keys))
+	if cfg.concurrency > 0 {
+		a.WithConcurrency(cfg.concurrency)
+	}
+	for i, key := range keys {
+		results[i].Key = key
+		cacheKey := cfg.key(key)
+		// Each task owns its result, so no locking is needed
+		a.TaskNamed(cacheKey, func(ctx context.Context) error {
+			// Found through the batch, so runners wrapping the runner work too
+			r := bindStateFrom(ctx).runnerOf()
+			if r == nil || r.cache == nil {
+				return ErrNoCache
+			}
+			backoff := cfg.backoff
+			for {
+				results[i].Attempts++
+				res, err := loader(ctx, key)
+				if err == nil {
+					return r.cache.cache.Set(ctx, cacheKey, CacheEntry{Value: res, Stored: r.cfg.clock.Now()}, cfg.ttl)
+				}
+				if results[i].Attempts > cfg.retries {
+					return err
+				}
+				if err := r.delays.sleep(ctx, backoff); err != nil {
+					return err
+				}
+				backoff *= 2
+			}
+		})
+	}
+
+	tasks, err := a.GoSettled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tasks {
+		if errors.Is(t.Err, ErrNoCache) {
+			return nil, ErrNoCache
+		}
+		results[t.Index].Err = t.Err
+	}
+	return results, nil
+}
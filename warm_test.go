@@ -0,0 +1,87 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWarmCache(t *testing.T) {
+	errFlaky := errors.New("flaky")
+	errDown := errors.New("down")
+	runner := NewAsyncRunner(WithCache(NewLRUCache(10)))
+
+	var flaky atomic.Int32
+	results, err := WarmCache(context.Background(), runner, []int{1, 2, 3}, func(ctx context.Context, id int) (string, error) {
+		switch id {
+		case 2:
+			if flaky.Add(1) < 3 {
+				return "", errFlaky
+			}
+		case 3:
+			return "", errDown
+		}
+		return fmt.Sprint("user-", id), nil
+	}, WarmRetries(2, time.Millisecond), WarmConcurrency(2), WarmKey(func(id int) string {
+		return fmt.Sprint("user:", id)
+	}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := []struct {
+		attempts int
+		err      error
+	}{{1, nil}, {3, nil}, {3, errDown}}
+	for i, w := range want {
+		r := results[i]
+		if r.Key != i+1 || r.Attempts != w.attempts || !errors.Is(r.Err, w.err) || (w.err == nil && r.Err != nil) {
+			t.Errorf("Expected key %d to take %d attempts with %v, got %+v", i+1, w.attempts, w.err, r)
+		}
+	}
+
+	// The warmed keys are hits for TaskCached
+	var user string
+	a := runner.RunInAsync()
+	TaskCached(a, "user:2", time.Minute, &user, func(ctx context.Context) (string, error) {
+		t.Error("Expected a cache hit")
+		return "", nil
+	})
+	if err := a.Go(context.Background()); err != nil || user != "user-2" {
+		t.Errorf("Expected the warmed result, got %q, %v", user, err)
+	}
+}
+
+func TestWarmCacheNoCache(t *testing.T) {
+	_, err := WarmCache(context.Background(), NewAsyncRunner(), []string{"a"}, func(ctx context.Context, key string) (int, error) {
+		return 1, nil
+	})
+	if !errors.Is(err, ErrNoCache) {
+		t.Errorf("Expected ErrNoCache, got %v", err)
+	}
+}
+
+// wrappedRunner stands for runners wrapping another, such as test doubles.
+type wrappedRunner struct {
+	AsyncRunner
+}
+
+func TestWarmCacheWrappedRunner(t *testing.T) {
+	runner := NewAsyncRunner(WithCache(NewLRUCache(10)))
+	results, err := WarmCache(context.Background(), wrappedRunner{runner}, []string{"a"}, func(ctx context.Context, key string) (int, error) {
+		return 1, nil
+	})
+	if err != nil || len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("Expected the key to be warmed, got %+v, %v", results, err)
+	}
+
+	var v int
+	if err := TaskCached(runner.RunInAsync(), "a", 0, &v, func(ctx context.Context) (int, error) {
+		return 2, nil
+	}).Go(context.Background()); err != nil || v != 1 {
+		t.Errorf("Expected the warmed value, got %d, %v", v, err)
+	}
+}
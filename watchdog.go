@@ -0,0 +1,79 @@
+package async
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// watchdog is the stall detection configured with WithWatchdog.
+type watchdog struct {
+	window  time.Duration
+	onStall func(Report)
+}
+
+// WithWatchdog reports the batch to onStall whenever no task has finished
+// within a rolling window of d, making stuck fan-outs diagnosable in production.
+func (a *async) WithWatchdog(d time.Duration, onStall func(Report)) Async {
+	a.build.Lock()
+	defer a.build.Unlock()
+
+	a.watchdog = &watchdog{window: d, onStall: onStall}
+	return a
+}
+
+// watch monitors the progress of the running batch until the returned
+// function is called. onStall is never called after stop returns.
+func (a *async) watch(w *watchdog) (stop func()) {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		clock := a.runner.cfg.clock
+		timer := clock.NewTimer(w.window)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-timer.C():
+			}
+
+			a.mu.Lock()
+			idle := clock.Now().Sub(a.progress)
+			a.mu.Unlock()
+
+			if idle < w.window {
+				// A task finished meanwhile, so the window restarts from it
+				timer.Reset(w.window - idle)
+				continue
+			}
+			w.onStall(a.stallReport())
+			timer.Reset(w.window)
+		}
+	}()
+
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+// stallReport returns the current report along with every goroutine's stack.
+func (a *async) stallReport() Report {
+	r := a.Report()
+
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			r.Stacks = buf[:n]
+			return r
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
@@ -0,0 +1,74 @@
+package async
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatchdog(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		stalls []Report
+	)
+
+	release := make(chan struct{})
+	time.AfterFunc(50*time.Millisecond, func() { close(release) })
+
+	err := NewAsyncRunner().RunInAsync().
+		WithWatchdog(20*time.Millisecond, func(r Report) {
+			mu.Lock()
+			stalls = append(stalls, r)
+			mu.Unlock()
+		}).
+		Task(func(ctx context.Context) error {
+			return nil
+		}).
+		Task(func(ctx context.Context) error {
+			<-release
+			return nil
+		}).
+		Go(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(stalls) == 0 {
+		t.Fatal("Expected the watchdog to report the stalled batch")
+	}
+	r := stalls[0]
+	if r.Tasks[0].Status != StatusSucceeded || r.Tasks[1].Status != StatusRunning {
+		t.Errorf("Expected the stalled task to be reported as running, got %+v", r.Tasks)
+	}
+	if !strings.Contains(string(r.Stacks), "TestWatchdog") {
+		t.Error("Expected the report to include goroutine stacks")
+	}
+}
+
+func TestWatchdogQuiet(t *testing.T) {
+	stalled := false
+
+	a := NewAsyncRunner().RunInAsync().
+		WithWatchdog(50*time.Millisecond, func(r Report) {
+			stalled = true
+		})
+	for i := 0; i < 5; i++ {
+		a.Task(func(ctx context.Context) error {
+			time.Sleep(time.Duration(i) * 5 * time.Millisecond)
+			return nil
+		})
+	}
+
+	if err := a.Go(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if stalled {
+		t.Error("Expected no stall report for a batch making progress")
+	}
+}
@@ -0,0 +1,103 @@
+package async
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// workerInitRetry is how long a worker waits before retrying a failed init.
+const workerInitRetry = time.Second
+
+// workerValueKey is the context key of the value of the worker running a task.
+type workerValueKey struct{}
+
+// WithWorkerInit runs fn whenever a worker starts, for instance to open a
+// connection or load a model. The returned value belongs to the worker: tasks
+// submitted with Submit, SubmitWait or SubmitKeyed read it with WorkerValue,
+// and it is closed when the worker exits if it implements io.Closer. A worker
+// whose init fails reports the error to the pool's error handler and retries.
+func WithWorkerInit(fn func(ctx context.Context) (any, error)) PoolOption {
+	return func(c *poolConfig) {
+		c.workerInit = fn
+	}
+}
+
+// defaultHealthInterval is how often workers are checked when WithHealthCheck
+// is given a non-positive interval.
+const defaultHealthInterval = 30 * time.Second
+
+// WithHealthCheck runs fn on every idle worker at the given interval with the
+// worker's value. A worker failing its check is closed and replaced by a new
+// one, which runs the init function again. A non-positive interval defaults
+// to 30 seconds.
+func WithHealthCheck(interval time.Duration, fn func(ctx context.Context, local any) error) PoolOption {
+	return func(c *poolConfig) {
+		c.healthInterval = interval
+		c.healthCheck = fn
+	}
+}
+
+// WorkerValue returns the value created by WithWorkerInit for the worker
+// running the task that owns ctx, or nil.
+func WorkerValue(ctx context.Context) any {
+	return ctx.Value(workerValueKey{})
+}
+
+// initWorker runs the init function until it succeeds. It reports false if the
+// pool stopped first.
+func (p *workerPool) initWorker() (any, bool) {
+	if p.cfg.workerInit == nil {
+		return nil, true
+	}
+
+	for {
+		local, err := p.cfg.workerInit(p.ctx)
+		if err == nil {
+			return local, true
+		}
+		p.report(err)
+
+		if p.stopped() || sleep(p.ctx, workerInitRetry) != nil {
+			return nil, false
+		}
+	}
+}
+
+// checkWorker runs the health check and reports whether the worker may keep
+// running. An unhealthy worker is replaced by a new one.
+func (p *workerPool) checkWorker(local any) bool {
+	err := safeCall(p.ctx, func(ctx context.Context) error {
+		return p.cfg.healthCheck(ctx, local)
+	})
+	if err == nil || p.ctx.Err() != nil {
+		return true
+	}
+	p.report(err)
+
+	// The exiting worker is still counted, so the group cannot be waited to zero
+	p.workers.Add(1)
+	go p.work()
+	return false
+}
+
+// stopped reports whether the pool no longer accepts tasks.
+func (p *workerPool) stopped() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.closed
+}
+
+// report hands err to the pool's error handler, if any.
+func (p *workerPool) report(err error) {
+	if p.cfg.onError != nil {
+		p.cfg.onError(err)
+	}
+}
+
+// closeWorker releases the value of an exiting worker.
+func closeWorker(local any) {
+	if c, ok := local.(io.Closer); ok {
+		c.Close()
+	}
+}
@@ -0,0 +1,122 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testConn struct {
+	id     int32
+	closed atomic.Bool
+}
+
+func (c *testConn) Close() error {
+	c.closed.Store(true)
+	return nil
+}
+
+func TestWorkerInit(t *testing.T) {
+	var (
+		ids   atomic.Int32
+		mu    sync.Mutex
+		conns []*testConn
+	)
+
+	pool := NewWorkerPool(2, WithWorkerInit(func(ctx context.Context) (any, error) {
+		c := &testConn{id: ids.Add(1)}
+		mu.Lock()
+		conns = append(conns, c)
+		mu.Unlock()
+		return c, nil
+	}))
+
+	for i := 0; i < 10; i++ {
+		err := pool.SubmitWait(context.Background(), func(ctx context.Context) error {
+			if _, ok := WorkerValue(ctx).(*testConn); !ok {
+				return errors.New("missing worker value")
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ids.Load() != 2 {
+		t.Errorf("Expected init to run once per worker, got %d", ids.Load())
+	}
+	for _, c := range conns {
+		if !c.closed.Load() {
+			t.Errorf("Expected connection %d to be closed on shutdown", c.id)
+		}
+	}
+}
+
+func TestWorkerHealthCheck(t *testing.T) {
+	var ids atomic.Int32
+	var errs atomic.Int32
+
+	pool := NewWorkerPool(1,
+		WithWorkerInit(func(ctx context.Context) (any, error) {
+			return &testConn{id: ids.Add(1)}, nil
+		}),
+		WithHealthCheck(5*time.Millisecond, func(ctx context.Context, local any) error {
+			if local.(*testConn).id == 1 {
+				return errors.New("unhealthy")
+			}
+			return nil
+		}),
+		WithPoolErrorHandler(func(err error) {
+			errs.Add(1)
+		}),
+	)
+	defer pool.Shutdown(context.Background())
+
+	deadline := time.Now().Add(time.Second)
+	for ids.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if ids.Load() != 2 {
+		t.Fatalf("Expected the unhealthy worker to be replaced, got %d inits", ids.Load())
+	}
+
+	var id int32
+	err := pool.SubmitWait(context.Background(), func(ctx context.Context) error {
+		id = WorkerValue(ctx).(*testConn).id
+		return nil
+	})
+	if err != nil || id != 2 {
+		t.Errorf("Expected the task to run on the replacement worker, got id %d and %v", id, err)
+	}
+	if errs.Load() != 1 {
+		t.Errorf("Expected the failed check to be reported once, got %d", errs.Load())
+	}
+	if workers := pool.Stats().Workers; workers != 1 {
+		t.Errorf("Expected the pool size to be unchanged, got %d", workers)
+	}
+}
+
+func TestWorkerHealthCheckInterval(t *testing.T) {
+	// A non-positive interval must not crash the workers
+	for _, interval := range []time.Duration{0, -time.Second} {
+		pool := NewWorkerPool(1, WithHealthCheck(interval, func(ctx context.Context, local any) error {
+			return nil
+		}))
+		if err := pool.SubmitWait(context.Background(), func(ctx context.Context) error {
+			return nil
+		}); err != nil {
+			t.Errorf("Expected no error with interval %v, got %v", interval, err)
+		}
+		if got := pool.(*workerPool).cfg.healthInterval; got != defaultHealthInterval {
+			t.Errorf("Expected the default interval for %v, got %v", interval, got)
+		}
+		pool.Shutdown(context.Background())
+	}
+}